@@ -0,0 +1,373 @@
+// Package astro computes sun and moon positions, rise and set times,
+// twilights and lunar illumination offline for any latitude, longitude and
+// date. The formulas are the low-precision ones from Astronomical Algorithms
+// (Meeus) as popularised by SunCalc. Sunrise and sunset come within about a
+// minute of NOAA's calculator at mid-latitudes and two near the polar
+// circles; moon times are good to a few minutes. That is plenty for a
+// weather app.
+package astro
+
+import (
+	"math"
+	"time"
+)
+
+const (
+	rad       = math.Pi / 180
+	j1970     = 2440588.0
+	j2000     = 2451545.0
+	obliquity = rad * 23.4397 // of the Earth's axis
+)
+
+// Position is a body's place in the sky, in degrees. Azimuth is measured
+// clockwise from north.
+type Position struct {
+	Altitude float64
+	Azimuth  float64
+}
+
+func toJulian(t time.Time) float64 {
+	return float64(t.UnixNano())/float64(24*time.Hour) - 0.5 + j1970
+}
+
+func fromJulian(j float64, loc *time.Location) time.Time {
+	ns := (j + 0.5 - j1970) * float64(24*time.Hour)
+	return time.Unix(0, int64(ns)).In(loc)
+}
+
+func toDays(t time.Time) float64 { return toJulian(t) - j2000 }
+
+func rightAscension(l, b float64) float64 {
+	return math.Atan2(math.Sin(l)*math.Cos(obliquity)-math.Tan(b)*math.Sin(obliquity), math.Cos(l))
+}
+
+func declination(l, b float64) float64 {
+	return math.Asin(math.Sin(b)*math.Cos(obliquity) + math.Cos(b)*math.Sin(obliquity)*math.Sin(l))
+}
+
+// azimuth is measured from south, as in the textbook formula.
+func azimuth(h, phi, dec float64) float64 {
+	return math.Atan2(math.Sin(h), math.Cos(h)*math.Sin(phi)-math.Tan(dec)*math.Cos(phi))
+}
+
+func altitude(h, phi, dec float64) float64 {
+	return math.Asin(math.Sin(phi)*math.Sin(dec) + math.Cos(phi)*math.Cos(dec)*math.Cos(h))
+}
+
+func siderealTime(d, lw float64) float64 {
+	return rad*(280.16+360.9856235*d) - lw
+}
+
+// refraction lifts an altitude (radians) by the atmosphere's bending of light.
+func refraction(h float64) float64 {
+	if h < 0 {
+		h = 0
+	}
+	return 0.0002967 / math.Tan(h+0.00312536/(h+0.08901179))
+}
+
+func toPosition(alt, az float64) Position {
+	deg := math.Mod(az/rad+180, 360) // south-based to north-based
+	if deg < 0 {
+		deg += 360
+	}
+	return Position{Altitude: alt / rad, Azimuth: deg}
+}
+
+// --- sun ---
+
+func solarMeanAnomaly(d float64) float64 { return rad * (357.5291 + 0.98560028*d) }
+
+func eclipticLongitude(m float64) float64 {
+	c := rad * (1.9148*math.Sin(m) + 0.02*math.Sin(2*m) + 0.0003*math.Sin(3*m))
+	perihelion := rad * 102.9372
+	return m + c + perihelion + math.Pi
+}
+
+func sunCoords(d float64) (dec, ra float64) {
+	l := eclipticLongitude(solarMeanAnomaly(d))
+	return declination(l, 0), rightAscension(l, 0)
+}
+
+// SunPosition returns the sun's altitude and azimuth at t.
+func SunPosition(t time.Time, lat, lon float64) Position {
+	lw, phi, d := -lon*rad, lat*rad, toDays(t)
+	dec, ra := sunCoords(d)
+	h := siderealTime(d, lw) - ra
+	return toPosition(altitude(h, phi, dec), azimuth(h, phi, dec))
+}
+
+// Sun altitudes that define the day's events, in degrees.
+const (
+	altSunrise      = -0.833 // upper limb on the horizon, with refraction
+	altCivil        = -6
+	altNautical     = -12
+	altAstronomical = -18
+	altBlueHourEnd  = -4 // blue hour is -6° to -4°, golden hour -4° to 6°
+	altGoldenHour   = 6
+)
+
+// Span is a time range; both ends are zero when it does not occur.
+type Span struct {
+	From, To time.Time
+}
+
+// IsZero reports whether the span does not occur.
+func (s Span) IsZero() bool { return s.From.IsZero() && s.To.IsZero() }
+
+// String is "07:12–07:58", or "—" when the span does not occur.
+func (s Span) String() string {
+	if s.IsZero() {
+		return "—"
+	}
+	return Clock(s.From) + "–" + Clock(s.To)
+}
+
+// Clock formats t as "15:04", or "—" for the zero time (an event that does
+// not happen that day).
+func Clock(t time.Time) string {
+	if t.IsZero() {
+		return "—"
+	}
+	return t.Format("15:04")
+}
+
+// SunTimes are one day's solar events, in the location of the day passed to
+// Sun. An event that does not happen (no astronomical night at midsummer in
+// Scotland, no sunrise in a polar night) is the zero time.
+type SunTimes struct {
+	SolarNoon                  time.Time
+	Sunrise, Sunset            time.Time
+	CivilDawn, CivilDusk       time.Time
+	NauticalDawn, NauticalDusk time.Time
+	AstroDawn, AstroDusk       time.Time
+
+	GoldenMorning, GoldenEvening Span // sun between -4° and 6°
+	BlueMorning, BlueEvening     Span // sun between -6° and -4°
+
+	PolarDay   bool // the sun never sets
+	PolarNight bool // the sun never rises
+}
+
+// Daylight is the time between sunrise and sunset: 24 h in a polar day and
+// 0 in a polar night.
+func (s SunTimes) Daylight() time.Duration {
+	switch {
+	case s.PolarDay:
+		return 24 * time.Hour
+	case s.Sunrise.IsZero() || s.Sunset.IsZero():
+		return 0
+	}
+	return s.Sunset.Sub(s.Sunrise)
+}
+
+// Sun computes the solar events for the calendar day of day, in day's
+// location.
+func Sun(day time.Time, lat, lon float64) SunTimes {
+	loc := day.Location()
+	noonLocal := time.Date(day.Year(), day.Month(), day.Day(), 12, 0, 0, 0, loc)
+
+	lw, phi := -lon*rad, lat*rad
+	n := math.Round(toDays(noonLocal) - lw/(2*math.Pi))
+
+	// transit is the Julian date of the sun's hour angle w (radians, 0 at
+	// noon) on day n, corrected by the equation of time at that moment.
+	transit := func(w float64) float64 {
+		ds := n + (w+lw)/(2*math.Pi)
+		m := solarMeanAnomaly(ds)
+		l := eclipticLongitude(m)
+		return j2000 + ds + 0.0053*math.Sin(m) - 0.0069*math.Sin(2*l)
+	}
+	jNoon := transit(0)
+	noonDec, _ := sunCoords(jNoon - j2000)
+
+	// hourAngle is the hour angle at which the sun crosses h (degrees) with
+	// declination dec, and the cosine it came from: outside [-1, 1] the sun
+	// never crosses h.
+	hourAngle := func(h, dec float64) (w, x float64) {
+		x = (math.Sin(h*rad) - math.Sin(phi)*math.Sin(dec)) / (math.Cos(phi) * math.Cos(dec))
+		return math.Acos(math.Max(-1, math.Min(1, x))), x
+	}
+
+	// event returns the rising and setting times for altitude h (degrees).
+	// above reports that the sun stays above h all day, below that it never
+	// reaches it; either way the times are zero. Each time is refined with
+	// the declination at that time rather than at noon, which moves by up
+	// to a few minutes around the equinoxes.
+	event := func(h float64) (rise, set time.Time, above, below bool) {
+		w, x := hourAngle(h, noonDec)
+		switch {
+		case x < -1:
+			return time.Time{}, time.Time{}, true, false
+		case x > 1:
+			return time.Time{}, time.Time{}, false, true
+		}
+		var j [2]float64
+		for i, sign := range []float64{-1, 1} {
+			j[i] = transit(sign * w)
+			for range 2 {
+				dec, _ := sunCoords(j[i] - j2000)
+				wi, _ := hourAngle(h, dec)
+				j[i] = transit(sign * wi)
+			}
+		}
+		return fromJulian(j[0], loc), fromJulian(j[1], loc), false, false
+	}
+
+	st := SunTimes{SolarNoon: fromJulian(jNoon, loc)}
+	var above, below bool
+	st.Sunrise, st.Sunset, above, below = event(altSunrise)
+	st.PolarDay, st.PolarNight = above, below
+	st.CivilDawn, st.CivilDusk, _, _ = event(altCivil)
+	st.NauticalDawn, st.NauticalDusk, _, _ = event(altNautical)
+	st.AstroDawn, st.AstroDusk, _, _ = event(altAstronomical)
+
+	blueRise, blueSet, _, _ := event(altBlueHourEnd)
+	goldRise, goldSet, goldAbove, _ := event(altGoldenHour)
+	if !blueRise.IsZero() {
+		// When the sun never climbs past 6° the golden hour lasts until
+		// noon and resumes after it.
+		if goldRise.IsZero() && !goldAbove {
+			goldRise, goldSet = st.SolarNoon, st.SolarNoon
+		}
+		if !goldRise.IsZero() {
+			st.GoldenMorning = Span{blueRise, goldRise}
+			st.GoldenEvening = Span{goldSet, blueSet}
+		}
+		if !st.CivilDawn.IsZero() {
+			st.BlueMorning = Span{st.CivilDawn, blueRise}
+			st.BlueEvening = Span{blueSet, st.CivilDusk}
+		}
+	}
+	return st
+}
+
+// --- moon ---
+
+// moonCoords returns the moon's declination, right ascension and distance
+// (km).
+func moonCoords(d float64) (dec, ra, dist float64) {
+	l := rad * (218.316 + 13.176396*d) // ecliptic longitude
+	m := rad * (134.963 + 13.064993*d) // mean anomaly
+	f := rad * (93.272 + 13.229350*d)  // mean distance
+
+	lon := l + rad*6.289*math.Sin(m)
+	lat := rad * 5.128 * math.Sin(f)
+	dist = 385001 - 20905*math.Cos(m)
+	return declination(lon, lat), rightAscension(lon, lat), dist
+}
+
+// MoonPosition returns the moon's altitude (with refraction) and azimuth.
+func MoonPosition(t time.Time, lat, lon float64) Position {
+	lw, phi, d := -lon*rad, lat*rad, toDays(t)
+	dec, ra, _ := moonCoords(d)
+	h := siderealTime(d, lw) - ra
+	alt := altitude(h, phi, dec)
+	return toPosition(alt+refraction(alt), azimuth(h, phi, dec))
+}
+
+// Illumination describes the moon's lit disc.
+type Illumination struct {
+	Fraction float64 // 0-1 of the disc lit
+	Phase    float64 // 0 new, 0.25 first quarter, 0.5 full, 0.75 last quarter
+}
+
+// MoonIllumination returns the illuminated fraction and phase at t.
+func MoonIllumination(t time.Time) Illumination {
+	const sunDist = 149598000.0 // km
+	d := toDays(t)
+	sDec, sRA := sunCoords(d)
+	mDec, mRA, mDist := moonCoords(d)
+
+	elong := math.Acos(math.Sin(sDec)*math.Sin(mDec) + math.Cos(sDec)*math.Cos(mDec)*math.Cos(sRA-mRA))
+	inc := math.Atan2(sunDist*math.Sin(elong), mDist-sunDist*math.Cos(elong))
+	angle := math.Atan2(math.Cos(sDec)*math.Sin(sRA-mRA),
+		math.Sin(sDec)*math.Cos(mDec)-math.Cos(sDec)*math.Sin(mDec)*math.Cos(sRA-mRA))
+
+	sign := 1.0
+	if angle < 0 {
+		sign = -1
+	}
+	return Illumination{
+		Fraction: (1 + math.Cos(inc)) / 2,
+		Phase:    0.5 + 0.5*inc*sign/math.Pi,
+	}
+}
+
+// MoonTimes are the moon's rise and set on one calendar day. Either may be
+// zero: the moon rises about 50 minutes later each day, so some days have
+// no moonrise or no moonset.
+type MoonTimes struct {
+	Rise, Set  time.Time
+	AlwaysUp   bool // above the horizon all day
+	AlwaysDown bool // below the horizon all day
+}
+
+// Moon finds moonrise and moonset for the calendar day of day by fitting a
+// parabola through the moon's altitude every hour, in day's location.
+func Moon(day time.Time, lat, lon float64) MoonTimes {
+	loc := day.Location()
+	start := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, loc)
+	const hc = 0.133 // degrees: the moon's mean semi-diameter and parallax
+	alt := func(hours float64) float64 {
+		return MoonPosition(start.Add(time.Duration(hours*float64(time.Hour))), lat, lon).Altitude - hc
+	}
+
+	var mt MoonTimes
+	var rise, set float64 = -1, -1
+	var ye float64
+	h0 := alt(0)
+	for i := 1.0; i <= 24; i += 2 {
+		h1, h2 := alt(i), alt(i+1)
+		a := (h0+h2)/2 - h1
+		b := (h2 - h0) / 2
+		xe := -b / (2 * a)
+		ye = (a*xe+b)*xe + h1
+		disc := b*b - 4*a*h1
+		roots := 0
+		var x1, x2 float64
+		if disc >= 0 {
+			dx := math.Sqrt(disc) / (math.Abs(a) * 2)
+			x1, x2 = xe-dx, xe+dx
+			if math.Abs(x1) <= 1 {
+				roots++
+			}
+			if math.Abs(x2) <= 1 {
+				roots++
+			}
+			if x1 < -1 {
+				x1 = x2
+			}
+		}
+		switch roots {
+		case 1:
+			if h0 < 0 {
+				rise = i + x1
+			} else {
+				set = i + x1
+			}
+		case 2:
+			if ye < 0 {
+				rise, set = i+x2, i+x1
+			} else {
+				rise, set = i+x1, i+x2
+			}
+		}
+		if rise >= 0 && set >= 0 {
+			break
+		}
+		h0 = h2
+	}
+
+	at := func(hours float64) time.Time { return start.Add(time.Duration(hours * float64(time.Hour))) }
+	if rise >= 0 {
+		mt.Rise = at(rise)
+	}
+	if set >= 0 {
+		mt.Set = at(set)
+	}
+	if rise < 0 && set < 0 {
+		mt.AlwaysUp, mt.AlwaysDown = ye > 0, ye <= 0
+	}
+	return mt
+}
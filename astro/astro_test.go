@@ -0,0 +1,119 @@
+package astro
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func near(t *testing.T, name string, got, want time.Time, tol time.Duration) {
+	t.Helper()
+	if d := got.Sub(want); d < -tol || d > tol {
+		t.Errorf("%s = %s, want %s ± %s", name, got.Format(time.RFC3339), want.Format(time.RFC3339), tol)
+	}
+}
+
+func TestSunLondon(t *testing.T) {
+	london, err := time.LoadLocation("Europe/London")
+	if err != nil {
+		t.Skip("no tzdata:", err)
+	}
+	// Reference times from NOAA's solar calculator.
+	tests := []struct {
+		day             time.Time
+		sunrise, sunset time.Time
+	}{
+		{
+			day:     time.Date(2024, 10, 15, 0, 0, 0, 0, london),
+			sunrise: time.Date(2024, 10, 15, 7, 25, 10, 0, london),
+			sunset:  time.Date(2024, 10, 15, 18, 6, 18, 0, london),
+		},
+		{
+			day:     time.Date(2024, 6, 21, 0, 0, 0, 0, london),
+			sunrise: time.Date(2024, 6, 21, 4, 43, 11, 0, london),
+			sunset:  time.Date(2024, 6, 21, 21, 21, 40, 0, london),
+		},
+	}
+	for _, tt := range tests {
+		st := Sun(tt.day, 51.5074, -0.1278)
+		near(t, "sunrise", st.Sunrise, tt.sunrise, 90*time.Second)
+		near(t, "sunset", st.Sunset, tt.sunset, 90*time.Second)
+		if st.Sunrise.Location() != london {
+			t.Errorf("sunrise in %s, want %s", st.Sunrise.Location(), london)
+		}
+		if st.PolarDay || st.PolarNight {
+			t.Errorf("%s: polar day %v, night %v", tt.day.Format("2006-01-02"), st.PolarDay, st.PolarNight)
+		}
+		if !(st.AstroDawn.IsZero() || st.AstroDawn.Before(st.NauticalDawn)) || !st.NauticalDawn.Before(st.CivilDawn) || !st.CivilDawn.Before(st.Sunrise) {
+			t.Errorf("%s: dawns out of order: %s %s %s", tt.day.Format("2006-01-02"), Clock(st.AstroDawn), Clock(st.NauticalDawn), Clock(st.CivilDawn))
+		}
+	}
+}
+
+func TestSunPolar(t *testing.T) {
+	const lat, lon = 69.65, 18.96 // Tromsø
+
+	summer := Sun(time.Date(2024, 6, 21, 0, 0, 0, 0, time.UTC), lat, lon)
+	if !summer.PolarDay || summer.PolarNight {
+		t.Errorf("midsummer: polar day %v, night %v; want day", summer.PolarDay, summer.PolarNight)
+	}
+	if !summer.Sunrise.IsZero() || !summer.Sunset.IsZero() {
+		t.Errorf("midsummer: sunrise %s, sunset %s; want none", Clock(summer.Sunrise), Clock(summer.Sunset))
+	}
+	if summer.Daylight() != 24*time.Hour {
+		t.Errorf("midsummer daylight = %s, want 24h", summer.Daylight())
+	}
+
+	winter := Sun(time.Date(2024, 12, 21, 0, 0, 0, 0, time.UTC), lat, lon)
+	if !winter.PolarNight || winter.PolarDay {
+		t.Errorf("midwinter: polar day %v, night %v; want night", winter.PolarDay, winter.PolarNight)
+	}
+	if winter.Daylight() != 0 {
+		t.Errorf("midwinter daylight = %s, want 0", winter.Daylight())
+	}
+	if winter.CivilDawn.IsZero() {
+		t.Error("midwinter: no civil dawn, want one (the sun reaches -6° at noon)")
+	}
+}
+
+func TestMoonIllumination(t *testing.T) {
+	// Phases of January 2024, UTC.
+	tests := []struct {
+		name     string
+		at       time.Time
+		fraction float64
+		phase    float64
+	}{
+		{"new", time.Date(2024, 1, 11, 11, 57, 0, 0, time.UTC), 0, 0},
+		{"first quarter", time.Date(2024, 1, 18, 3, 53, 0, 0, time.UTC), 0.5, 0.25},
+		{"full", time.Date(2024, 1, 25, 17, 54, 0, 0, time.UTC), 1, 0.5},
+		{"last quarter", time.Date(2024, 2, 2, 23, 18, 0, 0, time.UTC), 0.5, 0.75},
+	}
+	for _, tt := range tests {
+		il := MoonIllumination(tt.at)
+		if math.Abs(il.Fraction-tt.fraction) > 0.03 {
+			t.Errorf("%s: fraction = %.3f, want %.2f", tt.name, il.Fraction, tt.fraction)
+		}
+		phase := math.Abs(il.Phase - tt.phase)
+		if phase > 0.5 {
+			phase = 1 - phase // new moon wraps around 0/1
+		}
+		if phase > 0.02 {
+			t.Errorf("%s: phase = %.3f, want %.2f", tt.name, il.Phase, tt.phase)
+		}
+	}
+}
+
+func TestMoonTimes(t *testing.T) {
+	// Near full moon the moon rises around sunset and sets around sunrise.
+	mt := Moon(time.Date(2024, 1, 25, 0, 0, 0, 0, time.UTC), 51.5074, -0.1278)
+	if mt.Rise.IsZero() || mt.Set.IsZero() {
+		t.Fatalf("rise %s, set %s; want both", Clock(mt.Rise), Clock(mt.Set))
+	}
+	if h := mt.Rise.Hour(); h < 14 || h > 18 {
+		t.Errorf("moonrise = %s, want late afternoon", Clock(mt.Rise))
+	}
+	if h := mt.Set.Hour(); h < 6 || h > 10 {
+		t.Errorf("moonset = %s, want morning", Clock(mt.Set))
+	}
+}
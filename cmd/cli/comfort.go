@@ -0,0 +1,117 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"WeatherApp/weather"
+)
+
+// comfortPath is $WEATHER_COMFORT_FILE or the default XDG location.
+func comfortPath() string {
+	if path := os.Getenv("WEATHER_COMFORT_FILE"); path != "" {
+		return path
+	}
+	return weather.DefaultComfortPath()
+}
+
+// runComfort implements `weather-cli comfort [cold|ok|hot]`: record how
+// today's advice felt, adjust the profile by hand, or show it.
+func runComfort(args []string) {
+	fs := flag.NewFlagSet("comfort", flag.ExitOnError)
+	offset := fs.Float64("offset", math.NaN(), "Set the offset in °C directly, -6 to +6 (+2 = you feel 2 °C colder than average)")
+	prefer := fs.String("prefer", "", "Rename items by icon key, e.g. jacket=Hoodie,umbrella=Compact umbrella")
+	avoid := fs.String("avoid", "", "Never suggest these items, e.g. umbrella,sandals (\"-\" clears)")
+	resetFlag := fs.Bool("reset", false, "Forget the profile and all feedback")
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: weather-cli comfort [flags] [cold|ok|hot]")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	fail := func(err error) {
+		fmt.Fprintf(os.Stderr, "\n  %sError:%s %v\n\n", red+bold, reset, err)
+		os.Exit(1)
+	}
+
+	path := comfortPath()
+	profile, err := weather.LoadComfortProfile(path)
+	if err != nil {
+		fail(err)
+	}
+	changed := false
+	if *resetFlag {
+		profile, changed = &weather.ComfortProfile{}, true
+	}
+	if !math.IsNaN(*offset) {
+		if err := profile.SetOffset(*offset); err != nil {
+			fail(fmt.Errorf("-%v", err))
+		}
+		changed = true
+	}
+	if *prefer != "" {
+		if err := profile.SetPrefer(*prefer); err != nil {
+			fail(fmt.Errorf("-%v", err))
+		}
+		changed = true
+	}
+	if *avoid != "" {
+		profile.SetAvoid(*avoid)
+		changed = true
+	}
+	if fs.NArg() > 0 {
+		feel, err := weather.ParseFeel(strings.Join(fs.Args(), "-"))
+		if err != nil {
+			fail(err)
+		}
+		profile.AddFeedback(feel, time.Now())
+		changed = true
+	}
+	if changed {
+		if err := profile.Save(path); err != nil {
+			fail(err)
+		}
+	}
+
+	fmt.Println()
+	fmt.Println(topBar("Comfort Profile"))
+	fmt.Println(row(fmt.Sprintf("%s %s  %s",
+		clr(dim+cyan, "Calibration"),
+		clr(bold+white, profile.Summary()),
+		clr(dim, fmt.Sprintf("(offset %+.1f °C)", profile.OffsetC)),
+	)))
+	if len(profile.Prefer) > 0 {
+		var pairs []string
+		for icon, label := range profile.Prefer {
+			pairs = append(pairs, icon+" → "+label)
+		}
+		sort.Strings(pairs)
+		fmt.Println(row(clr(dim+cyan, "Prefer     ") + " " + strings.Join(pairs, ", ")))
+	}
+	if len(profile.Avoid) > 0 {
+		fmt.Println(row(clr(dim+cyan, "Avoid      ") + " " + strings.Join(profile.Avoid, ", ")))
+	}
+	if n := len(profile.Feedback); n > 0 {
+		recent := profile.Feedback[max(0, n-7):]
+		var marks []string
+		for _, f := range recent {
+			c := green
+			switch f.Feel {
+			case weather.FeltCold:
+				c = blue
+			case weather.FeltHot:
+				c = red
+			}
+			marks = append(marks, clr(c, f.Date[5:]+" "+string(f.Feel)))
+		}
+		fmt.Println(row(clr(dim+cyan, "Recent     ") + " " + strings.Join(marks, "  ")))
+	}
+	fmt.Println(row(clr(dim, "Tell it how today felt: weather-cli comfort cold | ok | hot")))
+	fmt.Println(botBar())
+	fmt.Println()
+}
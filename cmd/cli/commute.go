@@ -0,0 +1,100 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"WeatherApp/weather"
+)
+
+// configDir is where the CLI keeps what it remembers between runs:
+// $XDG_CONFIG_HOME/weather, falling back to ~/.config.
+func configDir() string {
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ".weather"
+		}
+		dir = filepath.Join(home, ".config")
+	}
+	return filepath.Join(dir, "weather")
+}
+
+// commutePath is where -save-commute keeps the windows between runs.
+func commutePath() string { return filepath.Join(configDir(), "commute") }
+
+// loadCommute returns the saved windows, or none if nothing was saved.
+func loadCommute() ([]weather.TimeWindow, error) {
+	b, err := os.ReadFile(commutePath())
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return weather.ParseWindows(strings.TrimSpace(string(b)))
+}
+
+// saveCommute stores windows for later runs; no windows removes the file.
+func saveCommute(windows []weather.TimeWindow) error {
+	path := commutePath()
+	if len(windows) == 0 {
+		if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(weather.FormatWindows(windows)+"\n"), 0o644)
+}
+
+// printCommute renders one box per window with the headline, an hourly strip
+// of feels-like temperatures and rain chances, and the items to bring.
+func printCommute(info *weather.WeatherInfo, commutes []weather.CommuteOutfit) {
+	for _, c := range commutes {
+		fmt.Println(topBar(tr("Commute") + " · " + tr(c.Day) + " " + c.Window.Label()))
+		fmt.Println(row(clr(dim+cyan, c.Outfit.Headline)))
+		if len(c.Outfit.Timeline) == 0 {
+			fmt.Println(botBar())
+			fmt.Println()
+			continue
+		}
+		fmt.Println(row(strings.Repeat("─", W-10)))
+
+		// Hourly strip, wrapped to fit the box: "08h 12° 10%".
+		const perLine = 6
+		for i := 0; i < len(c.Outfit.Timeline); i += perLine {
+			end := min(i+perLine, len(c.Outfit.Timeline))
+			var cells []string
+			for _, h := range c.Outfit.Timeline[i:end] {
+				rain := clr(dim, fmt.Sprintf("%3d%%", h.PrecipProb))
+				if h.PrecipProb >= 30 {
+					rain = clr(blue+bold, fmt.Sprintf("%3d%%", h.PrecipProb))
+				}
+				cells = append(cells, fmt.Sprintf("%s %s %s",
+					clr(dim, h.Time[:2]+"h"),
+					clr(tempColor(h.FeelsLike, info.TempUnit), fmt.Sprintf("%3.0f°", h.FeelsLike)),
+					rain,
+				))
+			}
+			fmt.Println(row(strings.Join(cells, "  ")))
+		}
+		fmt.Println(row(strings.Repeat("─", W-10)))
+		for _, item := range c.Outfit.Items {
+			fmt.Println(row(fmt.Sprintf("%s  %-16s  %s",
+				outfitEmoji(item.Icon),
+				clr(bold, item.Label),
+				clr(dim, item.Note),
+			)))
+		}
+		fmt.Println(botBar())
+		fmt.Println()
+	}
+}
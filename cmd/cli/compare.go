@@ -0,0 +1,171 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"WeatherApp/i18n"
+	"WeatherApp/weather"
+)
+
+// cityList is a repeatable -city flag.
+type cityList []string
+
+func (c *cityList) String() string { return strings.Join(*c, ", ") }
+
+func (c *cityList) Set(s string) error {
+	*c = append(*c, s)
+	return nil
+}
+
+// compareLabelW is the width of the row labels in the comparison table.
+const compareLabelW = 11
+
+// printCompare renders the cities side by side: current conditions, then
+// one row of highs and lows per forecast date.
+func printCompare(cmp *weather.Comparison) {
+	n := len(cmp.Cities)
+	cw := min(16, max(8, (W-8-compareLabelW)/n))
+	cell := func(color, s string) string {
+		return clr(color, fmt.Sprintf("%-*s", cw, clip(s, cw-1)))
+	}
+	// Cells drawn through key are highlighted in -watch when they change.
+	var key string
+	keyed := func(color, s string) string {
+		return watch.clr(key, color, fmt.Sprintf("%-*s", cw, clip(s, cw-1)))
+	}
+	line := func(label string, f func(c weather.CityWeather) string) {
+		cells := []string{clr(dim+cyan, fmt.Sprintf("%-*s", compareLabelW, clip(label, compareLabelW-1)))}
+		for _, c := range cmp.Cities {
+			if c.Info == nil {
+				cells = append(cells, cell(dim, "—"))
+				continue
+			}
+			key = c.Query + " " + label
+			cells = append(cells, f(c))
+		}
+		fmt.Println(row(strings.Join(cells, "")))
+	}
+
+	fmt.Println()
+	fmt.Println(topBar(tr("Compare")))
+	header := []string{strings.Repeat(" ", compareLabelW)}
+	for _, c := range cmp.Cities {
+		color := bold + white
+		if c.Info == nil {
+			color = bold + red
+		}
+		header = append(header, cell(color, c.Name()))
+	}
+	fmt.Println(row(strings.Join(header, "")))
+	fmt.Println(row(strings.Repeat("─", W-10)))
+
+	line(tr("Local time"), func(c weather.CityWeather) string {
+		t := c.Info.Current.Time
+		if len(t) >= 16 {
+			t = t[11:16]
+		}
+		return cell(dim, t)
+	})
+	line(tr("Now"), func(c weather.CityWeather) string {
+		return keyed(bold+white, tr(c.Info.Current.Description))
+	})
+	line(tr("Temp"), func(c weather.CityWeather) string {
+		cur, unit := c.Info.Current, c.Info.TempUnit
+		return keyed(bold+tempColor(cur.Temp, unit), i18n.Number(lang, cur.Temp, 1)+unit)
+	})
+	line(tr("Feels"), func(c weather.CityWeather) string {
+		cur, unit := c.Info.Current, c.Info.TempUnit
+		return keyed(tempColor(cur.FeelsLike, unit), i18n.Number(lang, cur.FeelsLike, 1)+unit)
+	})
+	line(tr("Humidity"), func(c weather.CityWeather) string {
+		return keyed(white, fmt.Sprintf("%d%%", c.Info.Current.Humidity))
+	})
+	line(tr("Wind"), func(c weather.CityWeather) string {
+		return keyed(white, fmt.Sprintf("%.0f %s", c.Info.Current.WindSpeed, c.Info.WindUnit))
+	})
+	line(tr("Rain 24h"), func(c weather.CityWeather) string {
+		p := c.MaxPrecipProb()
+		color := dim
+		if p >= 30 {
+			color = blue + bold
+		}
+		return keyed(color, fmt.Sprintf("%d%%", p))
+	})
+	line("UV", func(c weather.CityWeather) string {
+		uv := c.Info.Current.UVIndex
+		return keyed(uvColor(uv), i18n.Number(lang, uv, 0))
+	})
+	line(tr("Alerts"), func(c weather.CityWeather) string {
+		alerts := weather.Alerts(c.Info)
+		fresh := false
+		for _, a := range alerts {
+			fresh = watch.newAlert(c.Query+" "+a.Title, c.Info.CityName+": "+a.Title) || fresh
+		}
+		if len(alerts) == 0 {
+			return cell(dim, "—")
+		}
+		color := cyan
+		switch alerts[0].Level {
+		case weather.AlertDanger:
+			color = red + bold
+		case weather.AlertWarning:
+			color = yellow + bold
+		}
+		if fresh {
+			color += blink
+		}
+		return cell(color, alerts[0].Title)
+	})
+
+	if len(cmp.Dates) > 0 {
+		fmt.Println(row(strings.Repeat("─", W-10)))
+		fmt.Println(row(clr(dim, tr("Highs / lows"))))
+		for _, date := range cmp.Dates {
+			label := date
+			if d, err := time.Parse("2006-01-02", date); err == nil {
+				label = i18n.Date(lang, d, "Mon 2 Jan")
+			}
+			line(label, func(c weather.CityWeather) string {
+				day := c.Day(date)
+				if day == nil {
+					return cell(dim, "—")
+				}
+				unit := c.Info.TempUnit
+				hi := watch.clr(key+" hi", tempColor(day.TempMax, unit), fmt.Sprintf("%.0f°", day.TempMax))
+				lo := watch.clr(key+" lo", tempColor(day.TempMin, unit), fmt.Sprintf("%.0f°", day.TempMin))
+				visible := len([]rune(fmt.Sprintf("%.0f° / %.0f°", day.TempMax, day.TempMin)))
+				return hi + clr(dim, " / ") + lo + strings.Repeat(" ", max(0, cw-visible))
+			})
+		}
+	}
+	fmt.Println(botBar())
+
+	for _, c := range cmp.Cities {
+		if c.Err != "" {
+			fmt.Printf("  %s%s:%s %s\n", red+bold, c.Query, reset, c.Err)
+		}
+	}
+	fmt.Println()
+}
+
+// positionalCities reads the positional arguments as cities to compare,
+// except that words which together geocode as one place are that place, so
+// `weather-cli New York` still means New York. Saved location names always
+// count as cities of their own.
+func positionalCities(args []string) []string {
+	if len(args) < 2 {
+		return args
+	}
+	for _, a := range args {
+		if cfg.resolve(a) != a {
+			return args
+		}
+	}
+	joined := strings.Join(args, " ")
+	if _, err := weather.NewClient().Geocode(joined); err == nil {
+		return []string{joined}
+	}
+	return args
+}
@@ -0,0 +1,653 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"WeatherApp/i18n"
+)
+
+// config is the CLI's settings file, a small subset of TOML:
+//
+//	location = "home"           # default city, or a name from [locations]
+//	units    = "imperial"
+//	lang     = "de"
+//	theme    = "light"          # default, light or none
+//	sections = ["current", "hourly", "forecast"]
+//
+//	[locations]
+//	home   = "Leeds"
+//	office = "Manchester"
+//
+// WEATHER_LOCATION, WEATHER_UNITS, WEATHER_LANG, WEATHER_THEME and
+// WEATHER_SECTIONS override the file, and flags override both.
+type config struct {
+	Location  string
+	Units     string
+	Lang      string
+	Theme     string
+	Sections  []string
+	Locations []namedLocation // in file order
+}
+
+// namedLocation is one entry of [locations]: a short name for a city.
+type namedLocation struct {
+	Name, City string
+}
+
+// cfg is loaded before any subcommand runs.
+var cfg config
+
+// dashboardSections are the sections of the main forecast view, in the
+// order they are drawn; config and -sections pick from them.
+var dashboardSections = []string{
+	"alerts", "current", "daylight", "uv", "moon", "photo", "hourly",
+	"forecast", "outfit", "commute", "consensus", "ensemble",
+}
+
+var themes = []string{"default", "light", "none"}
+
+// configPath is $WEATHER_CONFIG, else config.toml in the config directory.
+func configPath() string {
+	if path := os.Getenv("WEATHER_CONFIG"); path != "" {
+		return path
+	}
+	return filepath.Join(configDir(), "config.toml")
+}
+
+// readConfigFile parses the config file; a missing file is an empty config.
+func readConfigFile() (config, error) {
+	path := configPath()
+	b, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		return config{}, nil
+	case err != nil:
+		return config{}, err
+	}
+	c, err := parseConfig(string(b))
+	if err != nil {
+		return config{}, fmt.Errorf("%s: %w", path, err)
+	}
+	return c, nil
+}
+
+// loadConfig reads the config file, if there is one, and applies the
+// environment overrides. A broken file or a bad setting is left out and
+// reported in err rather than stopping the CLI: flags and loc still work.
+func loadConfig() (config, error) {
+	var errs []error
+	c, err := readConfigFile()
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	for _, o := range []struct {
+		env string
+		dst *string
+	}{
+		{"WEATHER_LOCATION", &c.Location},
+		{"WEATHER_UNITS", &c.Units},
+		{"WEATHER_LANG", &c.Lang},
+		{"WEATHER_THEME", &c.Theme},
+	} {
+		if v := os.Getenv(o.env); v != "" {
+			*o.dst = v
+		}
+	}
+	if v := os.Getenv("WEATHER_SECTIONS"); v != "" {
+		c.Sections = splitList(v)
+	}
+	// NO_COLOR asks for plain output unless a theme was chosen on purpose.
+	if c.Theme == "" && os.Getenv("NO_COLOR") != "" {
+		c.Theme = "none"
+	}
+
+	if c.Units != "" && c.Units != "metric" && c.Units != "imperial" {
+		errs = append(errs, fmt.Errorf("units: %q is not metric or imperial", c.Units))
+		c.Units = ""
+	}
+	if c.Theme != "" && !slices.Contains(themes, c.Theme) {
+		errs = append(errs, fmt.Errorf("theme: %q is not one of %s", c.Theme, strings.Join(themes, ", ")))
+		c.Theme = ""
+	}
+	if _, err := parseSections(strings.Join(c.Sections, ",")); err != nil {
+		errs = append(errs, err)
+		c.Sections = nil
+	}
+	return c, errors.Join(errs...)
+}
+
+// resolve turns a location name into its city; anything else is a city
+// already and comes back unchanged.
+func (c config) resolve(city string) string {
+	for _, l := range c.Locations {
+		if strings.EqualFold(l.Name, city) {
+			return l.City
+		}
+	}
+	return city
+}
+
+// defaultCity is the configured location, else London.
+func (c config) defaultCity() string {
+	if c.Location == "" {
+		return "London"
+	}
+	return c.resolve(c.Location)
+}
+
+// unitsOr is the configured units, else def.
+func (c config) unitsOr(def string) string {
+	if c.Units == "" {
+		return def
+	}
+	return c.Units
+}
+
+// parseSections reads a comma-separated list of dashboardSections; an empty
+// list means all of them.
+func parseSections(spec string) ([]string, error) {
+	names := splitList(spec)
+	for _, name := range names {
+		if !slices.Contains(dashboardSections, name) {
+			return nil, fmt.Errorf("sections: unknown section %q (have %s)", name, strings.Join(dashboardSections, ", "))
+		}
+	}
+	return names, nil
+}
+
+// splitList splits a comma-separated list, dropping blanks.
+func splitList(s string) []string {
+	var out []string
+	for _, part := range strings.Split(s, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
+
+// parseConfig reads the TOML subset config uses: comments, a root table and
+// [locations], string values, and one-line arrays of strings for sections.
+func parseConfig(data string) (config, error) {
+	var c config
+	table := ""
+	for i, line := range strings.Split(data, "\n") {
+		line = strings.TrimSpace(stripComment(line))
+		if line == "" {
+			continue
+		}
+		fail := func(format string, args ...any) (config, error) {
+			return c, fmt.Errorf("line %d: %s", i+1, fmt.Sprintf(format, args...))
+		}
+		if strings.HasPrefix(line, "[") {
+			name, ok := tableHeader(line)
+			if !ok {
+				return fail("bad table header %s", line)
+			}
+			table = name
+			if table != "locations" {
+				return fail("unknown table [%s]", table)
+			}
+			continue
+		}
+		key, value, ok := splitKeyValue(line)
+		if !ok {
+			return fail("expected key = value")
+		}
+
+		if key == "sections" && table == "" {
+			list, err := parseStringArray(value)
+			if err != nil {
+				return fail("sections: %v", err)
+			}
+			c.Sections = list
+			continue
+		}
+		s, err := parseString(value)
+		if err != nil {
+			return fail("%s: %v", key, err)
+		}
+		if table == "locations" {
+			if slices.ContainsFunc(c.Locations, func(l namedLocation) bool { return strings.EqualFold(l.Name, key) }) {
+				return fail("location %q given twice", key)
+			}
+			c.Locations = append(c.Locations, namedLocation{key, s})
+			continue
+		}
+		switch key {
+		case "location":
+			c.Location = s
+		case "units":
+			c.Units = s
+		case "lang":
+			c.Lang = s
+		case "theme":
+			c.Theme = s
+		default:
+			return fail("unknown key %q", key)
+		}
+	}
+	return c, nil
+}
+
+// tableHeader reads a "[name]" line; TOML allows spaces inside the brackets.
+func tableHeader(line string) (name string, ok bool) {
+	if !strings.HasPrefix(line, "[") || !strings.HasSuffix(line, "]") {
+		return "", false
+	}
+	return strings.TrimSpace(line[1 : len(line)-1]), true
+}
+
+// stripComment drops a trailing # comment, leaving any # inside a string.
+func stripComment(line string) string {
+	var quote byte
+	for i := 0; i < len(line); i++ {
+		switch c := line[i]; {
+		case quote == '"' && c == '\\':
+			i++
+		case quote != 0 && c == quote:
+			quote = 0
+		case quote == 0 && (c == '"' || c == '\''):
+			quote = c
+		case quote == 0 && c == '#':
+			return line[:i]
+		}
+	}
+	return line
+}
+
+// splitKeyValue splits `key = value`; the key may be bare or quoted.
+func splitKeyValue(line string) (key, value string, ok bool) {
+	if strings.HasPrefix(line, `"`) || strings.HasPrefix(line, "'") {
+		end := quotedEnd(line)
+		if end < 0 {
+			return "", "", false
+		}
+		k, err := parseString(line[:end+1])
+		if err != nil {
+			return "", "", false
+		}
+		rest := strings.TrimSpace(line[end+1:])
+		if !strings.HasPrefix(rest, "=") {
+			return "", "", false
+		}
+		return k, strings.TrimSpace(rest[1:]), k != ""
+	}
+	key, value, ok = strings.Cut(line, "=")
+	key = strings.TrimSpace(key)
+	return key, strings.TrimSpace(value), ok && bareKey.MatchString(key)
+}
+
+var bareKey = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// quotedEnd returns the index of the quote that closes the string s starts
+// with, skipping escapes in a basic string, or -1 if it is unterminated.
+func quotedEnd(s string) int {
+	for i := 1; i < len(s); i++ {
+		switch {
+		case s[0] == '"' && s[i] == '\\':
+			i++
+		case s[i] == s[0]:
+			return i
+		}
+	}
+	return -1
+}
+
+// parseString reads a "basic" or 'literal' TOML string.
+func parseString(s string) (string, error) {
+	switch {
+	case len(s) >= 2 && s[0] == '\'' && s[len(s)-1] == '\'':
+		if strings.Contains(s[1:len(s)-1], "'") {
+			return "", fmt.Errorf("unexpected ' in %s", s)
+		}
+		return s[1 : len(s)-1], nil
+	case len(s) >= 2 && s[0] == '"' && s[len(s)-1] == '"':
+		return unquoteBasic(s[1 : len(s)-1])
+	}
+	return "", fmt.Errorf("expected a quoted string, got %s", s)
+}
+
+// unquoteBasic decodes the inside of a TOML basic string: the escapes
+// \b \t \n \f \r \" \\ \uXXXX and \UXXXXXXXX, and no bare quotes or control
+// characters other than tab.
+func unquoteBasic(s string) (string, error) {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '"':
+			return "", errors.New(`unescaped " in string`)
+		case c < 0x20 && c != '\t' || c == 0x7f:
+			return "", fmt.Errorf("control character %U in string", c)
+		case c != '\\':
+			b.WriteByte(c)
+			continue
+		}
+		if i++; i == len(s) {
+			return "", errors.New(`string ends in \`)
+		}
+		switch e := s[i]; e {
+		case 'b':
+			b.WriteByte('\b')
+		case 't':
+			b.WriteByte('\t')
+		case 'n':
+			b.WriteByte('\n')
+		case 'f':
+			b.WriteByte('\f')
+		case 'r':
+			b.WriteByte('\r')
+		case '"', '\\':
+			b.WriteByte(e)
+		case 'u', 'U':
+			n := 4
+			if e == 'U' {
+				n = 8
+			}
+			if i+n >= len(s) {
+				return "", fmt.Errorf(`short \%c escape`, e)
+			}
+			r, err := strconv.ParseUint(s[i+1:i+1+n], 16, 32)
+			if err != nil || !utf8.ValidRune(rune(r)) {
+				return "", fmt.Errorf(`bad \%c escape %s`, e, s[i+1:i+1+n])
+			}
+			b.WriteRune(rune(r))
+			i += n
+		default:
+			return "", fmt.Errorf(`bad escape \%c`, e)
+		}
+	}
+	return b.String(), nil
+}
+
+// tomlQuote writes s as a TOML basic string, the inverse of unquoteBasic.
+func tomlQuote(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range strings.ToValidUTF8(s, "\uFFFD") {
+		switch r {
+		case '"', '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case '\b':
+			b.WriteString(`\b`)
+		case '\t':
+			b.WriteString(`\t`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\f':
+			b.WriteString(`\f`)
+		case '\r':
+			b.WriteString(`\r`)
+		default:
+			if r < 0x20 || r == 0x7f {
+				fmt.Fprintf(&b, `\u%04X`, r)
+			} else {
+				b.WriteRune(r)
+			}
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+// parseStringArray reads ["a", "b"] on one line.
+func parseStringArray(s string) ([]string, error) {
+	if !strings.HasPrefix(s, "[") || !strings.HasSuffix(s, "]") {
+		return nil, fmt.Errorf("expected [\"a\", \"b\"], got %s", s)
+	}
+	var out []string
+	rest := strings.TrimSpace(s[1 : len(s)-1])
+	for rest != "" {
+		if rest[0] != '"' && rest[0] != '\'' {
+			return nil, fmt.Errorf("expected a quoted string at %s", rest)
+		}
+		end := quotedEnd(rest)
+		if end < 0 {
+			return nil, errors.New("unterminated string")
+		}
+		v, err := parseString(rest[:end+1])
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, v)
+		rest = strings.TrimSpace(rest[end+1:])
+		if strings.HasPrefix(rest, ",") {
+			rest = strings.TrimSpace(rest[1:])
+		} else if rest != "" {
+			return nil, fmt.Errorf("expected , at %s", rest)
+		}
+	}
+	return out, nil
+}
+
+// tomlKey writes name as a key, quoted unless it is a bare key.
+func tomlKey(name string) string {
+	if bareKey.MatchString(name) {
+		return name
+	}
+	return tomlQuote(name)
+}
+
+// editConfig rewrites the config file line by line so comments and layout
+// survive: set, when not nil, replaces or adds key in table ("" is the root
+// table); otherwise the key is removed. It reports whether the key was there.
+func editConfig(table, key string, set *string) (found bool, err error) {
+	path := configPath()
+	b, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return false, err
+	}
+	lines := strings.Split(strings.TrimRight(string(b), "\n"), "\n")
+	if len(b) == 0 {
+		lines = nil
+	}
+
+	// Find the table's lines and the key among them.
+	start, end := 0, len(lines)
+	if table != "" {
+		start = -1
+	}
+	for i, line := range lines {
+		l := strings.TrimSpace(stripComment(line))
+		if !strings.HasPrefix(l, "[") {
+			continue
+		}
+		if start >= 0 && i >= start {
+			end = i
+			break
+		}
+		if name, _ := tableHeader(l); name == table {
+			start = i + 1
+		}
+	}
+	at, last := -1, start-1
+	if start >= 0 {
+		for i := start; i < end; i++ {
+			l := strings.TrimSpace(stripComment(lines[i]))
+			if l == "" {
+				continue
+			}
+			last = i
+			if k, _, ok := splitKeyValue(l); ok && strings.EqualFold(k, key) {
+				at = i
+			}
+		}
+	}
+
+	switch {
+	case set == nil && at < 0:
+		return false, nil
+	case set == nil:
+		lines = slices.Delete(lines, at, at+1)
+	case at >= 0:
+		comment := strings.TrimSpace(lines[at][len(stripComment(lines[at])):])
+		lines[at] = strings.TrimSpace(tomlKey(key) + " = " + tomlQuote(*set) + " " + comment)
+	case start < 0:
+		if len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) != "" {
+			lines = append(lines, "")
+		}
+		lines = append(lines, "["+table+"]", tomlKey(key)+" = "+tomlQuote(*set))
+	case last+1 < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[last+1]), "["):
+		// Keep a blank line between the root keys and the first table.
+		lines = slices.Insert(lines, last+1, tomlKey(key)+" = "+tomlQuote(*set), "")
+	default:
+		lines = slices.Insert(lines, last+1, tomlKey(key)+" = "+tomlQuote(*set))
+	}
+
+	for len(lines) > 0 && strings.TrimSpace(lines[0]) == "" {
+		lines = lines[1:]
+	}
+	out := strings.Join(lines, "\n") + "\n"
+	if _, err := parseConfig(out); err != nil {
+		return false, fmt.Errorf("%s: %w", path, err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return false, err
+	}
+	return at >= 0, os.WriteFile(path, []byte(out), 0o644)
+}
+
+// runLoc implements `weather-cli loc list|add|rm`: named locations that
+// stand in for a city anywhere the CLI takes one.
+func runLoc(args []string) {
+	fs := flag.NewFlagSet("loc", flag.ExitOnError)
+	makeDefault := fs.Bool("default", false, "With add: also make it the default location")
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: weather-cli loc list")
+		fmt.Fprintln(os.Stderr, "       weather-cli loc add [-default] NAME City")
+		fmt.Fprintln(os.Stderr, "       weather-cli loc rm NAME")
+		fs.PrintDefaults()
+	}
+	if len(args) == 0 {
+		args = []string{"list"}
+	}
+	cmd := args[0]
+	fs.Parse(args[1:])
+	lang = i18n.Match(cfg.Lang, os.Getenv("LC_ALL"), os.Getenv("LC_MESSAGES"), os.Getenv("LANG"))
+
+	fail := func(err error) {
+		fmt.Fprintf(os.Stderr, "\n  %sError:%s %v\n\n", red+bold, reset, err)
+		os.Exit(1)
+	}
+	switch cmd {
+	case "list", "ls":
+		printLocations()
+	case "add":
+		if fs.NArg() < 2 {
+			fs.Usage()
+			os.Exit(2)
+		}
+		name, city := fs.Arg(0), strings.Join(fs.Args()[1:], " ")
+		replaced, err := editConfig("locations", name, &city)
+		if err != nil {
+			fail(err)
+		}
+		if *makeDefault {
+			if _, err := editConfig("", "location", &name); err != nil {
+				fail(err)
+			}
+		}
+		verb := "Added"
+		if replaced {
+			verb = "Updated"
+		}
+		fmt.Printf("\n  %s %s → %s\n\n", clr(green+bold, verb), clr(bold, name), city)
+	case "rm", "remove":
+		if fs.NArg() != 1 {
+			fs.Usage()
+			os.Exit(2)
+		}
+		name := fs.Arg(0)
+		file, err := readConfigFile()
+		if err != nil {
+			fail(err)
+		}
+		found, err := editConfig("locations", name, nil)
+		if err != nil {
+			fail(err)
+		}
+		if !found {
+			fail(fmt.Errorf("no saved location %q", name))
+		}
+		fmt.Printf("\n  %s %s\n", clr(yellow+bold, "Removed"), clr(bold, name))
+		// Left behind, the default would be geocoded as a city called name.
+		if strings.EqualFold(file.Location, name) {
+			if _, err := editConfig("", "location", nil); err != nil {
+				fail(err)
+			}
+			fmt.Printf("  %s\n", clr(dim, "It was the default location; the default is London again."))
+		}
+		fmt.Println()
+	default:
+		fs.Usage()
+		os.Exit(2)
+	}
+}
+
+// printLocations lists the named locations, marking the default.
+func printLocations() {
+	fmt.Println()
+	fmt.Println(topBar(tr("Saved Locations")))
+	if len(cfg.Locations) == 0 {
+		fmt.Println(row(clr(dim, tr("None yet: weather-cli loc add home Leeds"))))
+	}
+	for _, l := range cfg.Locations {
+		mark := ""
+		if strings.EqualFold(l.Name, cfg.Location) {
+			mark = clr(green, "  ("+tr("default")+")")
+		}
+		fmt.Println(row(fmt.Sprintf("%s  %s%s", clr(bold+cyan, fmt.Sprintf("%-12s", l.Name)), clr(white, l.City), mark)))
+	}
+	fmt.Println(botBar())
+	fmt.Println("  " + clr(dim, configPath()))
+	fmt.Println()
+}
+
+// palette is every color variable, for applyTheme to reset.
+func palette() []*string {
+	return []*string{
+		&reset, &bold, &dim, &italic, &blink, &reverse,
+		&red, &yellow, &green, &blue, &cyan, &magenta, &white, &orange, &black, &skyBlue,
+		&bgRed, &bgYellow, &bgBlue,
+	}
+}
+
+// defaultPalette is the colors as declared, before any theme.
+var defaultPalette = func() []string {
+	var colors []string
+	for _, c := range palette() {
+		colors = append(colors, *c)
+	}
+	return colors
+}()
+
+// applyTheme sets the color palette: default suits dark terminals, light
+// swaps the pale colors for darker ones, none prints plain text. Each call
+// starts again from the default palette, so the last theme applied is the
+// only one in effect.
+func applyTheme(name string) {
+	for i, c := range palette() {
+		*c = defaultPalette[i]
+	}
+	switch name {
+	case "light":
+		white, yellow, cyan, green = "\033[30m", "\033[38;5;136m", "\033[38;5;31m", "\033[38;5;28m"
+		skyBlue = "\033[38;5;26m"
+	case "none":
+		reset, bold, dim, italic, blink, reverse = "", "", "", "", "", ""
+		red, yellow, green, blue, cyan, magenta, white, orange, black, skyBlue = "", "", "", "", "", "", "", "", "", ""
+		bgRed, bgYellow, bgBlue = "", "", ""
+	}
+}
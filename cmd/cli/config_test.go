@@ -0,0 +1,188 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseConfig(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		in   string
+		want config
+		err  string // substring of the error; "" means none
+	}{
+		{
+			name: "empty",
+			in:   "\n# nothing yet\n",
+		},
+		{
+			name: "full",
+			in: `location = "home"           # default city
+units    = "imperial"
+lang     = "de"
+theme    = "light"
+sections = ["current", "hourly", "forecast"]
+
+[locations]
+home   = "Leeds"
+office = 'Manchester'
+`,
+			want: config{
+				Location: "home", Units: "imperial", Lang: "de", Theme: "light",
+				Sections:  []string{"current", "hourly", "forecast"},
+				Locations: []namedLocation{{"home", "Leeds"}, {"office", "Manchester"}},
+			},
+		},
+		{
+			name: "spaced table header",
+			in:   "[ locations ]\nhome = \"Leeds\"\n",
+			want: config{Locations: []namedLocation{{"home", "Leeds"}}},
+		},
+		{
+			name: "quoted key",
+			in:   "[locations]\n\"my \\\"place\\\"\" = \"York\"\n'old town' = \"Bath\"\n",
+			want: config{Locations: []namedLocation{{`my "place"`, "York"}, {"old town", "Bath"}}},
+		},
+		{
+			name: "escapes",
+			in:   `location = "a\"b\\c\tZ\u00e9\U0001F600 # not a comment" # a comment`,
+			want: config{Location: "a\"b\\c\tZé😀 # not a comment"},
+		},
+		{
+			name: "literal string keeps backslashes",
+			in:   `location = 'C:\temp'`,
+			want: config{Location: `C:\temp`},
+		},
+		{name: "unknown key", in: `colour = "red"`, err: `line 1: unknown key "colour"`},
+		{name: "unknown table", in: "\n[servers]\n", err: "line 2: unknown table [servers]"},
+		{name: "unclosed table", in: "[locations", err: "bad table header"},
+		{name: "duplicate location", in: "[locations]\nhome = \"Leeds\"\nHOME = \"York\"\n", err: `line 3: location "HOME" given twice`},
+		{name: "missing equals", in: `location "Leeds"`, err: "expected key = value"},
+		{name: "unquoted value", in: `location = Leeds`, err: "expected a quoted string"},
+		{name: "go-only escape", in: `location = "\x41"`, err: `bad escape \x`},
+		{name: "short unicode escape", in: `location = "\u00"`, err: `short \u escape`},
+		{name: "bare quote", in: `location = "a"b"`, err: `unescaped "`},
+		{name: "unterminated array string", in: `sections = ["current]`, err: "unterminated string"},
+		{name: "array without comma", in: `sections = ["current" "hourly"]`, err: "expected , at"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := parseConfig(tc.in)
+			if tc.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.err) {
+					t.Fatalf("err = %v, want it to contain %q", err, tc.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("got %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestStripComment(t *testing.T) {
+	for _, tc := range []struct{ in, want string }{
+		{`units = "metric"`, `units = "metric"`},
+		{`units = "metric" # or imperial`, `units = "metric" `},
+		{`# whole line`, ``},
+		{`location = "#1 Street"`, `location = "#1 Street"`},
+		{`location = '#1 Street' # note`, `location = '#1 Street' `},
+		{`location = "say \"#hi\"" # note`, `location = "say \"#hi\"" `},
+		{`location = 'C:\' # note`, `location = 'C:\' `},
+		{`location = "unterminated # still in the string`, `location = "unterminated # still in the string`},
+	} {
+		if got := stripComment(tc.in); got != tc.want {
+			t.Errorf("stripComment(%q) = %q, want %q", tc.in, got, tc.want)
+		}
+	}
+}
+
+func TestEditConfig(t *testing.T) {
+	str := func(s string) *string { return &s }
+	type edit struct {
+		table, key string
+		set        *string
+		found      bool
+	}
+	for _, tc := range []struct {
+		name  string
+		in    string
+		edits []edit
+		want  string
+	}{
+		{
+			name:  "new file",
+			edits: []edit{{"locations", "home", str("Leeds"), false}, {"", "location", str("home"), false}},
+			want:  "location = \"home\"\n\n[locations]\nhome = \"Leeds\"\n",
+		},
+		{
+			name:  "spaced header is reused",
+			in:    "units = \"metric\"\n\n[ locations ]  # saved places\nhome = \"Leeds\"\n",
+			edits: []edit{{"locations", "office", str("York"), false}},
+			want:  "units = \"metric\"\n\n[ locations ]  # saved places\nhome = \"Leeds\"\noffice = \"York\"\n",
+		},
+		{
+			name:  "replace keeps comments and case-insensitive match",
+			in:    "# my settings\nunits = \"metric\" # for now\n\n[locations]\nHome = \"Leeds\"\n",
+			edits: []edit{{"locations", "home", str("Bradford"), true}, {"", "units", str("imperial"), true}},
+			want:  "# my settings\nunits = \"imperial\" # for now\n\n[locations]\nhome = \"Bradford\"\n",
+		},
+		{
+			name:  "remove",
+			in:    "location = \"home\"\n\n[locations]\nhome = \"Leeds\"\noffice = \"York\"\n",
+			edits: []edit{{"locations", "home", nil, true}, {"", "location", nil, true}, {"", "location", nil, false}},
+			want:  "[locations]\noffice = \"York\"\n",
+		},
+		{
+			name:  "quoted key and escaped value",
+			edits: []edit{{"locations", "old town", str("a\"b\\c\x01\x7fé"), false}},
+			want:  "[locations]\n\"old town\" = \"a\\\"b\\\\c\\u0001\\u007Fé\"\n",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "config.toml")
+			t.Setenv("WEATHER_CONFIG", path)
+			if tc.in != "" {
+				if err := os.WriteFile(path, []byte(tc.in), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			for _, e := range tc.edits {
+				found, err := editConfig(e.table, e.key, e.set)
+				if err != nil {
+					t.Fatalf("editConfig(%q, %q): %v", e.table, e.key, err)
+				}
+				if found != e.found {
+					t.Errorf("editConfig(%q, %q) found = %v, want %v", e.table, e.key, found, e.found)
+				}
+			}
+			b, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(b) != tc.want {
+				t.Errorf("file:\n%s\nwant:\n%s", b, tc.want)
+			}
+			// Whatever was written reads back as what was set.
+			c, err := parseConfig(string(b))
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, e := range tc.edits {
+				if e.set == nil || e.table != "locations" {
+					continue
+				}
+				if got := c.resolve(e.key); got != *e.set {
+					t.Errorf("%s reads back as %q, want %q", e.key, got, *e.set)
+				}
+			}
+		})
+	}
+}
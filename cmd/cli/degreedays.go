@@ -0,0 +1,157 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"WeatherApp/weather"
+)
+
+// runDegreeDays implements `weather-cli degreedays [City]`: heating and
+// cooling degree days for the past days and the forecast against last
+// year, as a report or CSV.
+func runDegreeDays(args []string) {
+	fs := flag.NewFlagSet("degreedays", flag.ExitOnError)
+	cityFlag := fs.String("city", "", "City name (or first positional argument)")
+	units := fs.String("units", cfg.unitsOr("metric"), "Units: metric (°C) or imperial (°F)")
+	heat := fs.String("heat", "", "Heating base temperature in -units (default 15.5 °C / 65 °F)")
+	cool := fs.String("cool", "", "Cooling base temperature in -units (default 22 °C / 65 °F)")
+	past := fs.Int("past", 7, "Days before today to include (1–92)")
+	days := fs.Int("days", 7, "Days from today to forecast (1–16)")
+	asCSV := fs.Bool("csv", false, "Write CSV to stdout instead of the report")
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: weather-cli degreedays [flags] [City]")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	fail := func(err error) {
+		fmt.Fprintf(os.Stderr, "\n  %sError:%s %v\n\n", red+bold, reset, err)
+		os.Exit(1)
+	}
+	city := *cityFlag
+	if city == "" {
+		if fs.NArg() > 0 {
+			city = strings.Join(fs.Args(), " ")
+		} else {
+			city = cfg.defaultCity()
+		}
+	}
+	city = cfg.resolve(city)
+	opts := weather.DegreeDayOptions{Past: *past, Days: *days}
+	for _, f := range []struct {
+		name, spec string
+		dst        **float64
+	}{{"heating", *heat, &opts.HeatBase}, {"cooling", *cool, &opts.CoolBase}} {
+		if f.spec == "" {
+			continue
+		}
+		v, err := strconv.ParseFloat(f.spec, 64)
+		if err != nil {
+			fail(fmt.Errorf("bad %s base %q", f.name, f.spec))
+		}
+		*f.dst = &v
+	}
+
+	if *asCSV {
+		r, err := weather.NewClient().DegreeDays(city, *units, opts)
+		if err != nil {
+			fail(err)
+		}
+		if err := r.WriteCSV(os.Stdout); err != nil {
+			fail(err)
+		}
+		return
+	}
+
+	fmt.Println()
+	done := startSpinner("Fetching degree days for " + clr(bold+white, city) + " ...")
+	r, err := weather.NewClient().DegreeDays(city, *units, opts)
+	close(done)
+	time.Sleep(20 * time.Millisecond) // let spinner goroutine clear line
+	fmt.Println()
+	if err != nil {
+		fail(err)
+	}
+	printDegreeDays(r)
+}
+
+// ddChange formats a year-on-year change, e.g. "+12% on last year".
+func ddChange(pct float64, ok bool) string {
+	if !ok {
+		return clr(dim, "no comparison")
+	}
+	c := green
+	if pct > 0 {
+		c = orange
+	}
+	return clr(c, fmt.Sprintf("%+.0f%%", pct)) + clr(dim, " on last year")
+}
+
+// printDegreeDays renders one row per day, split into the past and the
+// forecast, then each period's totals against the same dates last year.
+func printDegreeDays(r *weather.DegreeDayReport) {
+	tu := r.TempUnit
+	top := 1.0
+	for _, d := range r.Days {
+		top = max(top, d.HDD, d.CDD)
+	}
+	bar := func(v float64, c string) string {
+		return progressBar(int(v/top*100+0.5), 8, c)
+	}
+
+	fmt.Println(topBar(fmt.Sprintf("Degree Days · %s, %s", r.City, r.Country)))
+	fmt.Println(row(clr(dim, fmt.Sprintf("Heating base %g%s · cooling base %g%s · mean-temperature method",
+		r.HeatBase, tu, r.CoolBase, tu))))
+	fmt.Println(blankRow())
+	fmt.Println(row(clr(dim, fmt.Sprintf("%-10s  %-9s  %-14s  %-14s  %s", "DAY", "HI/LO", "HDD", "CDD", "LAST YEAR"))))
+	forecast := false
+	for _, d := range r.Days {
+		if d.Forecast && !forecast {
+			forecast = true
+			fmt.Println(row(clr(dim, strings.Repeat("─", 22)+" forecast "+strings.Repeat("─", 22))))
+		}
+		day, _ := time.Parse("2006-01-02", d.Date)
+		label := day.Format("Mon 2 Jan")
+		if d.Date == r.Today {
+			label = "Today"
+		}
+		last := clr(dim, "—")
+		if d.LastYearHDD != nil {
+			last = clr(dim, fmt.Sprintf("%4.1f / %4.1f", *d.LastYearHDD, *d.LastYearCDD))
+		}
+		fmt.Println(row(fmt.Sprintf("%s  %s  %s %s  %s %s  %s",
+			clr(bold, fmt.Sprintf("%-10s", label)),
+			clr(tempColor(d.TMax, tu), fmt.Sprintf("%3.0f", d.TMax))+clr(dim, "/")+
+				clr(tempColor(d.TMin, tu), fmt.Sprintf("%-3.0f", d.TMin))+"  ",
+			bar(d.HDD, red), clr(red, fmt.Sprintf("%4.1f", d.HDD)),
+			bar(d.CDD, blue), clr(blue, fmt.Sprintf("%4.1f", d.CDD)),
+			last,
+		)))
+	}
+	fmt.Println(blankRow())
+	for _, p := range []struct {
+		name string
+		t    weather.DegreeDayTotals
+	}{
+		{fmt.Sprintf("Last %d days", r.Past.Days), r.Past},
+		{fmt.Sprintf("Next %d days", r.Ahead.Days), r.Ahead},
+	} {
+		if p.t.Days == 0 {
+			continue
+		}
+		fmt.Println(row(clr(dim+cyan, fmt.Sprintf("%-13s", p.name)) +
+			clr(bold+red, fmt.Sprintf("HDD %5.1f", p.t.HDD)) + "  " + ddChange(p.t.HDDChange())))
+		fmt.Println(row(strings.Repeat(" ", 13) +
+			clr(bold+blue, fmt.Sprintf("CDD %5.1f", p.t.CDD)) + "  " + ddChange(p.t.CDDChange())))
+	}
+	fmt.Println(blankRow())
+	fmt.Println(row(clr(dim, "Energy use for heating or cooling scales roughly with degree days;")))
+	fmt.Println(row(clr(dim, "multiply by your kWh per degree day for a week-ahead estimate.")))
+	fmt.Println(botBar())
+	fmt.Println()
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// favoritesPath is where the TUI's city switcher keeps saved cities, one
+// per line.
+func favoritesPath() string { return filepath.Join(configDir(), "favorites") }
+
+// loadFavorites returns the saved cities, or none if nothing was saved.
+func loadFavorites() ([]string, error) {
+	b, err := os.ReadFile(favoritesPath())
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var cities []string
+	for _, line := range strings.Split(string(b), "\n") {
+		if city := strings.TrimSpace(line); city != "" && !slices.Contains(cities, city) {
+			cities = append(cities, city)
+		}
+	}
+	return cities, nil
+}
+
+// saveFavorites stores cities for later runs; none removes the file.
+func saveFavorites(cities []string) error {
+	path := favoritesPath()
+	if len(cities) == 0 {
+		if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(strings.Join(cities, "\n")+"\n"), 0o644)
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"WeatherApp/weather"
+)
+
+// runGarden implements `weather-cli garden [City]`: growing degree days,
+// chill hours, soil, frost dates and whether to water today.
+func runGarden(args []string) {
+	fs := flag.NewFlagSet("garden", flag.ExitOnError)
+	cityFlag := fs.String("city", "", "City name (or first positional argument)")
+	units := fs.String("units", cfg.unitsOr("metric"), "Units: metric (°C/mm) or imperial (°F/in)")
+	since := fs.String("since", "", "Count growing degree days from YYYY-MM-DD (default 1 Mar, or 1 Sep south of the equator)")
+	chillSince := fs.String("chill-since", "", "Count chill hours from YYYY-MM-DD (default 1 Oct, or 1 Apr south of the equator)")
+	base := fs.String("base", "", "GDD base temperature in -units (default 10 °C / 50 °F)")
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: weather-cli garden [flags] [City]")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	fail := func(err error) {
+		fmt.Fprintf(os.Stderr, "\n  %sError:%s %v\n\n", red+bold, reset, err)
+		os.Exit(1)
+	}
+	city := *cityFlag
+	if city == "" {
+		if fs.NArg() > 0 {
+			city = strings.Join(fs.Args(), " ")
+		} else {
+			city = cfg.defaultCity()
+		}
+	}
+	city = cfg.resolve(city)
+	var opts weather.GardenOptions
+	for _, f := range []struct {
+		spec string
+		dst  *time.Time
+	}{{*since, &opts.Since}, {*chillSince, &opts.ChillSince}} {
+		if f.spec == "" {
+			continue
+		}
+		d, err := time.Parse("2006-01-02", f.spec)
+		if err != nil {
+			fail(fmt.Errorf("bad date %q: want YYYY-MM-DD", f.spec))
+		}
+		*f.dst = d
+	}
+	if *base != "" {
+		v, err := strconv.ParseFloat(*base, 64)
+		if err != nil {
+			fail(fmt.Errorf("bad base temperature %q", *base))
+		}
+		if *units == "imperial" {
+			v = (v - 32) * 5 / 9
+		}
+		opts.BaseC = &v
+	}
+
+	fmt.Println()
+	done := startSpinner("Fetching garden data for " + clr(bold+white, city) + " ...")
+	g, err := weather.NewClient().Garden(city, *units, opts)
+	close(done)
+	time.Sleep(20 * time.Millisecond) // let spinner goroutine clear line
+	fmt.Println()
+	if err != nil {
+		fail(err)
+	}
+	printGarden(g)
+}
+
+// printGarden renders the Garden box and the week-either-side water balance.
+func printGarden(g *weather.GardenReport) {
+	tu, du := g.TempUnit(), g.DepthUnit()
+	label := func(s string) string { return clr(dim+cyan, fmt.Sprintf("%-13s", s)) }
+
+	fmt.Println(topBar(fmt.Sprintf("Garden · %s, %s", g.City, g.Country)))
+	fmt.Println(row(label("Degree days") + " " + clr(bold+green, fmt.Sprintf("%.0f %s·d", g.DegreeDays(g.GDD), tu)) +
+		clr(dim, fmt.Sprintf(" since %s, base %.0f%s · +%.0f next week",
+			g.Since.Format("2 Jan"), g.Temp(g.BaseC), tu, g.DegreeDays(g.GDDNextWeek)))))
+	fmt.Println(row(label("Chill hours") + " " + clr(bold+cyan, fmt.Sprintf("%d h", g.ChillHours)) +
+		clr(dim, fmt.Sprintf(" since %s, between %.0f and %.1f%s", g.ChillSince.Format("2 Jan"), g.Temp(0), g.Temp(7.2), tu))))
+	fmt.Println(row(label("Soil") + " " +
+		clr(tempColor(g.Temp(g.SoilTemp6), tu), fmt.Sprintf("%.0f%s", g.Temp(g.SoilTemp6), tu)) + clr(dim, " at 6 cm  ") +
+		clr(tempColor(g.Temp(g.SoilTemp18), tu), fmt.Sprintf("%.0f%s", g.Temp(g.SoilTemp18), tu)) + clr(dim, " at 18 cm  ") +
+		clr(blue, fmt.Sprintf("%.0f%% / %.0f%%", g.SoilMoist*100, g.SoilMoistDp*100)) + clr(dim, " water")))
+	if f := g.Frost; f != nil {
+		fmt.Println(row(label("Last frost") + " " + clr(white, weather.MonthDay(f.LastMedian)) +
+			clr(dim, fmt.Sprintf(" typical · 1 in 10 after %s", weather.MonthDay(f.LastLate)))))
+		fmt.Println(row(label("First frost") + " " + clr(white, weather.MonthDay(f.FirstMedian)) +
+			clr(dim, fmt.Sprintf(" typical · 1 in 10 before %s", weather.MonthDay(f.FirstEarly)))))
+		fmt.Println(row(label("") + " " + clr(dim, fmt.Sprintf("%s (%d years)", f.RiskText(), f.Years))))
+	}
+	if len(g.FrostNights) > 0 {
+		fmt.Println(row(label("Frost due") + " " + clr(bold+cyan, strings.Join(g.FrostNights, ", "))))
+	}
+	fmt.Println(blankRow())
+	if g.Water.Water {
+		fmt.Println(row(clr(bold+blue, "💧 Water today") + "  " + clr(white, g.Water.Reason)))
+	} else {
+		fmt.Println(row(clr(bold+green, "✓ No need to water") + "  " + clr(dim, g.Water.Reason)))
+	}
+	fmt.Println(botBar())
+	fmt.Println()
+
+	if len(g.Days) == 0 {
+		return
+	}
+	fmt.Println(topBar("Water Balance · rain minus evapotranspiration"))
+	fmt.Println(row(clr(dim, fmt.Sprintf("%-10s  %7s  %7s  %8s  %s", "DAY", "RAIN", "ET0", "BALANCE", "LOW"))))
+	var total float64
+	prec := 1 // tenths of a mm, hundredths of an inch
+	if g.Imperial {
+		prec = 2
+	}
+	for _, d := range g.Days {
+		t, _ := time.Parse("2006-01-02", d.Date)
+		day := t.Format("Mon 2")
+		switch {
+		case d.Today:
+			day = "Today"
+		case d.Forecast:
+			day += " ·"
+		}
+		if !d.Forecast && !d.Today {
+			total += d.Balance
+		}
+		balColor := green
+		if d.Balance < 0 {
+			balColor = orange
+		}
+		frost := ""
+		if d.Frost {
+			frost = clr(bold+cyan, " frost")
+		}
+		line := fmt.Sprintf("%-10s  %s  %s  %s  %s%s",
+			day,
+			clr(blue, fmt.Sprintf("%5.*f%s", prec, g.Depth(d.Precip), du)),
+			clr(yellow, fmt.Sprintf("%5.*f%s", prec, g.Depth(d.ET0), du)),
+			clr(balColor, fmt.Sprintf("%+6.*f%s", prec, g.Depth(d.Balance), du)),
+			clr(tempColor(g.Temp(d.TMin), tu), fmt.Sprintf("%3.0f%s", g.Temp(d.TMin), tu)),
+			frost,
+		)
+		if d.Today {
+			line = clr(bold, line)
+		}
+		fmt.Println(row(line))
+	}
+	fmt.Println(row(clr(dim, fmt.Sprintf("Last week's balance %+.*f %s · days marked · are forecast", prec, g.Depth(total), du))))
+	fmt.Println(botBar())
+	fmt.Println()
+}
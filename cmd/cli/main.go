@@ -1,21 +1,30 @@
 package main
 
 import (
+	"errors"
 	"flag"
 	"fmt"
 	"math"
 	"os"
+	"slices"
 	"strings"
 	"time"
+	"unicode/utf8"
 
+	"WeatherApp/astro"
+	"WeatherApp/comfort"
+	"WeatherApp/i18n"
 	"WeatherApp/weather"
 )
 
-const (
-	reset  = "\033[0m"
-	bold   = "\033[1m"
-	dim    = "\033[2m"
-	italic = "\033[3m"
+// The palette is variable so applyTheme can change it.
+var (
+	reset   = "\033[0m"
+	bold    = "\033[1m"
+	dim     = "\033[2m"
+	italic  = "\033[3m"
+	blink   = "\033[5m"
+	reverse = "\033[7m"
 
 	red     = "\033[31m"
 	yellow  = "\033[33m"
@@ -25,6 +34,8 @@ const (
 	magenta = "\033[35m"
 	white   = "\033[97m"
 	orange  = "\033[38;5;208m"
+	black   = "\033[30m"
+	skyBlue = "\033[94m"
 
 	bgRed    = "\033[41m"
 	bgYellow = "\033[43m"
@@ -35,6 +46,21 @@ const W = 70 // total line width for boxes
 
 func clr(color, s string) string { return color + s + reset }
 
+// lang is the -lang locale for the main forecast.
+var lang = i18n.Default
+
+// tr translates a message into lang.
+func tr(msg string) string { return i18n.T(lang, msg) }
+
+// clip shortens s to n characters, ending in "…" when cut.
+func clip(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n-1]) + "…"
+}
+
 func tempColor(temp float64, unit string) string {
 	t := temp
 	if unit == "°F" {
@@ -42,7 +68,7 @@ func tempColor(temp float64, unit string) string {
 	}
 	switch {
 	case t <= 0:
-		return "\033[94m" // bright blue
+		return skyBlue
 	case t <= 10:
 		return cyan
 	case t <= 20:
@@ -69,12 +95,27 @@ func uvColor(uv float64) string {
 	}
 }
 
+func stressColor(l comfort.Level) string {
+	switch l {
+	case comfort.ExtremeDanger:
+		return magenta
+	case comfort.Danger:
+		return red
+	case comfort.ExtremeCaution:
+		return orange
+	case comfort.Caution:
+		return yellow
+	default:
+		return green
+	}
+}
+
 func topBar(title string) string {
 	inner := W - 4 // space inside ┌ ... ┐
 	if title == "" {
 		return "  ┌" + strings.Repeat("─", inner) + "┐"
 	}
-	dash := inner - 2 - len(title) - 1 // "─ " + title + " "
+	dash := inner - 2 - utf8.RuneCountInString(title) - 1 // "─ " + title + " "
 	if dash < 0 {
 		dash = 0
 	}
@@ -102,12 +143,39 @@ func alertColor(level weather.AlertLevel) string {
 	case weather.AlertDanger:
 		return bgRed + bold + white
 	case weather.AlertWarning:
-		return bgYellow + bold + "\033[30m"
+		return bgYellow + bold + black
 	default:
 		return bgBlue + bold + white
 	}
 }
 
+func agreePctColor(pct int) string {
+	switch {
+	case pct >= 80:
+		return green
+	case pct >= 50:
+		return yellow
+	default:
+		return red
+	}
+}
+
+// bandStr renders a model spread band as a fixed-width "±1.2" coloured by
+// confidence. Blank when no band is available.
+func bandStr(b weather.Band) string {
+	if b.Models == 0 {
+		return strings.Repeat(" ", 5)
+	}
+	c := green
+	switch b.Confidence {
+	case "Moderate":
+		c = yellow
+	case "Low":
+		c = red
+	}
+	return clr(c, fmt.Sprintf("±%-4.1f", b.HalfSpread()))
+}
+
 func alertPrefix(level weather.AlertLevel) string {
 	switch level {
 	case weather.AlertDanger:
@@ -155,7 +223,7 @@ func sunLine(sun weather.SunBar, width int) string {
 			if sun.IsDay {
 				colored[i] = clr(bold+yellow, string(ch))
 			} else {
-				colored[i] = clr(bold+"\033[94m", string(ch))
+				colored[i] = clr(bold+skyBlue, string(ch))
 			}
 		default:
 			colored[i] = clr(dim, string(ch))
@@ -204,24 +272,253 @@ func startSpinner(msg string) chan struct{} {
 
 /* Main func*/
 func main() {
-	cityFlag := flag.String("city", "", "City name (or first positional argument)")
-	units := flag.String("units", "metric", "Units: metric (°C/km·h) or imperial (°F/mph)")
+	var err error
+	if cfg, err = loadConfig(); err != nil {
+		for _, line := range strings.Split(err.Error(), "\n") {
+			fmt.Fprintf(os.Stderr, "  %sWarning:%s config: %s (ignored)\n", yellow+bold, reset, line)
+		}
+	}
+	applyTheme(cfg.Theme)
+
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "loc":
+			runLoc(os.Args[2:])
+			return
+		case "verify":
+			runVerify(os.Args[2:])
+			return
+		case "comfort":
+			runComfort(os.Args[2:])
+			return
+		case "pack":
+			runPack(os.Args[2:])
+			return
+		case "stars":
+			runStars(os.Args[2:])
+			return
+		case "garden":
+			runGarden(os.Args[2:])
+			return
+		case "solar":
+			runSolar(os.Args[2:])
+			return
+		case "degreedays":
+			runDegreeDays(os.Args[2:])
+			return
+		case "tui":
+			runTUI(os.Args[2:])
+			return
+		}
+	}
+
+	var cityFlags cityList
+	flag.Var(&cityFlags, "city", "City name; repeat, or give several positional arguments, to compare cities")
+	units := flag.String("units", "", "Units: metric (°C/km·h) or imperial (°F/mph) (default from the config, else the locale's region)")
+	sectionsFlag := flag.String("sections", strings.Join(cfg.Sections, ","), "Sections to show, comma-separated: "+strings.Join(dashboardSections, ", ")+" (default all)")
+	themeFlag := flag.String("theme", cfg.Theme, "Colors: "+strings.Join(themes, ", "))
+	models := flag.String("models", "", "Consensus models, comma-separated (e.g. ecmwf,icon,gfs,jma,gem,ukmo)")
+	aggregation := flag.String("consensus", "mean", "Consensus aggregation: mean, median or trimmed")
+	ensemble := flag.String("ensemble", "", "Ensemble models, comma-separated (e.g. ecmwf,gefs,icon,gem)")
+	activityFlag := flag.String("activity", "walk", "Outfit and suitability profile: "+strings.Join(weather.ActivityKeys(), ", "))
+	commute := flag.String("commute", "", "Commute windows, e.g. 08:00-09:00 or work=08:00-18:00,gym=19:00-21:00")
+	saveCommuteFlag := flag.Bool("save-commute", false, "Remember -commute for later runs (an empty -commute clears it)")
+	skillWeights := flag.Bool("skill-weights", false, "Weight consensus models by their verified skill at this location")
+	skinFlag := flag.String("skin", "2", "Fitzpatrick skin type for UV burn times: 1-6 or I-VI")
+	spf := flag.Float64("spf", 0, "Sunscreen SPF for UV burn times (0 = none)")
+	brief := flag.Bool("brief", false, "Print only a short forecast narrative")
+	notifyFlag := flag.Bool("notify", false, "Also send the forecast narrative as a desktop notification (with -watch, new alerts)")
+	watchFlag := flag.Duration("watch", 0, "Redraw every interval (e.g. 10m) until Ctrl-C, highlighting changes")
+	langFlag := flag.String("lang", "", "Language: "+strings.Join(i18n.Tags(), ", ")+" (default from $LANG)")
+	quotesFlag := flag.String("quotes", os.Getenv("WEATHER_QUOTES"), "Quote packs: built-in names, pack files or directories, comma-separated; off hides the quote")
 	flag.Parse()
 
-	// Support positional arg: weather-cli London  or  weather-cli New York
-	city := *cityFlag
-	if city == "" {
-		if args := flag.Args(); len(args) > 0 {
-			city = strings.Join(args, " ")
-		} else {
-			city = "London"
+	// -lang, then the config, then the POSIX locale; units follow the
+	// locale's region unless -units or the config sets them.
+	localePrefs := []string{*langFlag, cfg.Lang, os.Getenv("LC_ALL"), os.Getenv("LC_MESSAGES"), os.Getenv("LANG")}
+	lang = i18n.Match(localePrefs...)
+	if *units == "" {
+		*units = cfg.unitsOr(i18n.Units(localePrefs...))
+	}
+	if *themeFlag != "" && !slices.Contains(themes, *themeFlag) {
+		fmt.Fprintf(os.Stderr, "\n  %sError:%s -theme must be one of %s\n\n", red+bold, reset, strings.Join(themes, ", "))
+		os.Exit(1)
+	}
+	applyTheme(*themeFlag) // replaces the config's theme
+	sections, err := parseSections(*sectionsFlag)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "\n  %sError:%s %v\n\n", red+bold, reset, err)
+		os.Exit(1)
+	}
+
+	// Each positional argument is a city or a saved location name:
+	// weather-cli London Paris Berlin compares three, weather-cli "New York"
+	// shows one.
+	cities := append(cityFlags, positionalCities(flag.Args())...)
+	if len(cities) == 0 {
+		cities = cityList{cfg.defaultCity()}
+	}
+	for i, c := range cities {
+		cities[i] = cfg.resolve(c)
+	}
+	if len(cities) > weather.MaxCompareCities {
+		fmt.Fprintf(os.Stderr, "\n  %sError:%s at most %d cities can be compared\n\n", red+bold, reset, weather.MaxCompareCities)
+		os.Exit(1)
+	}
+	city := cities[0]
+	if *watchFlag != 0 {
+		switch {
+		case *watchFlag < time.Minute:
+			fmt.Fprintf(os.Stderr, "\n  %sError:%s -watch must be at least 1m\n\n", red+bold, reset)
+			os.Exit(1)
+		case *brief:
+			fmt.Fprintf(os.Stderr, "\n  %sError:%s -watch cannot be combined with -brief\n\n", red+bold, reset)
+			os.Exit(1)
 		}
 	}
 
-	fmt.Println()
-	done := startSpinner("Fetching weather for " + clr(bold+white, city) + " ...")
+	activity, err := weather.ParseActivity(*activityFlag)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "\n  %sError:%s %v\n\n", red+bold, reset, err)
+		os.Exit(1)
+	}
+	skin, err := weather.ParseSkinType(*skinFlag)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "\n  %sError:%s %v\n\n", red+bold, reset, err)
+		os.Exit(1)
+	}
+	quotes, err := weather.LoadQuotes(*quotesFlag)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "\n  %sError:%s %v\n\n", red+bold, reset, err)
+		os.Exit(1)
+	}
+	outfitOpts := weather.OutfitOptions{Activity: activity}
+	if profile, err := weather.LoadComfortProfile(comfortPath()); err != nil {
+		fmt.Fprintf(os.Stderr, "\n  %sWarning:%s ignoring comfort profile: %v\n", yellow+bold, reset, err)
+	} else {
+		outfitOpts.Comfort = profile
+	}
+
+	// Commute windows: -commute overrides the saved set for this run; with
+	// -save-commute it also replaces it.
+	windows, err := weather.ParseWindows(*commute)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "\n  %sError:%s %v\n\n", red+bold, reset, err)
+		os.Exit(1)
+	}
+	if *saveCommuteFlag {
+		if err := saveCommute(windows); err != nil {
+			fmt.Fprintf(os.Stderr, "\n  %sError:%s saving commute: %v\n\n", red+bold, reset, err)
+			os.Exit(1)
+		}
+	} else if *commute == "" {
+		if windows, err = loadCommute(); err != nil {
+			fmt.Fprintf(os.Stderr, "\n  %sWarning:%s ignoring saved commute: %v\n", yellow+bold, reset, err)
+		}
+	}
+
+	done := make(chan struct{})
+	if !*brief && *watchFlag == 0 {
+		fmt.Println()
+		done = startSpinner("Fetching weather for " + clr(bold+white, strings.Join(cities, ", ")) + " ...")
+	}
 
 	client := weather.NewClient()
+	client.Lang = lang
+	if *models != "" {
+		m, err := weather.ParseModels(*models)
+		if err != nil {
+			close(done)
+			fmt.Fprintf(os.Stderr, "\n  %sError:%s %v\n\n", red+bold, reset, err)
+			os.Exit(1)
+		}
+		client.Models = m
+	}
+	agg, err := weather.ParseAggregation(*aggregation)
+	if err != nil {
+		close(done)
+		fmt.Fprintf(os.Stderr, "\n  %sError:%s %v\n\n", red+bold, reset, err)
+		os.Exit(1)
+	}
+	client.Aggregation = agg
+	if *ensemble != "" {
+		m, err := weather.ParseEnsembleModels(*ensemble)
+		if err != nil {
+			close(done)
+			fmt.Fprintf(os.Stderr, "\n  %sError:%s %v\n\n", red+bold, reset, err)
+			os.Exit(1)
+		}
+		client.EnsembleModels = m
+	}
+	// Verification is best-effort: an unreadable store only loses skill tracking.
+	if store, err := openVerifyStore(); err == nil {
+		client.Verify = store
+		client.SkillWeighting = *skillWeights && store != nil
+	}
+	d := dashboard{
+		units:    *units,
+		quotes:   quotes,
+		outfit:   outfitOpts,
+		windows:  windows,
+		uv:       weather.UVOptions{Skin: skin, SPF: *spf},
+		sections: sections,
+	}
+	if *watchFlag != 0 {
+		runWatch(*watchFlag, strings.Join(cities, ", "), *notifyFlag, func() (func(), error) {
+			if len(cities) > 1 {
+				cmp := weather.Compare(cities, func(city string) (*weather.WeatherInfo, error) {
+					return client.GetWeather(city, *units)
+				})
+				for _, c := range cmp.Cities {
+					if c.Info != nil {
+						return func() { printCompare(cmp) }, nil
+					}
+				}
+				return nil, errors.New(cmp.Cities[0].Err)
+			}
+			info, err := client.GetWeather(city, *units)
+			if err != nil {
+				return nil, err
+			}
+			return func() { d.print(info) }, nil
+		})
+		return
+	}
+
+	if len(cities) > 1 {
+		cmp := weather.Compare(cities, func(city string) (*weather.WeatherInfo, error) {
+			return client.GetWeather(city, *units)
+		})
+		close(done)
+		time.Sleep(20 * time.Millisecond)
+		ok := false
+		for _, c := range cmp.Cities {
+			if c.Info == nil {
+				if *brief {
+					fmt.Fprintf(os.Stderr, "%sError:%s %s: %s\n", red+bold, reset, c.Query, c.Err)
+				}
+				continue
+			}
+			ok = true
+			if *notifyFlag {
+				if err := notify(tr("Weather")+" · "+c.Info.CityName, briefText(c.Info)); err != nil {
+					fmt.Fprintf(os.Stderr, "\n  %sWarning:%s notification not sent: %v\n", yellow+bold, reset, err)
+				}
+			}
+			if *brief {
+				printBrief(c.Info)
+				fmt.Println()
+			}
+		}
+		if !*brief {
+			printCompare(cmp)
+		}
+		if !ok {
+			os.Exit(1)
+		}
+		return
+	}
+
 	info, err := client.GetWeather(city, *units)
 	close(done)
 	time.Sleep(20 * time.Millisecond) // let spinner goroutine clear line
@@ -231,20 +528,51 @@ func main() {
 		fmt.Fprintf(os.Stderr, "  %sError:%s %v\n\n", red+bold, reset, err)
 		os.Exit(1)
 	}
+	if *notifyFlag {
+		if err := notify(tr("Weather")+" · "+info.CityName, briefText(info)); err != nil {
+			fmt.Fprintf(os.Stderr, "\n  %sWarning:%s notification not sent: %v\n", yellow+bold, reset, err)
+		}
+	}
+	if *brief {
+		printBrief(info)
+		return
+	}
+
+	d.print(info)
+}
+
+// dashboard holds what the full forecast view is drawn with besides the
+// forecast itself.
+type dashboard struct {
+	units   string
+	quotes  *weather.QuoteBook
+	outfit  weather.OutfitOptions
+	windows []weather.TimeWindow
+	uv      weather.UVOptions
+
+	sections []string // from dashboardSections; none means all
+}
 
+// show reports whether the named section is drawn.
+func (d dashboard) show(section string) bool {
+	return len(d.sections) == 0 || slices.Contains(d.sections, section)
+}
+
+// print draws the full forecast view for info.
+func (d dashboard) print(info *weather.WeatherInfo) {
 	cur := info.Current
-	unitLabel := "Metric"
-	if *units == "imperial" {
-		unitLabel = "Imperial"
+	unitLabel := tr("Metric")
+	if d.units == "imperial" {
+		unitLabel = tr("Imperial")
 	}
 
 	fmt.Println()
 
 	cityUpper := strings.ToUpper(info.CityName) + ", " + strings.ToUpper(info.Country)
-	hLeft := "  WEATHER  —  " + cityUpper
+	hLeft := "  " + strings.ToUpper(tr("Weather")) + "  —  " + cityUpper
 	hRight := unitLabel + "  ●  LIVE"
 	innerW := W - 4
-	hPad := innerW - len(hLeft) + 2 - len(hRight) // +2 for "  " prefix before ║
+	hPad := innerW - utf8.RuneCountInString(hLeft) + 2 - utf8.RuneCountInString(hRight) // +2 for "  " prefix before ║
 	if hPad < 1 {
 		hPad = 1
 	}
@@ -259,84 +587,125 @@ func main() {
 	fmt.Println()
 
 	alerts := weather.Alerts(info)
-	if len(alerts) > 0 {
+	if d.show("alerts") && len(alerts) > 0 {
 		for _, a := range alerts {
 			prefix := alertColor(a.Level) + alertPrefix(a.Level) + reset
-			fmt.Printf("  %s %s\n", prefix, clr(bold, a.Title))
+			title := clr(bold, a.Title)
+			if watch.newAlert(a.Title, a.Title) {
+				title = clr(bold+blink, a.Title) + "  " + clr(bold+yellow, tr("NEW"))
+			}
+			fmt.Printf("  %s %s\n", prefix, title)
 			fmt.Printf("     %s\n", clr(dim, a.Message))
 		}
 		fmt.Println()
 	}
 
-	fmt.Println(topBar("Current Conditions"))
-
-	quote := weather.QuoteFromIcon(cur.Icon)
-	for _, line := range wordWrap(quote, W-8) {
-		fmt.Println(row(clr(italic+dim+magenta, line)))
-	}
-	fmt.Println(blankRow())
-
-	tc := tempColor(cur.Temp, info.TempUnit)
-	fc := tempColor(cur.FeelsLike, info.TempUnit)
-	tempStr := clr(bold+tc, fmt.Sprintf("%.1f%s", cur.Temp, info.TempUnit))
-	feelStr := clr(fc, fmt.Sprintf("%.1f%s", cur.FeelsLike, info.TempUnit))
-	condStr := clr(bold+white, cur.Description)
-	fmt.Println(row(fmt.Sprintf("%-30s  %s %s  %s %s",
-		condStr,
-		clr(dim+cyan, "Temp"), tempStr,
-		clr(dim+cyan, "Feels"), feelStr,
-	)))
-
-	advice := weather.Advice(cur.FeelsLike, info.TempUnit)
-	fmt.Println(row(clr(dim, "  → ") + clr(green, advice)))
-	fmt.Println(blankRow())
-
-	// Stats row 1: Humidity + Cloud Cover
-	humBar := progressBar(cur.Humidity, 14, cyan)
-	cldBar := progressBar(cur.CloudCover, 14, blue)
-	fmt.Println(row(fmt.Sprintf(
-		"%s %s %3d%%    %s %s %3d%%",
-		clr(dim+cyan, "Humidity  "), humBar, cur.Humidity,
-		clr(dim+cyan, "Cloud    "), cldBar, cur.CloudCover,
-	)))
-
-	// Stats row 2: Pressure + Wind (with direction)
-	windDir := weather.WindCompass(cur.WindDir)
-	fmt.Println(row(fmt.Sprintf(
-		"%s %s      %s %s %s",
-		clr(dim+cyan, "Pressure  "), clr(white, fmt.Sprintf("%.0f hPa", cur.Pressure)),
-		clr(dim+cyan, "Wind     "), clr(white, fmt.Sprintf("%.1f %s", cur.WindSpeed, info.WindUnit)),
-		clr(dim+cyan, windDir),
-	)))
-
-	// Stats row 2b: Dew Point + FeelsLike
-	fmt.Println(row(fmt.Sprintf(
-		"%s %s      %s %s",
-		clr(dim+cyan, "Dew Point "), clr(white, fmt.Sprintf("%.1f%s", cur.DewPoint, info.TempUnit)),
-		clr(dim+cyan, "Feels    "), clr(white, fmt.Sprintf("%.1f%s", cur.FeelsLike, info.TempUnit)),
-	)))
-
-	// Stats row 3: UV Index + Updated
-	uvc := uvColor(cur.UVIndex)
-	uvLvl := weather.UVLevel(cur.UVIndex)
-	fmt.Println(row(fmt.Sprintf(
-		"%s %s %s      %s %s",
-		clr(dim+cyan, "UV Index  "),
-		clr(uvc, fmt.Sprintf("%.1f", cur.UVIndex)),
-		clr(uvc, "("+uvLvl+")"),
-		clr(dim+cyan, "Updated  "), clr(dim, cur.Time),
-	)))
-	fmt.Println(row(fmt.Sprintf(
-		"%s %s",
-		clr(dim+cyan, "UV Advice "), clr(dim, weather.UVAdvice(cur.UVIndex)),
-	)))
-
-	fmt.Println(botBar())
-	fmt.Println()
+	if d.show("current") {
+		fmt.Println(topBar(tr("Current Conditions")))
+
+		if quote := d.quotes.For(info); quote != "" {
+			for _, line := range wordWrap(tr(quote), W-8) {
+				fmt.Println(row(clr(italic+dim+magenta, line)))
+			}
+			fmt.Println(blankRow())
+		}
+
+		tc := tempColor(cur.Temp, info.TempUnit)
+		fc := tempColor(cur.FeelsLike, info.TempUnit)
+		tempStr := watch.clr("temp", bold+tc, i18n.Number(lang, cur.Temp, 1)+info.TempUnit)
+		feelStr := watch.clr("feels", fc, i18n.Number(lang, cur.FeelsLike, 1)+info.TempUnit)
+		condStr := watch.clr("cond", bold+white, fmt.Sprintf("%-17s", tr(cur.Description)))
+		fmt.Println(row(fmt.Sprintf("%s  %s %s  %s %s",
+			condStr,
+			clr(dim+cyan, tr("Temp")), tempStr,
+			clr(dim+cyan, tr("Feels")), feelStr,
+		)))
+
+		advice := tr(d.outfit.Comfort.Advice(cur.FeelsLike, info.TempUnit))
+		fmt.Println(row(clr(dim, "  → ") + clr(green, advice)))
+		fmt.Println(blankRow())
+
+		// Stats row 1: Humidity + Cloud Cover
+		humBar := progressBar(cur.Humidity, 14, cyan)
+		cldBar := progressBar(cur.CloudCover, 14, blue)
+		fmt.Println(row(fmt.Sprintf(
+			"%s %s %s    %s %s %s",
+			clr(dim+cyan, fmt.Sprintf("%-10s", tr("Humidity"))), humBar, watch.clr("humidity", "", fmt.Sprintf("%3d%%", cur.Humidity)),
+			clr(dim+cyan, fmt.Sprintf("%-9s", tr("Cloud"))), cldBar, watch.clr("cloud", "", fmt.Sprintf("%3d%%", cur.CloudCover)),
+		)))
+
+		// Stats row 2: Pressure + Wind (with direction)
+		windDir := weather.WindCompass(cur.WindDir)
+		fmt.Println(row(fmt.Sprintf(
+			"%s %s      %s %s %s",
+			clr(dim+cyan, fmt.Sprintf("%-10s", tr("Pressure"))), watch.clr("pressure", white, fmt.Sprintf("%.0f hPa", cur.Pressure)),
+			clr(dim+cyan, fmt.Sprintf("%-9s", tr("Wind"))), watch.clr("wind", white, i18n.Number(lang, cur.WindSpeed, 1)+" "+info.WindUnit),
+			watch.clr("wind dir", dim+cyan, windDir),
+		)))
+
+		// Stats row 2b: Dew Point + FeelsLike
+		fmt.Println(row(fmt.Sprintf(
+			"%s %s      %s %s",
+			clr(dim+cyan, fmt.Sprintf("%-10s", tr("Dew Point"))), watch.clr("dew point", white, i18n.Number(lang, cur.DewPoint, 1)+info.TempUnit),
+			clr(dim+cyan, fmt.Sprintf("%-9s", tr("Feels"))), clr(white, i18n.Number(lang, cur.FeelsLike, 1)+info.TempUnit),
+		)))
+
+		// Stats row 3: UV Index + Updated
+		uvc := uvColor(cur.UVIndex)
+		uvLvl := tr(weather.UVLevel(cur.UVIndex))
+		fmt.Println(row(fmt.Sprintf(
+			"%s %s %s      %s %s",
+			clr(dim+cyan, fmt.Sprintf("%-10s", tr("UV Index"))),
+			watch.clr("uv", uvc, i18n.Number(lang, cur.UVIndex, 1)),
+			clr(uvc, "("+uvLvl+")"),
+			clr(dim+cyan, fmt.Sprintf("%-9s", tr("Updated"))), watch.clr("updated", dim, cur.Time),
+		)))
+		fmt.Println(row(fmt.Sprintf(
+			"%s %s",
+			clr(dim+cyan, fmt.Sprintf("%-10s", tr("UV Advice"))), clr(dim, tr(weather.UVAdvice(cur.UVIndex))),
+		)))
 
-	if info.Sun.SunriseTime != "" {
+		// Heat and cold stress indices, only those defined for the conditions.
+		var stress []string
+		for _, x := range cur.Stress.All() {
+			if !x.Applies {
+				continue
+			}
+			val := fmt.Sprintf("%.1f%s", x.In(info.TempUnit), info.TempUnit)
+			if x.Name == "Humidex" {
+				val = fmt.Sprintf("%.0f", x.Value)
+			}
+			sc := stressColor(x.Level)
+			stress = append(stress, fmt.Sprintf("%s %s %s",
+				clr(dim+cyan, fmt.Sprintf("%-10s", x.Name)),
+				clr(sc, fmt.Sprintf("%-7s", val)),
+				clr(sc, fmt.Sprintf("%-16s", tr(x.Label))),
+			))
+		}
+		for i := 0; i < len(stress); i += 2 {
+			fmt.Println(row(strings.Join(stress[i:min(i+2, len(stress))], "  ")))
+		}
+		if worst := cur.Stress.Worst(); worst.Level > comfort.None {
+			for j, line := range wordWrap(tr(worst.Guidance), W-20) {
+				label := "          "
+				if j == 0 {
+					label = "Work/Rest "
+				}
+				fmt.Println(row(clr(dim+cyan, label) + " " + clr(stressColor(worst.Level), line)))
+			}
+		}
+
+		fmt.Println(botBar())
+		fmt.Println()
+	}
+
+	if d.show("daylight") && info.Sun.SunriseTime != "" {
 		arcWidth := W - 22 // leave room for sunrise/sunset labels
-		fmt.Println(topBar("Daylight  " + info.Sun.DaylightHours))
+		title := "Daylight  " + info.Sun.DaylightHours
+		if info.Sun.Polar != "" {
+			title += "  ·  " + info.Sun.Polar
+		}
+		fmt.Println(topBar(title))
 
 		arcLine := clr(yellow, "☀ "+info.Sun.SunriseTime) +
 			"  " + sunLine(info.Sun, arcWidth) +
@@ -357,30 +726,62 @@ func main() {
 			)))
 		}
 
+		// Twilights and the photographers' golden and blue hours.
+		st := info.Sun.Times
+		fmt.Println(blankRow())
+		fmt.Println(row(fmt.Sprintf("%s %-13s  %s %s",
+			clr(dim+cyan, "Solar noon"), clr(white, astro.Clock(st.SolarNoon)),
+			clr(dim+cyan, "Civil     "), clr(white, astro.Clock(st.CivilDawn)+"–"+astro.Clock(st.CivilDusk)),
+		)))
+		fmt.Println(row(fmt.Sprintf("%s %-13s  %s %s",
+			clr(dim+cyan, "Nautical  "), clr(white, astro.Clock(st.NauticalDawn)+"–"+astro.Clock(st.NauticalDusk)),
+			clr(dim+cyan, "Astronom. "), clr(white, astro.Clock(st.AstroDawn)+"–"+astro.Clock(st.AstroDusk)),
+		)))
+		fmt.Println(row(fmt.Sprintf("%s %s  %s",
+			clr(dim+cyan, "Golden hr "), clr(yellow, st.GoldenMorning.String()), clr(yellow, st.GoldenEvening.String()),
+		)))
+		fmt.Println(row(fmt.Sprintf("%s %s  %s",
+			clr(dim+cyan, "Blue hour "), clr(blue, st.BlueMorning.String()), clr(blue, st.BlueEvening.String()),
+		)))
+
 		fmt.Println(botBar())
 		fmt.Println()
 	}
 
-	if info.Sun.MoonPhaseName != "" {
+	if x := d.uv.Report(info); x != nil && d.show("uv") {
+		printUVExposure(x)
+	}
+
+	if d.show("moon") && info.Sun.MoonPhaseName != "" {
 		moonIcon := moonPhaseIcon(info.Sun.MoonPhase)
-		illumPct := 0
-		if info.Sun.MoonPhase > 0.5 {
-			illumPct = int((1 - info.Sun.MoonPhase) * 100 * 2)
-		} else {
-			illumPct = int(info.Sun.MoonPhase * 100 * 2)
-		}
-		fmt.Println(topBar("Moon Phase"))
+		illumPct := int(math.Round(info.Sun.MoonIllum * 100))
+		fmt.Println(topBar(tr("Moon Phase")))
 		fmt.Println(row(fmt.Sprintf(
 			"%s  %s  %s",
-			clr(bold+"\033[94m", moonIcon),
-			clr(bold+white, info.Sun.MoonPhaseName),
-			clr(dim, fmt.Sprintf("(%d%% illuminated)", illumPct)),
+			clr(bold+skyBlue, moonIcon),
+			clr(bold+white, tr(info.Sun.MoonPhaseName)),
+			clr(dim, i18n.Sprintf(lang, "(%d%% illuminated)", illumPct)),
 		)))
+		mt := info.Sun.Moon
+		switch {
+		case mt.AlwaysUp:
+			fmt.Println(row(clr(dim, "Above the horizon all day")))
+		case mt.AlwaysDown:
+			fmt.Println(row(clr(dim, "Below the horizon all day")))
+		default:
+			fmt.Println(row(fmt.Sprintf("%s %s      %s %s",
+				clr(dim+cyan, "Moonrise  "), clr(white, astro.Clock(mt.Rise)),
+				clr(dim+cyan, "Moonset  "), clr(white, astro.Clock(mt.Set)),
+			)))
+		}
 		fmt.Println(botBar())
 		fmt.Println()
 	}
-	if len(info.Hourly) > 0 {
-		fmt.Println(topBar("Next 24 Hours"))
+	if d.show("photo") && len(info.Photo) > 0 {
+		printPhotoPlan(info.Photo)
+	}
+	if d.show("hourly") && len(info.Hourly) > 0 {
+		fmt.Println(topBar(tr("Next 24 Hours")))
 
 		// Sparkline: map temperatures to block characters
 		sparkChars := []rune{'▁', '▂', '▃', '▄', '▅', '▆', '▇', '█'}
@@ -414,10 +815,10 @@ func main() {
 		fmt.Println(blankRow())
 
 		// Compact table: every 3 hours (8 rows)
-		fmt.Println(row(fmt.Sprintf("%-5s  %-14s  %5s  %-12s  %s",
-			clr(dim, "TIME"), clr(dim, "CONDITION"),
-			clr(dim, "TEMP"), clr(dim, "RAIN"),
-			clr(dim, "WIND"),
+		fmt.Println(row(fmt.Sprintf("%-5s  %-14s  %5s  %5s  %-12s  %s",
+			clr(dim, tr("TIME")), clr(dim, tr("CONDITION")),
+			clr(dim, tr("TEMP")), clr(dim, "±"), clr(dim, tr("RAIN")),
+			clr(dim, tr("WIND")),
 		)))
 		fmt.Println(row(strings.Repeat("─", W-10)))
 
@@ -427,18 +828,16 @@ func main() {
 			}
 			tc := tempColor(h.Temp, info.TempUnit)
 			pBars := h.PrecipProb / 10
-			pBar := clr("\033[34m", strings.Repeat("█", pBars)) +
+			pBar := clr(blue, strings.Repeat("█", pBars)) +
 				clr(dim, strings.Repeat("░", 10-pBars))
-			cond := h.Description
-			if len(cond) > 14 {
-				cond = cond[:13] + "…"
-			}
-			fmt.Println(row(fmt.Sprintf("%-5s  %-14s  %s  %s %s  %s",
+			cond := clip(tr(h.Description), 14)
+			fmt.Println(row(fmt.Sprintf("%-5s  %-14s  %s %s  %s %s  %s",
 				clr(bold, h.Time),
 				clr(dim, cond),
-				clr(tc, fmt.Sprintf("%4.0f%s", h.Temp, info.TempUnit)),
+				watch.clr("hour "+h.Time+" temp", tc, fmt.Sprintf("%4.0f%s", h.Temp, info.TempUnit)),
+				bandStr(h.TempBand),
 				pBar,
-				clr("\033[34m", fmt.Sprintf("%3d%%", h.PrecipProb)),
+				watch.clr("hour "+h.Time+" rain", blue, fmt.Sprintf("%3d%%", h.PrecipProb)),
 				clr(blue, fmt.Sprintf("%.0f %s", h.WindSpeed, info.WindUnit)),
 			)))
 		}
@@ -447,49 +846,53 @@ func main() {
 		fmt.Println()
 	}
 
-	fmt.Println(topBar("5-Day Forecast"))
-	fmt.Println(row(fmt.Sprintf("%-10s  %-15s  %5s  %5s  %8s  %-12s",
-		clr(dim, "DATE"),
-		clr(dim, "CONDITION"),
-		clr(dim, "HI"),
-		clr(dim, "LO"),
-		clr(dim, "WIND"),
-		clr(dim, "RAIN"),
-	)))
-	fmt.Println(row(strings.Repeat("─", W-10)))
-
-	for _, day := range info.Forecast {
-		htc := tempColor(day.TempMax, info.TempUnit)
-		ltc := tempColor(day.TempMin, info.TempUnit)
-		hiStr := clr(htc, fmt.Sprintf("%4.0f%s", day.TempMax, info.TempUnit))
-		loStr := clr(ltc, fmt.Sprintf("%4.0f%s", day.TempMin, info.TempUnit))
-		wdStr := clr(blue, fmt.Sprintf("%5.0f %s", day.WindMax, info.WindUnit))
-
-		pBars := day.PrecipProb / 10
-		pBar := clr("\033[34m", strings.Repeat("█", pBars)) +
-			clr(dim, strings.Repeat("░", 10-pBars))
-		pctStr := clr("\033[34m", fmt.Sprintf("%3d%%", day.PrecipProb))
-
-		cond := day.Description
-		if len(cond) > 15 {
-			cond = cond[:14] + "…"
-		}
-
-		fmt.Println(row(fmt.Sprintf("%-10s  %-15s  %s  %s  %s  %s %s",
-			clr(bold, day.Date),
-			clr(dim, cond),
-			hiStr, loStr, wdStr,
-			pBar, pctStr,
+	if d.show("forecast") {
+		fmt.Println(topBar(tr("5-Day Forecast")))
+		fmt.Println(row(fmt.Sprintf("%-10s  %-15s  %11s  %11s  %8s  %-12s",
+			clr(dim, tr("DATE")),
+			clr(dim, tr("CONDITION")),
+			clr(dim, tr("HI ±")),
+			clr(dim, tr("LO ±")),
+			clr(dim, tr("WIND")),
+			clr(dim, tr("RAIN")),
 		)))
-	}
+		fmt.Println(row(strings.Repeat("─", W-10)))
 
-	fmt.Println(botBar())
-	fmt.Println()
+		for _, day := range info.Forecast {
+			htc := tempColor(day.TempMax, info.TempUnit)
+			ltc := tempColor(day.TempMin, info.TempUnit)
+			hiStr := watch.clr("day "+day.Date+" hi", htc, fmt.Sprintf("%4.0f%s", day.TempMax, info.TempUnit)) + bandStr(day.TempMaxBand)
+			loStr := watch.clr("day "+day.Date+" lo", ltc, fmt.Sprintf("%4.0f%s", day.TempMin, info.TempUnit)) + bandStr(day.TempMinBand)
+			wdStr := watch.clr("day "+day.Date+" wind", blue, fmt.Sprintf("%5.0f %s", day.WindMax, info.WindUnit))
+
+			pBars := day.PrecipProb / 10
+			pBar := clr(blue, strings.Repeat("█", pBars)) +
+				clr(dim, strings.Repeat("░", 10-pBars))
+			pctStr := watch.clr("day "+day.Date+" rain", blue, fmt.Sprintf("%3d%%", day.PrecipProb))
 
-	outfit := info.Outfit
-	if len(outfit.Items) > 0 {
-		fmt.Println(topBar("What to Wear"))
+			cond := clip(tr(day.Description), 15)
+			date := day.Date
+			if d, err := time.Parse("2006-01-02", day.Date); err == nil {
+				date = i18n.Date(lang, d, "Mon 2 Jan")
+			}
+
+			fmt.Println(row(fmt.Sprintf("%-10s  %-15s  %s  %s  %s  %s %s",
+				clr(bold, date),
+				clr(dim, cond),
+				hiStr, loStr, wdStr,
+				pBar, pctStr,
+			)))
+		}
+
+		fmt.Println(botBar())
+		fmt.Println()
+	}
+
+	outfit := d.outfit.Build(info)
+	if d.show("outfit") && len(outfit.Items) > 0 {
+		fmt.Println(topBar(tr("What to Wear") + " · " + outfit.Activity))
 		fmt.Println(row(clr(dim+cyan, outfit.Headline)))
+		fmt.Println(row(suitabilityLine(outfit.Suitability)))
 		fmt.Println(row(strings.Repeat("─", W-10)))
 		for _, item := range outfit.Items {
 			emoji := outfitEmoji(item.Icon)
@@ -503,28 +906,52 @@ func main() {
 		fmt.Println()
 	}
 
-	if info.Consensus != nil && info.Consensus.AvailCount > 0 {
+	if d.show("commute") {
+		printCommute(info, weather.CommuteOutfits(info, d.windows, d.outfit))
+	}
+
+	if d.show("consensus") && info.Consensus != nil && info.Consensus.AvailCount > 0 {
 		cons := info.Consensus
-		fmt.Println(topBar("Model Consensus"))
+		fmt.Println(topBar(tr("Model Consensus")))
 
-		agreeClr := green
-		if cons.AgreePct < 50 {
-			agreeClr = red
-		} else if cons.AgreePct < 80 {
-			agreeClr = yellow
-		}
+		agreeClr := agreePctColor(cons.AgreePct)
 
 		fmt.Println(row(fmt.Sprintf(
-			"%s %s    %s %s    %s %s",
-			clr(dim+cyan, "Agreement"),
-			clr(agreeClr+bold, fmt.Sprintf("%s (%d%%)", cons.Agreement, cons.AgreePct)),
-			clr(dim+cyan, "Avg"),
-			clr(white, fmt.Sprintf("%.1f%s", cons.AvgTemp, info.TempUnit)),
-			clr(dim+cyan, "Spread"),
-			clr(agreeClr, fmt.Sprintf("%.1f%s", cons.Spread, info.TempUnit)),
+			"%s %s    %s %s",
+			clr(dim+cyan, fmt.Sprintf("%-10s", tr("Agreement"))),
+			clr(agreeClr+bold, fmt.Sprintf("%s (%d%%)", tr(cons.Agreement), cons.AgreePct)),
+			clr(dim+cyan, fmt.Sprintf("%-9s", tr("Condition"))),
+			clr(white, fmt.Sprintf("%s (%d/%d)", tr(cons.Weather.Description), cons.Weather.Votes, cons.AvailCount)),
 		)))
 		fmt.Println(blankRow())
 
+		// Per-variable statistics table
+		fmt.Println(row(fmt.Sprintf("%-9s  %8s  %8s  %6s  %-15s  %s",
+			clr(dim, "VARIABLE"), clr(dim, strings.ToUpper(string(cons.Method))), clr(dim, "MEDIAN"),
+			clr(dim, "SD"), clr(dim, "RANGE"), clr(dim, "AGREE"),
+		)))
+		fmt.Println(row(strings.Repeat("─", W-10)))
+		for _, v := range []struct {
+			label string
+			stats weather.VarStats
+			unit  string
+		}{
+			{"Temp", cons.Temp, info.TempUnit},
+			{"Humidity", cons.Humidity, "%"},
+			{"Wind", cons.Wind, " " + info.WindUnit},
+			{"Pressure", cons.Pressure, " hPa"},
+		} {
+			fmt.Println(row(fmt.Sprintf("%-9s  %8s  %8s  %6s  %-15s  %s",
+				clr(cyan, v.label),
+				clr(white, fmt.Sprintf("%.1f", v.stats.Value)),
+				fmt.Sprintf("%.1f", v.stats.Median),
+				fmt.Sprintf("%.1f", v.stats.StdDev),
+				clr(dim, fmt.Sprintf("%.1f–%.1f%s", v.stats.Min, v.stats.Max, v.unit)),
+				clr(agreePctColor(v.stats.AgreePct), fmt.Sprintf("%3d%%", v.stats.AgreePct)),
+			)))
+		}
+		fmt.Println(blankRow())
+
 		barW := 22
 		for _, r := range cons.Models {
 			name := fmt.Sprintf("%-14s", r.Model)
@@ -532,9 +959,13 @@ func main() {
 				fmt.Println(row(clr(dim, name+"  unavailable")))
 				continue
 			}
+			if r.Excluded {
+				fmt.Println(row(clr(dim, fmt.Sprintf("  %s  %.1f%s  excluded — %s", name, r.Temp, info.TempUnit, r.Reason))))
+				continue
+			}
 			var filled int
-			if cons.Spread > 0.1 {
-				filled = int((r.Temp-cons.MinTemp)/cons.Spread*float64(barW-2)) + 1
+			if cons.Temp.Spread > 0.1 {
+				filled = int((r.Temp-cons.Temp.Min)/cons.Temp.Spread*float64(barW-2)) + 1
 			} else {
 				filled = barW / 2
 			}
@@ -546,19 +977,55 @@ func main() {
 			}
 			bar := clr(green, strings.Repeat("█", filled)) +
 				clr(dim, strings.Repeat("░", barW-filled))
-			fmt.Println(row(fmt.Sprintf("  %s  [%s]  %s",
+			note := ""
+			if r.Discounted() {
+				note = "  " + clr(dim, r.Reason)
+			}
+			fmt.Println(row(fmt.Sprintf("  %s  [%s]  %s%s",
 				clr(cyan, name),
 				bar,
 				clr(white, fmt.Sprintf("%.1f%s", r.Temp, info.TempUnit)),
+				note,
 			)))
 		}
 
 		fmt.Println(blankRow())
 		fmt.Println(row(fmt.Sprintf("%s  %.1f%s — %.1f%s",
 			clr(dim+cyan, "Range            "),
-			cons.MinTemp, info.TempUnit,
-			cons.MaxTemp, info.TempUnit,
+			cons.Temp.Min, info.TempUnit,
+			cons.Temp.Max, info.TempUnit,
+		)))
+		fmt.Println(botBar())
+		fmt.Println()
+	}
+
+	if ens := info.Ensemble; d.show("ensemble") && ens != nil && len(ens.Probabilities) > 0 {
+		fmt.Println(topBar("Ensemble Outlook"))
+		fmt.Println(row(clr(dim, fmt.Sprintf("%d members · %s", ens.Members, strings.Join(ens.Models, ", ")))))
+		fmt.Println(blankRow())
+
+		for _, p := range ens.Probabilities {
+			label := fmt.Sprintf("P(%s) %s", p.Label, p.When)
+			fmt.Println(row(fmt.Sprintf("%-28s  %s  %s",
+				clr(cyan, label),
+				progressBar(p.Pct, 20, blue),
+				clr(bold+white, fmt.Sprintf("%3d%%", p.Pct)),
+			)))
+		}
+		fmt.Println(blankRow())
+
+		fmt.Println(row(fmt.Sprintf("%-10s  %-14s  %-14s  %s",
+			clr(dim, "DATE"), clr(dim, "HI P10–P90"), clr(dim, "LO P10–P90"), clr(dim, "RAIN P50 (P90)"),
 		)))
+		fmt.Println(row(strings.Repeat("─", W-10)))
+		for _, d := range ens.Daily {
+			fmt.Println(row(fmt.Sprintf("%-10s  %-14s  %-14s  %s",
+				clr(bold, d.Date),
+				clr(tempColor(d.TempMax.P50, info.TempUnit), fmt.Sprintf("%.0f–%.0f%s", d.TempMax.P10, d.TempMax.P90, info.TempUnit)),
+				clr(tempColor(d.TempMin.P50, info.TempUnit), fmt.Sprintf("%.0f–%.0f%s", d.TempMin.P10, d.TempMin.P90, info.TempUnit)),
+				clr(blue, fmt.Sprintf("%.1f mm (%.1f)", d.PrecipSum.P50, d.PrecipSum.P90)),
+			)))
+		}
 		fmt.Println(botBar())
 		fmt.Println()
 	}
@@ -567,23 +1034,41 @@ func main() {
 	fmt.Println()
 }
 
+// suitabilityLine renders "Suitability ████░░ 72/100 Good · wind 35 km/h".
+func suitabilityLine(s weather.Suitability) string {
+	line := fmt.Sprintf("%s %s %s",
+		clr(dim+cyan, tr("Suitability")),
+		progressBar(s.Score, 16, agreePctColor(s.Score)),
+		clr(agreePctColor(s.Score)+bold, fmt.Sprintf("%d/100 %s", s.Score, tr(s.Label))),
+	)
+	if len(s.Reasons) > 0 {
+		line += clr(dim, " · "+strings.Join(s.Reasons, ", "))
+	}
+	return line
+}
+
 func outfitEmoji(icon string) string {
 	m := map[string]string{
-		"thermal":    "[~~]",
-		"sweater":    "[\\~/]",
-		"longsleeve": "[>--]",
-		"tshirt":     "[T]  ",
-		"coat":       "[|||]",
-		"jacket":     "[/|\\]",
-		"windbreaker":"[>>>]",
-		"umbrella":   " ( ) ",
-		"raincoat":   "[:::]",
-		"sunscreen":  "[SPF]",
-		"sunglasses": "(_8_)",
-		"beanie":     "[^^^]",
-		"hat":        "[ ^ ]",
-		"boots":      "[|||]",
-		"sandals":    "[ _ ]",
+		"thermal":     "[~~]",
+		"sweater":     "[\\~/]",
+		"longsleeve":  "[>--]",
+		"tshirt":      "[T]  ",
+		"coat":        "[|||]",
+		"jacket":      "[/|\\]",
+		"windbreaker": "[>>>]",
+		"umbrella":    " ( ) ",
+		"raincoat":    "[:::]",
+		"sunscreen":   "[SPF]",
+		"sunglasses":  "(_8_)",
+		"beanie":      "[^^^]",
+		"hat":         "[ ^ ]",
+		"boots":       "[|||]",
+		"sandals":     "[ _ ]",
+		"hivis":       "[/!\\]",
+		"gloves":      "[www]",
+		"lights":      "(*) ",
+		"bottle":      "[ U ]",
+		"socks":       "[~~~]",
 	}
 	if e, ok := m[icon]; ok {
 		return e
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"WeatherApp/i18n"
+	"WeatherApp/weather"
+)
+
+// notify shows a desktop notification: osascript on macOS, notify-send
+// (libnotify) elsewhere.
+func notify(title, body string) error {
+	switch runtime.GOOS {
+	case "darwin":
+		script := fmt.Sprintf("display notification %q with title %q", body, title)
+		return exec.Command("osascript", "-e", script).Run()
+	case "windows":
+		return errors.New("desktop notifications are not supported on Windows")
+	}
+	if _, err := exec.LookPath("notify-send"); err != nil {
+		return errors.New("notify-send not found (install libnotify)")
+	}
+	return exec.Command("notify-send", "--app-name=weather-cli", title, body).Run()
+}
+
+// briefText is the narrative with any alert titles after it, as used for
+// -brief and notifications.
+func briefText(info *weather.WeatherInfo) string {
+	text := weather.Narrative(info)
+	if alerts := weather.Alerts(info); len(alerts) > 0 {
+		titles := make([]string, len(alerts))
+		for i, a := range alerts {
+			titles[i] = a.Title
+		}
+		text += " " + i18n.Sprintf(lang, "Alerts: %s.", strings.Join(titles, ", "))
+	}
+	return text
+}
+
+// printBrief is the -brief output: a line for the place and current
+// conditions, then the narrative.
+func printBrief(info *weather.WeatherInfo) {
+	cur := info.Current
+	fmt.Printf("%s · %s, %s\n",
+		clr(bold+white, info.CityName+", "+info.Country),
+		clr(bold+tempColor(cur.Temp, info.TempUnit), fmt.Sprintf("%.0f%s", cur.Temp, info.TempUnit)),
+		tr(cur.Description))
+	for _, line := range wordWrap(briefText(info), W) {
+		fmt.Println(line)
+	}
+}
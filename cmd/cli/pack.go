@@ -0,0 +1,107 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"WeatherApp/weather"
+)
+
+// runPack implements `weather-cli pack City:FROM[:TO] ...`: a forecast for
+// each leg of the trip and one packing list for all of it.
+func runPack(args []string) {
+	fs := flag.NewFlagSet("pack", flag.ExitOnError)
+	units := fs.String("units", cfg.unitsOr("metric"), "Units: metric (°C/km·h) or imperial (°F/mph)")
+	activityFlag := fs.String("activity", "walk", "Outfit profile: "+strings.Join(weather.ActivityKeys(), ", "))
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: weather-cli pack [flags] City:YYYY-MM-DD[:YYYY-MM-DD] ...")
+		fmt.Fprintln(os.Stderr, "  e.g. weather-cli pack Berlin:2026-10-20:2026-10-22 Lisbon:2026-10-23:2026-10-25")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	fail := func(err error) {
+		fmt.Fprintf(os.Stderr, "\n  %sError:%s %v\n\n", red+bold, reset, err)
+		os.Exit(1)
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		os.Exit(2)
+	}
+	legs, err := weather.ParseItinerary(strings.Join(fs.Args(), ";"))
+	if err != nil {
+		fail(err)
+	}
+	activity, err := weather.ParseActivity(*activityFlag)
+	if err != nil {
+		fail(err)
+	}
+	opts := weather.OutfitOptions{Activity: activity}
+	if profile, err := weather.LoadComfortProfile(comfortPath()); err == nil {
+		opts.Comfort = profile
+	}
+
+	fmt.Println()
+	done := startSpinner(fmt.Sprintf("Fetching forecasts for %d stop(s) ...", len(legs)))
+	plan := weather.NewClient().PlanTrip(legs, *units, opts)
+	close(done)
+	time.Sleep(20 * time.Millisecond) // let spinner goroutine clear line
+	fmt.Println()
+
+	for _, lf := range plan.Legs {
+		title := lf.Leg.City
+		if lf.Country != "" {
+			title += ", " + lf.Country
+		}
+		fmt.Println(topBar(fmt.Sprintf("%s  ·  %s → %s", title,
+			lf.Leg.From.Format("Mon 2 Jan"), lf.Leg.To.Format("Mon 2 Jan"))))
+		if lf.Err != "" {
+			fmt.Println(row(clr(red, lf.Err)))
+			fmt.Println(botBar())
+			fmt.Println()
+			continue
+		}
+		fmt.Println(row(fmt.Sprintf("%-10s  %-20s  %6s  %6s  %5s  %4s",
+			clr(dim, "DAY"), clr(dim, "CONDITIONS"), clr(dim, "HI"), clr(dim, "LO"), clr(dim, "RAIN"), clr(dim, "UV"))))
+		for _, d := range lf.Days {
+			cond := d.Description
+			if len([]rune(cond)) > 20 {
+				cond = string([]rune(cond)[:19]) + "…"
+			}
+			fmt.Println(row(fmt.Sprintf("%-10s  %-20s  %s  %s  %s  %s",
+				clr(bold, d.Weekday+" "+d.Date[8:]),
+				cond,
+				clr(tempColor(d.TempMax, plan.TempUnit), fmt.Sprintf("%5.0f%s", d.TempMax, plan.TempUnit)),
+				clr(tempColor(d.TempMin, plan.TempUnit), fmt.Sprintf("%5.0f%s", d.TempMin, plan.TempUnit)),
+				clr(blue, fmt.Sprintf("%4d%%", d.PrecipProb)),
+				clr(uvColor(d.UVMax), fmt.Sprintf("%4.0f", d.UVMax)),
+			)))
+		}
+		if len(lf.Missing) > 0 {
+			fmt.Println(row(clr(dim, fmt.Sprintf("No forecast yet for %d day(s) — beyond the 16-day range", len(lf.Missing)))))
+		}
+		fmt.Println(botBar())
+		fmt.Println()
+	}
+
+	fmt.Println(topBar(fmt.Sprintf("Packing List · %d days · %s", plan.Days, activity.Name)))
+	if len(plan.Items) == 0 {
+		fmt.Println(row(clr(dim, "Nothing to plan — no trip day has a forecast yet.")))
+	}
+	for _, it := range plan.Items {
+		qty := "   "
+		if it.Qty > 0 {
+			qty = fmt.Sprintf("%2d×", it.Qty)
+		}
+		line := fmt.Sprintf("%s  %s  %s", outfitEmoji(it.Icon), clr(bold+white, qty), it.Name)
+		if it.Reason != "" {
+			line += "  " + clr(dim, it.Reason)
+		}
+		fmt.Println(row(line))
+	}
+	fmt.Println(botBar())
+	fmt.Println()
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"fmt"
+
+	"WeatherApp/astro"
+	"WeatherApp/weather"
+)
+
+// photoColor grades a light-quality score.
+func photoColor(score int) string {
+	switch {
+	case score >= 80:
+		return magenta + bold
+	case score >= 60:
+		return orange
+	case score >= 40:
+		return yellow
+	}
+	return dim
+}
+
+// printPhotoPlan renders the Photography box: per day, the sunrise and
+// sunset bearings, then each golden and blue hour with the sun's direction
+// and a light-quality score from the cloud layers. The best window still
+// ahead on each day is starred.
+func printPhotoPlan(days []weather.PhotoDay) {
+	fmt.Println(topBar("Photography · golden & blue hours"))
+	for i, d := range days {
+		if i > 0 {
+			fmt.Println(blankRow())
+		}
+		line := clr(bold+white, fmt.Sprintf("%-7s", d.Label))
+		if d.Sunrise.IsZero() && d.Sunset.IsZero() {
+			line += clr(dim, "  no sunrise or sunset")
+		} else {
+			line += fmt.Sprintf("  %s %s %s   %s %s %s",
+				clr(yellow, "↑"), clr(white, astro.Clock(d.Sunrise)), clr(dim, fmt.Sprintf("%.0f°", d.SunriseAz)),
+				clr(orange, "↓"), clr(white, astro.Clock(d.Sunset)), clr(dim, fmt.Sprintf("%.0f°", d.SunsetAz)))
+		}
+		fmt.Println(row(line))
+		best := d.Best()
+		for j := range d.Windows {
+			w := &d.Windows[j]
+			kindColor := blue
+			if w.Kind == "Golden hour" {
+				kindColor = yellow
+			}
+			mark := " "
+			if w == best && w.Score >= 40 {
+				mark = clr(yellow+bold, "★")
+			}
+			text := fmt.Sprintf("%-7s %-11s %-11s %-2s %3.0f°  ", w.Session, w.Kind, w.Span, w.Direction, w.AzimuthAt)
+			if w.Past {
+				fmt.Println(row(clr(dim, "  "+text+"past")))
+				continue
+			}
+			fmt.Println(row(fmt.Sprintf("%s %s%s %s %s",
+				mark, clr(kindColor, text),
+				progressBar(w.Score, 8, photoColor(w.Score)),
+				clr(photoColor(w.Score), fmt.Sprintf("%3d", w.Score)),
+				clr(dim, fmt.Sprintf("%-5s low %d%% · mid %d%% · high %d%%", w.Rating, w.CloudLow, w.CloudMid, w.CloudHigh)),
+			)))
+		}
+	}
+	fmt.Println(botBar())
+	fmt.Println()
+}
@@ -0,0 +1,9 @@
+//go:build !unix
+
+package main
+
+import "os"
+
+// notifyResize does nothing where there is no SIGWINCH; the TUI keeps its
+// starting size.
+func notifyResize(c chan<- os.Signal) {}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// screen is the TUI's frame buffer: one string per terminal row, colors
+// included. The terminal gets it through flush; tests drive the TUI with
+// keys and read the frame back as plain text with Text or Line.
+type screen struct {
+	w, h  int
+	lines []string
+}
+
+func newScreen(w, h int) *screen {
+	s := &screen{}
+	s.resize(w, h)
+	return s
+}
+
+// resize changes the size and blanks the frame.
+func (s *screen) resize(w, h int) {
+	s.w, s.h = w, h
+	s.lines = make([]string, h)
+}
+
+// clear blanks every row.
+func (s *screen) clear() {
+	for i := range s.lines {
+		s.lines[i] = ""
+	}
+}
+
+// set puts line on row y, cut to the screen width. Rows off the screen are
+// ignored so callers needn't check the height.
+func (s *screen) set(y int, line string) {
+	if y < 0 || y >= s.h {
+		return
+	}
+	s.lines[y] = clipANSI(line, s.w)
+}
+
+// Line is row y without colors or trailing spaces.
+func (s *screen) Line(y int) string {
+	if y < 0 || y >= s.h {
+		return ""
+	}
+	return strings.TrimRight(stripANSI(s.lines[y]), " ")
+}
+
+// Text is the whole frame as plain text, one row per line.
+func (s *screen) Text() string {
+	rows := make([]string, s.h)
+	for y := range rows {
+		rows[y] = s.Line(y)
+	}
+	return strings.TrimRight(strings.Join(rows, "\n"), "\n") + "\n"
+}
+
+// flush redraws the terminal from the top-left corner, clearing the rest of
+// each row so a shorter line leaves nothing behind.
+func (s *screen) flush(out io.Writer) {
+	var b strings.Builder
+	b.WriteString("\033[H")
+	for y, line := range s.lines {
+		b.WriteString(line + reset + "\033[K")
+		if y < s.h-1 {
+			b.WriteString("\r\n")
+		}
+	}
+	fmt.Fprint(out, b.String())
+}
+
+// stripANSI drops color escape sequences.
+func stripANSI(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\033' {
+			i = escapeEnd(s, i)
+			continue
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
+
+// padANSI pads s with spaces to n visible characters; fmt's widths count
+// the escape sequences too.
+func padANSI(s string, n int) string {
+	return s + strings.Repeat(" ", max(0, n-utf8.RuneCountInString(stripANSI(s))))
+}
+
+// clipANSI cuts s to n visible characters, keeping its escape sequences.
+func clipANSI(s string, n int) string {
+	var b strings.Builder
+	visible := 0
+	for i := 0; i < len(s); {
+		if s[i] == '\033' {
+			end := escapeEnd(s, i)
+			b.WriteString(s[i : end+1])
+			i = end + 1
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if visible == n {
+			i += size
+			continue
+		}
+		b.WriteRune(r)
+		visible++
+		i += size
+	}
+	return b.String()
+}
+
+// escapeEnd is the index of the last byte of the escape sequence at s[i]:
+// the final letter of "\033[...m" and the like.
+func escapeEnd(s string, i int) int {
+	if i+1 >= len(s) || s[i+1] != '[' {
+		return i
+	}
+	for j := i + 2; j < len(s); j++ {
+		if s[j] >= 0x40 && s[j] <= 0x7e {
+			return j
+		}
+	}
+	return len(s) - 1
+}
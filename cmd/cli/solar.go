@@ -0,0 +1,117 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"WeatherApp/weather"
+)
+
+// runSolar implements `weather-cli solar [City]`: the rooftop PV output
+// forecast, hour by hour and day by day.
+func runSolar(args []string) {
+	def := weather.DefaultSolarSystem
+	fs := flag.NewFlagSet("solar", flag.ExitOnError)
+	cityFlag := fs.String("city", "", "City name (or first positional argument)")
+	kwp := fs.Float64("kwp", def.KWp, "System size, kWp")
+	tilt := fs.Float64("tilt", def.Tilt, "Panel tilt, degrees from horizontal")
+	azimuth := fs.Float64("azimuth", def.Azimuth, "Direction the panels face, degrees from north (180 = south; negative faces the equator)")
+	losses := fs.Float64("losses", def.Losses, "System losses, %")
+	days := fs.Int("days", 7, fmt.Sprintf("Days to forecast (1–%d)", weather.MaxSolarDays-1))
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: weather-cli solar [flags] [City]")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	city := *cityFlag
+	if city == "" {
+		if fs.NArg() > 0 {
+			city = strings.Join(fs.Args(), " ")
+		} else {
+			city = cfg.defaultCity()
+		}
+	}
+	city = cfg.resolve(city)
+	sys := weather.SolarSystem{KWp: *kwp, Tilt: *tilt, Azimuth: *azimuth, Losses: *losses}
+
+	fmt.Println()
+	done := startSpinner("Fetching irradiance for " + clr(bold+white, city) + " ...")
+	f, err := weather.NewClient().Solar(city, sys, *days)
+	close(done)
+	time.Sleep(20 * time.Millisecond) // let spinner goroutine clear line
+	fmt.Println()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "  %sError:%s %v\n\n", red+bold, reset, err)
+		os.Exit(1)
+	}
+	printSolar(f)
+}
+
+// solarSpark draws hourly output as block characters scaled to top; hours
+// with no output are dim dots so night and dawn are easy to tell apart.
+func solarSpark(hours []weather.SolarHour, top float64) string {
+	sparkChars := []rune{'▁', '▂', '▃', '▄', '▅', '▆', '▇', '█'}
+	var sb strings.Builder
+	for _, h := range hours {
+		if h.KWh < 0.005 {
+			sb.WriteString(clr(dim, "·"))
+			continue
+		}
+		idx := min(7, max(0, int(h.KWh/top*7+0.5)))
+		sb.WriteString(clr(yellow, string(sparkChars[idx])))
+	}
+	return sb.String()
+}
+
+// printSolar renders the Solar PV box: the next 24 hours, then one row per
+// day with its hourly shape, total and peak.
+func printSolar(f *weather.SolarForecast) {
+	sys := f.System
+	top := f.PeakKW()
+
+	fmt.Println(topBar(fmt.Sprintf("Solar PV · %s, %s", f.City, f.Country)))
+	fmt.Println(row(clr(dim, fmt.Sprintf("%.1f kWp · tilt %.0f° · facing %s · losses %.0f%%",
+		sys.KWp, sys.Tilt, sys.Facing(), sys.Losses))))
+	fmt.Println(blankRow())
+	next := f.Next24()
+	if len(next) > 0 {
+		var kwh float64
+		for _, h := range next {
+			kwh += h.KWh
+		}
+		fmt.Println(row(clr(dim+cyan, "Next 24h  ") + solarSpark(next, top) +
+			clr(dim, fmt.Sprintf("  from %s · ", next[0].Time[11:])) + clr(bold+yellow, fmt.Sprintf("%.1f kWh", kwh))))
+		fmt.Println(row(clr(dim+cyan, "Now       ") + clr(bold+white, fmt.Sprintf("%.2f kW", f.NowKW)) +
+			clr(dim, fmt.Sprintf(" (%.0f%% of rated)", f.NowKW/sys.KWp*100))))
+		fmt.Println(blankRow())
+	}
+
+	best := 0.1
+	for _, d := range f.Days {
+		best = max(best, d.KWh)
+	}
+	fmt.Println(row(clr(dim, fmt.Sprintf("%-9s %-24s  %-6s  %5s  %s", "DAY", "00    06    12    18", "", "KWH", "PEAK"))))
+	for _, d := range f.Days {
+		peak := clr(dim, "—")
+		if d.PeakKW > 0 {
+			peak = fmt.Sprintf("%.2f kW at %s", d.PeakKW, d.PeakAt)
+		}
+		fmt.Println(row(fmt.Sprintf("%s %s  %s  %s  %s",
+			clr(bold, fmt.Sprintf("%-9s", d.Label)),
+			solarSpark(d.Hours, top),
+			progressBar(int(d.KWh/best*100+0.5), 6, yellow),
+			clr(bold+yellow, fmt.Sprintf("%5.1f", d.KWh)),
+			clr(dim, peak),
+		)))
+	}
+	fmt.Println(blankRow())
+	fmt.Println(row(clr(dim+cyan, "Total     ") + clr(bold+yellow, fmt.Sprintf("%.1f kWh", f.TotalKWh)) +
+		clr(dim, fmt.Sprintf(" over %d days · %.1f kWh/kWp a day on average",
+			len(f.Days), f.TotalKWh/float64(max(1, len(f.Days)))/sys.KWp))))
+	fmt.Println(botBar())
+	fmt.Println()
+}
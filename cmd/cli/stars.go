@@ -0,0 +1,121 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"WeatherApp/weather"
+)
+
+// runStars implements `weather-cli stars [City]`: the stargazing outlook for
+// the coming nights and the best night hour by hour.
+func runStars(args []string) {
+	fs := flag.NewFlagSet("stars", flag.ExitOnError)
+	cityFlag := fs.String("city", "", "City name (or first positional argument)")
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: weather-cli stars [flags] [City]")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	city := *cityFlag
+	if city == "" {
+		if fs.NArg() > 0 {
+			city = strings.Join(fs.Args(), " ")
+		} else {
+			city = cfg.defaultCity()
+		}
+	}
+	city = cfg.resolve(city)
+
+	fmt.Println()
+	done := startSpinner("Fetching the night sky for " + clr(bold+white, city) + " ...")
+	info, err := weather.NewClient().GetWeather(city, "metric")
+	close(done)
+	time.Sleep(20 * time.Millisecond) // let spinner goroutine clear line
+	fmt.Println()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "  %sError:%s %v\n\n", red+bold, reset, err)
+		os.Exit(1)
+	}
+	printStars(info)
+}
+
+// starColor grades a stargazing score like the suitability bars.
+func starColor(score int) string {
+	switch {
+	case score >= 80:
+		return green + bold
+	case score >= 60:
+		return green
+	case score >= 40:
+		return yellow
+	case score >= 20:
+		return orange
+	}
+	return red
+}
+
+// printStars renders the outlook: one row per night, then the best night's
+// hours with cloud, humidity and where the moon is.
+func printStars(info *weather.WeatherInfo) {
+	if len(info.Stars) == 0 {
+		fmt.Println(row(clr(dim, "No stargazing outlook — the forecast has no night hours.")))
+		return
+	}
+	best := weather.BestStarNight(info.Stars)
+
+	fmt.Println(topBar(fmt.Sprintf("Stargazing · %s, %s", info.CityName, info.Country)))
+	for i, n := range info.Stars {
+		mark := " "
+		if i == best && n.Score > 0 {
+			mark = clr(yellow+bold, "★")
+		}
+		window := clr(dim, "no good window")
+		if !n.Best.IsZero() {
+			window = clr(white, "best "+n.Best.String())
+		}
+		fmt.Println(row(fmt.Sprintf("%s %-8s %s %s %-9s %s  %s %s",
+			mark, clr(bold, n.Label),
+			progressBar(n.Score, 10, starColor(n.Score)),
+			clr(starColor(n.Score), fmt.Sprintf("%3d", n.Score)), n.Rating,
+			window,
+			clr(dim, fmt.Sprintf("moon %2.0f%%", n.MoonIllum*100)),
+			clr(dim, n.Limit),
+		)))
+	}
+	fmt.Println(blankRow())
+	fmt.Println(row(clr(dim, "Astronomical darkness: ") + clr(white, info.Stars[0].Dark.String()) +
+		clr(dim, fmt.Sprintf(" (%s)", info.Stars[0].Label))))
+	fmt.Println(botBar())
+	fmt.Println()
+
+	n := info.Stars[best]
+	if len(n.Hours) == 0 {
+		return
+	}
+	fmt.Println(topBar(fmt.Sprintf("%s, hour by hour · %s", n.Label, n.Rating)))
+	fmt.Println(row(clr(dim, fmt.Sprintf("%-5s  %-14s  %5s  %5s  %s", "HOUR", "SCORE", "CLOUD", "HUMID", "SKY"))))
+	for _, h := range n.Hours {
+		sky := "twilight"
+		if h.Dark() {
+			sky = "dark"
+		}
+		if h.MoonAlt > 0 {
+			sky += fmt.Sprintf(", moon up %.0f°", h.MoonAlt)
+		}
+		fmt.Println(row(fmt.Sprintf("%s  %s %s  %s  %s  %s",
+			h.Time,
+			progressBar(h.Score, 10, starColor(h.Score)),
+			clr(starColor(h.Score), fmt.Sprintf("%3d", h.Score)),
+			clr(blue, fmt.Sprintf("%4d%%", h.Cloud)),
+			clr(cyan, fmt.Sprintf("%4d%%", h.Humidity)),
+			clr(dim, sky),
+		)))
+	}
+	fmt.Println(botBar())
+	fmt.Println()
+}
@@ -0,0 +1,820 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"WeatherApp/i18n"
+	"WeatherApp/weather"
+)
+
+// tuiTabs are the TUI's tabs in order; the digit keys 1-5 jump to them.
+var tuiTabs = []string{"Current", "Hourly", "5-Day", "Consensus", "Outfit"}
+
+const (
+	tabCurrent = iota
+	tabHourly
+	tabDaily
+	tabConsensus
+	tabOutfit
+)
+
+// tui is the state of `weather-cli tui`. It changes only through key and
+// draws only into scr, so a test can run it on a virtual screen with a fake
+// fetch and compare scr.Text() after each key.
+type tui struct {
+	scr    *screen
+	fetch  func(city string) (*weather.WeatherInfo, error)
+	outfit weather.OutfitOptions
+
+	info  *weather.WeatherInfo
+	city  string
+	units string // display units; info keeps the units it was fetched in
+
+	tab       int
+	hour, day int // selected rows on the Hourly and 5-Day tabs
+
+	favorites     []string
+	saveFavorites func([]string) error // nil keeps favorites in memory only
+
+	switching bool   // city switcher open
+	pick      int    // highlighted favorite in the switcher
+	input     string // city typed into the switcher
+	status    string // footer message, cleared by the next key
+}
+
+// load fetches city and shows it; on failure the old forecast stays up with
+// the error in the footer.
+func (t *tui) load(city string) {
+	city = cfg.resolve(city)
+	info, err := t.fetch(city)
+	if err != nil {
+		t.status = clr(red+bold, "Error:") + " " + err.Error()
+		return
+	}
+	t.info, t.city = info, city
+	t.hour, t.day = 0, 0
+	t.status = ""
+}
+
+// key handles one key press and reports whether the TUI should quit.
+func (t *tui) key(k string) (quit bool) {
+	t.status = ""
+	if k == "ctrl-c" {
+		return true
+	}
+	if t.switching {
+		t.switchKey(k)
+		return false
+	}
+	switch k {
+	case "q":
+		return true
+	case "tab", "right", "l":
+		t.tab = (t.tab + 1) % len(tuiTabs)
+	case "backtab", "left", "h":
+		t.tab = (t.tab + len(tuiTabs) - 1) % len(tuiTabs)
+	case "1", "2", "3", "4", "5":
+		t.tab = int(k[0] - '1')
+	case "down", "j":
+		t.move(1)
+	case "up", "k":
+		t.move(-1)
+	case "pgdown":
+		t.move(6)
+	case "pgup":
+		t.move(-6)
+	case "home", "g":
+		t.move(-1 << 10)
+	case "end", "G":
+		t.move(1 << 10)
+	case "u":
+		if t.units == "imperial" {
+			t.units = "metric"
+		} else {
+			t.units = "imperial"
+		}
+	case "c", "/":
+		t.switching, t.pick, t.input = true, 0, ""
+	case "f":
+		t.toggleFavorite()
+	case "r":
+		if t.city != "" {
+			t.load(t.city)
+		}
+	}
+	return false
+}
+
+// move shifts the selected hour or day by n, within the forecast.
+func (t *tui) move(n int) {
+	if t.info == nil {
+		return
+	}
+	switch t.tab {
+	case tabHourly:
+		t.hour = max(0, min(t.hour+n, len(t.info.Hourly)-1))
+	case tabDaily:
+		t.day = max(0, min(t.day+n, len(t.info.Forecast)-1))
+	}
+}
+
+// switchKey handles keys while the city switcher is open: type a city, or
+// pick a favorite with the arrows; Enter loads it, Esc closes.
+func (t *tui) switchKey(k string) {
+	switch k {
+	case "esc":
+		t.switching = false
+	case "up":
+		t.pick = max(0, t.pick-1)
+	case "down":
+		t.pick = max(0, min(t.pick+1, len(t.favorites)-1))
+	case "delete":
+		if t.input == "" && t.pick < len(t.favorites) {
+			name := t.favorites[t.pick]
+			t.favorites = slices.Delete(t.favorites, t.pick, t.pick+1)
+			t.pick = max(0, min(t.pick, len(t.favorites)-1))
+			t.status = i18n.Sprintf(lang, "Removed %s from favorites", name)
+			t.storeFavorites()
+		}
+	case "backspace":
+		if r := []rune(t.input); len(r) > 0 {
+			t.input = string(r[:len(r)-1])
+		}
+	case "enter":
+		city := strings.TrimSpace(t.input)
+		if city == "" && t.pick < len(t.favorites) {
+			city = t.favorites[t.pick]
+		}
+		t.switching = false
+		if city != "" {
+			t.load(city)
+		}
+	default:
+		if utf8.RuneCountInString(k) == 1 {
+			t.input += k
+		}
+	}
+}
+
+// toggleFavorite saves the city on screen as a favorite, or forgets it.
+func (t *tui) toggleFavorite() {
+	if t.info == nil {
+		return
+	}
+	name := t.info.CityName
+	if i := slices.Index(t.favorites, name); i >= 0 {
+		t.favorites = slices.Delete(t.favorites, i, i+1)
+		t.status = i18n.Sprintf(lang, "Removed %s from favorites", name)
+	} else {
+		t.favorites = append(t.favorites, name)
+		t.status = i18n.Sprintf(lang, "Added %s to favorites", name)
+	}
+	t.storeFavorites()
+}
+
+func (t *tui) storeFavorites() {
+	if t.saveFavorites == nil {
+		return
+	}
+	if err := t.saveFavorites(t.favorites); err != nil {
+		t.status = clr(yellow+bold, "Warning:") + " " + err.Error()
+	}
+}
+
+// temp converts a temperature from the fetched units to the display units.
+func (t *tui) temp(v float64) float64 {
+	switch {
+	case t.units == "imperial" && t.info.TempUnit == "°C":
+		return v*9/5 + 32
+	case t.units != "imperial" && t.info.TempUnit == "°F":
+		return (v - 32) * 5 / 9
+	}
+	return v
+}
+
+// tempDelta converts a temperature difference, such as a spread.
+func (t *tui) tempDelta(v float64) float64 {
+	switch {
+	case t.units == "imperial" && t.info.TempUnit == "°C":
+		return v * 9 / 5
+	case t.units != "imperial" && t.info.TempUnit == "°F":
+		return v * 5 / 9
+	}
+	return v
+}
+
+// wind converts a wind speed from the fetched units to the display units.
+func (t *tui) wind(v float64) float64 {
+	switch {
+	case t.units == "imperial" && t.info.WindUnit == "km/h":
+		return v / 1.60934
+	case t.units != "imperial" && t.info.WindUnit == "mph":
+		return v * 1.60934
+	}
+	return v
+}
+
+// tempStr is a colored temperature in the display units.
+func (t *tui) tempStr(v float64, prec int) string {
+	c, unit := t.temp(v), weather.TempUnitSymbol(t.units)
+	return clr(tempColor(c, unit), i18n.Number(lang, c, prec)+unit)
+}
+
+// windStr is a wind speed in the display units.
+func (t *tui) windStr(v float64) string {
+	return fmt.Sprintf("%.0f %s", t.wind(v), weather.WindUnitLabel(t.units))
+}
+
+// draw renders the whole frame into scr: title, tab bar, the tab (or the
+// city switcher) and a footer with the status or the key help.
+func (t *tui) draw() {
+	s := t.scr
+	s.clear()
+
+	title := "  " + strings.ToUpper(tr("Weather"))
+	if t.info != nil {
+		title += "  —  " + strings.ToUpper(t.info.CityName+", "+t.info.Country)
+	}
+	right := weather.TempUnitSymbol(t.units) + " · " + weather.WindUnitLabel(t.units)
+	pad := max(1, W-2-utf8.RuneCountInString(title)-utf8.RuneCountInString(right))
+	s.set(0, clr(bold+white, title)+strings.Repeat(" ", pad)+clr(dim, right))
+
+	tabs := make([]string, len(tuiTabs))
+	for i, name := range tuiTabs {
+		label := fmt.Sprintf(" %d %s ", i+1, tr(name))
+		if i == t.tab {
+			tabs[i] = clr(reverse+bold, label)
+		} else {
+			tabs[i] = clr(dim, label)
+		}
+	}
+	s.set(1, "  "+strings.Join(tabs, " "))
+
+	for i, line := range t.body(s.h - 4) {
+		s.set(3+i, line)
+	}
+
+	footer := "  " + t.status
+	switch {
+	case t.status != "":
+	case t.switching:
+		footer = "  " + clr(dim, tr("↑↓ pick · type a city · Enter go · Del remove · Esc back"))
+	default:
+		footer = "  " + clr(dim, tr("←→ tabs · ↑↓ select · u units · c city · f favorite · r refresh · q quit"))
+	}
+	s.set(s.h-1, footer)
+}
+
+// body is the lines under the tab bar, at most height of them.
+func (t *tui) body(height int) []string {
+	var lines []string
+	switch {
+	case t.switching:
+		lines = t.switcherLines()
+	case t.info == nil:
+		lines = []string{row(clr(dim, tr("No forecast loaded. Press c to pick a city.")))}
+	case t.tab == tabCurrent:
+		lines = t.currentLines()
+	case t.tab == tabHourly:
+		lines = t.hourlyLines(height)
+	case t.tab == tabDaily:
+		lines = t.dailyLines(height)
+	case t.tab == tabConsensus:
+		lines = t.consensusLines()
+	case t.tab == tabOutfit:
+		lines = t.outfitLines()
+	}
+	if len(lines) > height {
+		lines = lines[:max(0, height)]
+	}
+	return lines
+}
+
+func (t *tui) currentLines() []string {
+	info, cur := t.info, t.info.Current
+	lines := []string{topBar(tr("Current Conditions"))}
+	lines = append(lines, row(fmt.Sprintf("%s  %s %s  %s %s",
+		clr(bold+white, fmt.Sprintf("%-17s", clip(tr(cur.Description), 17))),
+		clr(dim+cyan, tr("Temp")), clr(bold, t.tempStr(cur.Temp, 1)),
+		clr(dim+cyan, tr("Feels")), t.tempStr(cur.FeelsLike, 1),
+	)), blankRow())
+	lines = append(lines, row(fmt.Sprintf("%s %s %3d%%    %s %s %3d%%",
+		clr(dim+cyan, fmt.Sprintf("%-10s", tr("Humidity"))), progressBar(cur.Humidity, 14, cyan), cur.Humidity,
+		clr(dim+cyan, fmt.Sprintf("%-9s", tr("Cloud"))), progressBar(cur.CloudCover, 14, blue), cur.CloudCover,
+	)))
+	lines = append(lines, row(fmt.Sprintf("%s %s  %s %s %s",
+		clr(dim+cyan, fmt.Sprintf("%-10s", tr("Pressure"))), padANSI(clr(white, fmt.Sprintf("%.0f hPa", cur.Pressure)), 14),
+		clr(dim+cyan, fmt.Sprintf("%-9s", tr("Wind"))), clr(white, t.windStr(cur.WindSpeed)),
+		clr(dim+cyan, weather.WindCompass(cur.WindDir)),
+	)))
+	uvc := uvColor(cur.UVIndex)
+	lines = append(lines, row(fmt.Sprintf("%s %s  %s %s %s",
+		clr(dim+cyan, fmt.Sprintf("%-10s", tr("Dew Point"))), padANSI(t.tempStr(cur.DewPoint, 1), 14),
+		clr(dim+cyan, fmt.Sprintf("%-9s", tr("UV Index"))), clr(uvc, i18n.Number(lang, cur.UVIndex, 1)),
+		clr(uvc, "("+tr(weather.UVLevel(cur.UVIndex))+")"),
+	)))
+	if info.Sun.SunriseTime != "" {
+		lines = append(lines, row(fmt.Sprintf("%s %s  %s %s   %s",
+			clr(dim+cyan, fmt.Sprintf("%-10s", tr("Sunrise"))), padANSI(clr(yellow, info.Sun.SunriseTime), 14),
+			clr(dim+cyan, fmt.Sprintf("%-9s", tr("Sunset"))), clr(yellow, info.Sun.SunsetTime),
+			clr(dim, info.Sun.DaylightHours),
+		)))
+	}
+	lines = append(lines, botBar())
+
+	for _, a := range weather.Alerts(info) {
+		prefix := alertColor(a.Level) + alertPrefix(a.Level) + reset
+		lines = append(lines, fmt.Sprintf("  %s %s  %s", prefix, clr(bold, a.Title), clr(dim, a.Message)))
+	}
+	return lines
+}
+
+// scrollStart is the first of n visible rows out of total that keeps sel
+// in view, centred where it can be.
+func scrollStart(sel, total, n int) int {
+	return max(0, min(sel-n/2, total-n))
+}
+
+// listRow draws one row of the Hourly or 5-Day list. The selected row is in
+// reverse video, so its cells go uncolored.
+func listRow(selected bool, cells ...string) string {
+	if selected {
+		return row(clr(reverse+bold, "▶ "+stripANSI(strings.Join(cells, "  "))))
+	}
+	return row("  " + strings.Join(cells, "  "))
+}
+
+func (t *tui) hourlyLines(height int) []string {
+	hours := t.info.Hourly
+	if len(hours) == 0 {
+		return []string{topBar(tr("Next 24 Hours")), row(clr(dim, tr("No hourly forecast"))), botBar()}
+	}
+	unit := weather.TempUnitSymbol(t.units)
+	lines := []string{
+		topBar(tr("Next 24 Hours")),
+		row(clr(dim, fmt.Sprintf("  %-5s  %-14s  %6s  %5s  %s", tr("TIME"), tr("CONDITION"), tr("TEMP"), tr("RAIN"), tr("WIND")))),
+	}
+	const detail = 6
+	visible := max(1, height-len(lines)-1-detail)
+	first := scrollStart(t.hour, len(hours), visible)
+	for i := first; i < min(first+visible, len(hours)); i++ {
+		h := hours[i]
+		temp := t.temp(h.Temp)
+		rainColor := dim
+		if h.PrecipProb >= 30 {
+			rainColor = blue + bold
+		}
+		lines = append(lines, listRow(i == t.hour,
+			clr(bold, fmt.Sprintf("%-5s", h.Time)),
+			clr(dim, fmt.Sprintf("%-14s", clip(tr(h.Description), 14))),
+			clr(tempColor(temp, unit), fmt.Sprintf("%5.0f%s", temp, unit)),
+			clr(rainColor, fmt.Sprintf("%4d%%", h.PrecipProb)),
+			clr(blue, t.windStr(h.WindSpeed)),
+		))
+	}
+	lines = append(lines, botBar())
+
+	h := hours[t.hour]
+	uvc := uvColor(h.UVIndex)
+	lines = append(lines,
+		topBar(h.Time+" · "+tr(h.Description)),
+		row(fmt.Sprintf("%s %s  %s %s",
+			clr(dim+cyan, fmt.Sprintf("%-10s", tr("Temp"))), padANSI(t.tempStr(h.Temp, 1), 14),
+			clr(dim+cyan, fmt.Sprintf("%-9s", tr("Feels"))), t.tempStr(h.FeelsLike, 1),
+		)),
+		row(fmt.Sprintf("%s %s  %s %s",
+			clr(dim+cyan, fmt.Sprintf("%-10s", tr("Rain"))), padANSI(clr(blue, fmt.Sprintf("%d%%", h.PrecipProb)), 14),
+			clr(dim+cyan, fmt.Sprintf("%-9s", tr("UV Index"))), clr(uvc, i18n.Number(lang, h.UVIndex, 1)+" ("+tr(weather.UVLevel(h.UVIndex))+")"),
+		)),
+		row(fmt.Sprintf("%s %s  %s %s",
+			clr(dim+cyan, fmt.Sprintf("%-10s", tr("Wind"))), padANSI(clr(white, t.windStr(h.WindSpeed)), 14),
+			clr(dim+cyan, fmt.Sprintf("%-9s", tr("Gusts"))), clr(white, t.windStr(h.WindGust)),
+		)),
+	)
+	if b := h.TempBand; b.Models > 0 {
+		lines = append(lines, row(fmt.Sprintf("%s %s",
+			clr(dim+cyan, fmt.Sprintf("%-10s", tr("Models"))),
+			clr(dim, fmt.Sprintf("%.0f–%.0f%s (%s)", t.temp(b.Min), t.temp(b.Max), unit, tr(b.Confidence))),
+		)))
+	}
+	return append(lines, botBar())
+}
+
+func (t *tui) dailyLines(height int) []string {
+	days := t.info.Forecast
+	if len(days) == 0 {
+		return []string{topBar(tr("5-Day Forecast")), row(clr(dim, tr("No daily forecast"))), botBar()}
+	}
+	unit := weather.TempUnitSymbol(t.units)
+	lines := []string{
+		topBar(tr("5-Day Forecast")),
+		row(clr(dim, fmt.Sprintf("  %-10s  %-14s  %5s  %5s  %5s  %s", tr("DATE"), tr("CONDITION"), tr("HI"), tr("LO"), tr("RAIN"), tr("WIND")))),
+	}
+	const detail = 6
+	visible := max(1, height-len(lines)-1-detail)
+	first := scrollStart(t.day, len(days), visible)
+	for i := first; i < min(first+visible, len(days)); i++ {
+		d := days[i]
+		hi, lo := t.temp(d.TempMax), t.temp(d.TempMin)
+		lines = append(lines, listRow(i == t.day,
+			clr(bold, fmt.Sprintf("%-10s", dayLabel(d.Date))),
+			clr(dim, fmt.Sprintf("%-14s", clip(tr(d.Description), 14))),
+			clr(tempColor(hi, unit), fmt.Sprintf("%4.0f°", hi)),
+			clr(tempColor(lo, unit), fmt.Sprintf("%4.0f°", lo)),
+			clr(blue, fmt.Sprintf("%4d%%", d.PrecipProb)),
+			clr(blue, t.windStr(d.WindMax)),
+		))
+	}
+	lines = append(lines, botBar())
+
+	d := days[t.day]
+	lines = append(lines,
+		topBar(dayLabel(d.Date)+" · "+tr(d.Description)),
+		row(fmt.Sprintf("%s %s  %s %s",
+			clr(dim+cyan, fmt.Sprintf("%-10s", tr("High"))), padANSI(t.tempStr(d.TempMax, 1), 14),
+			clr(dim+cyan, fmt.Sprintf("%-9s", tr("Low"))), t.tempStr(d.TempMin, 1),
+		)),
+		row(fmt.Sprintf("%s %s  %s %s",
+			clr(dim+cyan, fmt.Sprintf("%-10s", tr("Rain"))), padANSI(clr(blue, fmt.Sprintf("%d%%", d.PrecipProb)), 14),
+			clr(dim+cyan, fmt.Sprintf("%-9s", tr("Wind"))), clr(white, t.windStr(d.WindMax)),
+		)),
+	)
+	if hb, lb := d.TempMaxBand, d.TempMinBand; hb.Models > 0 {
+		lines = append(lines, row(fmt.Sprintf("%s %s",
+			clr(dim+cyan, fmt.Sprintf("%-10s", tr("Models"))),
+			clr(dim, fmt.Sprintf("%s %.0f–%.0f%s · %s %.0f–%.0f%s (%s)",
+				tr("High"), t.temp(hb.Min), t.temp(hb.Max), unit,
+				tr("Low"), t.temp(lb.Min), t.temp(lb.Max), unit, tr(d.Confidence))),
+		)))
+	}
+	return append(lines, botBar())
+}
+
+// dayLabel is "Mon 2 Jan" in lang, or the date as given if it won't parse.
+func dayLabel(date string) string {
+	if d, err := time.Parse("2006-01-02", date); err == nil {
+		return i18n.Date(lang, d, "Mon 2 Jan")
+	}
+	return date
+}
+
+func (t *tui) consensusLines() []string {
+	cons := t.info.Consensus
+	if cons == nil || cons.AvailCount == 0 {
+		return []string{topBar(tr("Model Consensus")), row(clr(dim, tr("Model consensus unavailable"))), botBar()}
+	}
+	unit, windUnit := weather.TempUnitSymbol(t.units), weather.WindUnitLabel(t.units)
+	lines := []string{
+		topBar(tr("Model Consensus")),
+		row(fmt.Sprintf("%s %s    %s %s",
+			clr(dim+cyan, fmt.Sprintf("%-10s", tr("Agreement"))),
+			clr(agreePctColor(cons.AgreePct)+bold, fmt.Sprintf("%s (%d%%)", tr(cons.Agreement), cons.AgreePct)),
+			clr(dim+cyan, fmt.Sprintf("%-9s", tr("Condition"))),
+			clr(white, fmt.Sprintf("%s (%d/%d)", tr(cons.Weather.Description), cons.Weather.Votes, cons.AvailCount)),
+		)),
+		blankRow(),
+		row(fmt.Sprintf("%-9s  %8s  %6s  %-17s  %s",
+			clr(dim, "VARIABLE"), clr(dim, strings.ToUpper(string(cons.Method))),
+			clr(dim, "SD"), clr(dim, "RANGE"), clr(dim, "AGREE"),
+		)),
+	}
+	for _, v := range []struct {
+		label  string
+		stats  weather.VarStats
+		conv   func(float64) float64
+		spread func(float64) float64
+		unit   string
+	}{
+		{"Temp", cons.Temp, t.temp, t.tempDelta, unit},
+		{"Humidity", cons.Humidity, nil, nil, "%"},
+		{"Wind", cons.Wind, t.wind, t.wind, " " + windUnit},
+		{"Pressure", cons.Pressure, nil, nil, " hPa"},
+	} {
+		conv, spread := v.conv, v.spread
+		if conv == nil {
+			conv = func(x float64) float64 { return x }
+			spread = conv
+		}
+		lines = append(lines, row(fmt.Sprintf("%-9s  %8s  %6s  %-17s  %s",
+			clr(cyan, v.label),
+			clr(white, fmt.Sprintf("%.1f", conv(v.stats.Value))),
+			fmt.Sprintf("%.1f", spread(v.stats.StdDev)),
+			clr(dim, fmt.Sprintf("%.1f–%.1f%s", conv(v.stats.Min), conv(v.stats.Max), v.unit)),
+			clr(agreePctColor(v.stats.AgreePct), fmt.Sprintf("%3d%%", v.stats.AgreePct)),
+		)))
+	}
+	lines = append(lines, blankRow())
+	for _, r := range cons.Models {
+		name := fmt.Sprintf("%-14s", r.Model)
+		switch {
+		case !r.Available:
+			lines = append(lines, row(clr(dim, name+"  unavailable")))
+		case r.Excluded:
+			lines = append(lines, row(clr(dim, fmt.Sprintf("%s  %.1f%s  excluded — %s", name, t.temp(r.Temp), unit, r.Reason))))
+		default:
+			lines = append(lines, row(fmt.Sprintf("%s  %s  %s",
+				clr(cyan, name), t.tempStr(r.Temp, 1), clr(dim, r.Reason))))
+		}
+	}
+	return append(lines, botBar())
+}
+
+func (t *tui) outfitLines() []string {
+	o := t.outfit.Build(t.info)
+	lines := []string{
+		topBar(tr("What to Wear") + " · " + o.Activity),
+		row(clr(dim+cyan, o.Headline)),
+		row(suitabilityLine(o.Suitability)),
+		row(strings.Repeat("─", W-10)),
+	}
+	for _, item := range o.Items {
+		lines = append(lines, row(fmt.Sprintf("%s  %-16s  %s",
+			outfitEmoji(item.Icon), clr(bold, item.Label), clr(dim, item.Note))))
+	}
+	return append(lines, botBar())
+}
+
+func (t *tui) switcherLines() []string {
+	lines := []string{
+		topBar(tr("Switch City")),
+		row(clr(dim+cyan, tr("City")+": ") + clr(bold+white, t.input) + clr(orange, "█")),
+		blankRow(),
+	}
+	if len(t.favorites) == 0 {
+		lines = append(lines, row(clr(dim, tr("No favorites yet. Press f on a city to save it."))))
+	}
+	for i, name := range t.favorites {
+		if i == t.pick && t.input == "" {
+			lines = append(lines, row(clr(reverse+bold, "★ "+name)))
+		} else {
+			lines = append(lines, row(clr(yellow, "☆ ")+name))
+		}
+	}
+	return append(lines, botBar())
+}
+
+// escKeys names the escape sequences the TUI understands, without the
+// leading ESC.
+var escKeys = map[string]string{
+	"[A": "up", "[B": "down", "[C": "right", "[D": "left",
+	"OA": "up", "OB": "down", "OC": "right", "OD": "left",
+	"[H": "home", "[F": "end", "OH": "home", "OF": "end",
+	"[1~": "home", "[4~": "end", "[3~": "delete",
+	"[5~": "pgup", "[6~": "pgdown", "[Z": "backtab",
+}
+
+// decodeKeys turns bytes read from the terminal into key names: "up",
+// "enter", "esc", "ctrl-c" or the character typed. A read can end partway
+// through an escape sequence or a UTF-8 character; those bytes come back
+// as rest, to be decoded with the next read.
+func decodeKeys(b []byte) (keys []string, rest []byte) {
+	for i := 0; i < len(b); {
+		c := b[i]
+		switch {
+		case c == 0x1b:
+			if escPrefix(b[i+1:]) {
+				return keys, b[i:]
+			}
+			k, n := "esc", 1
+			for l := 3; l >= 2; l-- {
+				if i+1+l <= len(b) {
+					if name, ok := escKeys[string(b[i+1:i+1+l])]; ok {
+						k, n = name, 1+l
+						break
+					}
+				}
+			}
+			keys = append(keys, k)
+			i += n
+		case c == 0x03:
+			keys = append(keys, "ctrl-c")
+			i++
+		case c == '\t':
+			keys = append(keys, "tab")
+			i++
+		case c == '\r' || c == '\n':
+			keys = append(keys, "enter")
+			i++
+		case c == 0x7f || c == 0x08:
+			keys = append(keys, "backspace")
+			i++
+		case c < 0x20:
+			i++
+		case !utf8.FullRune(b[i:]):
+			return keys, b[i:]
+		default:
+			r, size := utf8.DecodeRune(b[i:])
+			keys = append(keys, string(r))
+			i += size
+		}
+	}
+	return keys, nil
+}
+
+// escPrefix reports whether b, the bytes after an ESC, could still grow
+// into one of escKeys.
+func escPrefix(b []byte) bool {
+	for seq := range escKeys {
+		if len(b) < len(seq) && strings.HasPrefix(seq, string(b)) {
+			return true
+		}
+	}
+	return false
+}
+
+// escWait is how long an unfinished escape sequence waits for the rest
+// before it counts as the Esc key on its own.
+const escWait = 50 * time.Millisecond
+
+// readKeys sends the keys read from r until it fails.
+func readKeys(r io.Reader, keys chan<- string) {
+	defer close(keys)
+	chunks := make(chan []byte)
+	go func() {
+		defer close(chunks)
+		for {
+			buf := make([]byte, 64)
+			n, err := r.Read(buf)
+			if n > 0 {
+				chunks <- buf[:n]
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	var pending []byte
+	for {
+		var timeout <-chan time.Time
+		if len(pending) > 0 {
+			timeout = time.After(escWait)
+		}
+		var ks []string
+		select {
+		case chunk, ok := <-chunks:
+			if !ok {
+				if len(pending) > 0 && pending[0] == 0x1b {
+					keys <- "esc"
+				}
+				return
+			}
+			ks, pending = decodeKeys(append(pending, chunk...))
+		case <-timeout:
+			// A lone Esc, or a sequence the terminal never finished; a
+			// broken UTF-8 character is dropped.
+			if pending[0] == 0x1b {
+				ks, _ = decodeKeys(pending[1:])
+				ks = append([]string{"esc"}, ks...)
+			}
+			pending = nil
+		}
+		for _, k := range ks {
+			keys <- k
+		}
+	}
+}
+
+// stty runs stty on the terminal; the CLI has no terminal library, so raw
+// mode and the window size come from it.
+func stty(args ...string) (string, error) {
+	cmd := exec.Command("stty", args...)
+	cmd.Stdin = os.Stdin
+	out, err := cmd.Output()
+	return strings.TrimSpace(string(out)), err
+}
+
+// termSize is the terminal's width and height.
+func termSize() (w, h int, err error) {
+	out, err := stty("size")
+	if err != nil {
+		return 0, 0, errors.New("weather-cli tui needs an interactive terminal with stty")
+	}
+	if _, err := fmt.Sscan(out, &h, &w); err != nil || w == 0 || h == 0 {
+		return 0, 0, fmt.Errorf("cannot read terminal size %q", out)
+	}
+	return w, h, nil
+}
+
+// rawMode switches the terminal to raw input without echo and returns the
+// function that puts it back.
+func rawMode() (restore func(), err error) {
+	saved, err := stty("-g")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := stty("raw", "-echo"); err != nil {
+		return nil, err
+	}
+	return func() { stty(saved) }, nil
+}
+
+// runTUI implements `weather-cli tui [City]`: a full-screen view with tabs,
+// keyboard navigation, a city switcher and a unit toggle.
+func runTUI(args []string) {
+	fs := flag.NewFlagSet("tui", flag.ExitOnError)
+	cityFlag := fs.String("city", "", "City name (or first positional argument; default: first favorite, else the configured location)")
+	units := fs.String("units", "", "Units to start in: metric or imperial (default from the locale; u toggles)")
+	activityFlag := fs.String("activity", "walk", "Outfit and suitability profile: "+strings.Join(weather.ActivityKeys(), ", "))
+	langFlag := fs.String("lang", "", "Language: "+strings.Join(i18n.Tags(), ", ")+" (default from $LANG)")
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: weather-cli tui [flags] [City]")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	localePrefs := []string{*langFlag, cfg.Lang, os.Getenv("LC_ALL"), os.Getenv("LC_MESSAGES"), os.Getenv("LANG")}
+	lang = i18n.Match(localePrefs...)
+	if *units == "" {
+		*units = cfg.unitsOr(i18n.Units(localePrefs...))
+	}
+	activity, err := weather.ParseActivity(*activityFlag)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "\n  %sError:%s %v\n\n", red+bold, reset, err)
+		os.Exit(1)
+	}
+	outfitOpts := weather.OutfitOptions{Activity: activity}
+	if profile, err := weather.LoadComfortProfile(comfortPath()); err == nil {
+		outfitOpts.Comfort = profile
+	}
+	favorites, err := loadFavorites()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "\n  %sWarning:%s ignoring favorites: %v\n", yellow+bold, reset, err)
+	}
+
+	city := *cityFlag
+	switch {
+	case city != "":
+	case fs.NArg() > 0:
+		city = strings.Join(fs.Args(), " ")
+	case len(favorites) > 0:
+		city = favorites[0]
+	default:
+		city = cfg.defaultCity()
+	}
+
+	w, h, err := termSize()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "\n  %sError:%s %v\n\n", red+bold, reset, err)
+		os.Exit(1)
+	}
+	restore, err := rawMode()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "\n  %sError:%s %v\n\n", red+bold, reset, err)
+		os.Exit(1)
+	}
+	defer restore()
+	fmt.Print("\033[?1049h" + hideCursor) // alternate screen, so the shell comes back untouched
+	defer fmt.Print(showCursor + "\033[?1049l")
+
+	client := weather.NewClient()
+	client.Lang = lang
+	t := &tui{
+		scr:           newScreen(w, h),
+		outfit:        outfitOpts,
+		units:         *units,
+		favorites:     favorites,
+		saveFavorites: saveFavorites,
+	}
+	// Units only change how values are shown, so every fetch uses the
+	// starting units and u never refetches.
+	fetchUnits := *units
+	t.fetch = func(city string) (*weather.WeatherInfo, error) {
+		t.status = clr(dim, "Fetching weather for "+city+" ...")
+		t.draw()
+		t.scr.flush(os.Stdout)
+		return client.GetWeather(city, fetchUnits)
+	}
+	t.load(city)
+
+	keys := make(chan string)
+	go readKeys(os.Stdin, keys)
+	resize := make(chan os.Signal, 1)
+	notifyResize(resize)
+	defer signal.Stop(resize)
+	for {
+		t.draw()
+		t.scr.flush(os.Stdout)
+		select {
+		case k, ok := <-keys:
+			if !ok || t.key(k) {
+				return
+			}
+		case <-resize:
+			if w, h, err := termSize(); err == nil && (w != t.scr.w || h != t.scr.h) {
+				t.scr.resize(w, h)
+				fmt.Print("\033[2J")
+			}
+		}
+	}
+}
@@ -0,0 +1,279 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"WeatherApp/weather"
+)
+
+// fakeInfo is a day of made-up weather in °C and km/h: hour i is 10+i °C,
+// day i has a high of 20+i.
+func fakeInfo(city string) *weather.WeatherInfo {
+	info := &weather.WeatherInfo{
+		CityName: city,
+		Country:  "United Kingdom",
+		TempUnit: "°C",
+		WindUnit: "km/h",
+		Current: weather.CurrentDisplay{
+			Description: "Partly cloudy",
+			Temp:        20,
+			FeelsLike:   19,
+			Humidity:    60,
+			WindSpeed:   16.0934,
+			Pressure:    1012,
+		},
+	}
+	for i := range 24 {
+		info.Hourly = append(info.Hourly, weather.HourlyPoint{
+			Time:        fmt.Sprintf("%02d:00", i),
+			Description: "Clear sky",
+			Temp:        float64(10 + i),
+			FeelsLike:   float64(9 + i),
+			PrecipProb:  i,
+			WindSpeed:   10,
+		})
+	}
+	for i := range 5 {
+		info.Forecast = append(info.Forecast, weather.ForecastDay{
+			Date:        fmt.Sprintf("2026-05-%02d", 4+i),
+			Description: "Overcast",
+			TempMax:     float64(20 + i),
+			TempMin:     float64(8 + i),
+		})
+	}
+	return info
+}
+
+// newTestTUI is a TUI on an 80×24 virtual screen whose fetch serves
+// fakeInfo and records the cities asked for.
+func newTestTUI(t *testing.T) (*tui, *[]string) {
+	t.Helper()
+	lang = "en"
+	var fetched []string
+	tt := &tui{
+		scr:   newScreen(80, 24),
+		units: "metric",
+		fetch: func(city string) (*weather.WeatherInfo, error) {
+			fetched = append(fetched, city)
+			if city == "Nowhere" {
+				return nil, fmt.Errorf("no such place")
+			}
+			return fakeInfo(city), nil
+		},
+	}
+	tt.load("London")
+	return tt, &fetched
+}
+
+// press sends keys one by one and redraws, as the main loop does.
+func press(t *testing.T, tt *tui, keys ...string) {
+	t.Helper()
+	for _, k := range keys {
+		if tt.key(k) {
+			t.Fatalf("key %q quit the TUI", k)
+		}
+	}
+	tt.draw()
+}
+
+// lineWith is the first screen row containing s, or "".
+func lineWith(scr *screen, s string) string {
+	for y := range scr.h {
+		if l := scr.Line(y); strings.Contains(l, s) {
+			return l
+		}
+	}
+	return ""
+}
+
+func TestTUITabs(t *testing.T) {
+	tt, _ := newTestTUI(t)
+	tt.draw()
+	if got := tt.scr.Line(0); !strings.Contains(got, "LONDON, UNITED KINGDOM") || !strings.Contains(got, "°C · km/h") {
+		t.Errorf("title = %q", got)
+	}
+	if lineWith(tt.scr, "Current Conditions") == "" || lineWith(tt.scr, "Partly cloudy") == "" {
+		t.Errorf("Current tab missing conditions:\n%s", tt.scr.Text())
+	}
+
+	press(t, tt, "tab")
+	if lineWith(tt.scr, "Next 24 Hours") == "" {
+		t.Fatalf("tab did not open Hourly:\n%s", tt.scr.Text())
+	}
+	press(t, tt, "3")
+	if lineWith(tt.scr, "5-Day Forecast") == "" {
+		t.Errorf("3 did not open 5-Day:\n%s", tt.scr.Text())
+	}
+	press(t, tt, "left", "left", "left")
+	if lineWith(tt.scr, "What to Wear") == "" {
+		t.Errorf("left from 5-Day ×3 did not wrap to Outfit:\n%s", tt.scr.Text())
+	}
+	press(t, tt, "right")
+	if tt.tab != tabCurrent {
+		t.Errorf("right from Outfit: tab = %d, want Current", tt.tab)
+	}
+}
+
+func TestTUIHourlySelection(t *testing.T) {
+	tt, _ := newTestTUI(t)
+	press(t, tt, "2")
+	if got := lineWith(tt.scr, "▶"); !strings.Contains(got, "00:00") {
+		t.Errorf("selected row = %q, want 00:00", got)
+	}
+
+	press(t, tt, "j", "j", "j", "k")
+	if got := lineWith(tt.scr, "▶"); !strings.Contains(got, "02:00") || !strings.Contains(got, "12°C") {
+		t.Errorf("after j j j k: selected row = %q, want 02:00 at 12°C", got)
+	}
+	if lineWith(tt.scr, "02:00 · Clear sky") == "" {
+		t.Errorf("no detail box for 02:00:\n%s", tt.scr.Text())
+	}
+
+	press(t, tt, "k", "k", "k")
+	if tt.hour != 0 {
+		t.Errorf("k past the top: hour = %d, want 0", tt.hour)
+	}
+	press(t, tt, "G")
+	if got := lineWith(tt.scr, "▶"); !strings.Contains(got, "23:00") {
+		t.Errorf("G: selected row = %q, want 23:00", got)
+	}
+}
+
+func TestTUIUnitToggle(t *testing.T) {
+	tt, fetched := newTestTUI(t)
+	press(t, tt, "u")
+	if got := tt.scr.Line(0); !strings.Contains(got, "°F · mph") {
+		t.Errorf("title after u = %q, want °F · mph", got)
+	}
+	if got := lineWith(tt.scr, "Temp"); !strings.Contains(got, "68.0°F") {
+		t.Errorf("temperature row = %q, want 20 °C as 68.0°F", got)
+	}
+	if got := lineWith(tt.scr, "Wind"); !strings.Contains(got, "10 mph") {
+		t.Errorf("wind row = %q, want 10 mph", got)
+	}
+	if len(*fetched) != 1 {
+		t.Errorf("u fetched again: %v", *fetched)
+	}
+
+	press(t, tt, "u")
+	if got := lineWith(tt.scr, "Temp"); !strings.Contains(got, "20.0°C") {
+		t.Errorf("temperature row after u u = %q, want 20.0°C", got)
+	}
+}
+
+func TestTUICitySwitcher(t *testing.T) {
+	tt, fetched := newTestTUI(t)
+	var saved [][]string
+	tt.saveFavorites = func(f []string) error {
+		saved = append(saved, slices.Clone(f))
+		return nil
+	}
+
+	press(t, tt, "f")
+	if !strings.Contains(tt.scr.Line(23), "Added London to favorites") {
+		t.Errorf("footer after f = %q", tt.scr.Line(23))
+	}
+	if len(saved) != 1 || !slices.Equal(saved[0], []string{"London"}) {
+		t.Errorf("saved favorites = %v, want [[London]]", saved)
+	}
+
+	press(t, tt, "c")
+	if lineWith(tt.scr, "Switch City") == "" || !strings.Contains(lineWith(tt.scr, "★"), "London") {
+		t.Fatalf("switcher not shown with London highlighted:\n%s", tt.scr.Text())
+	}
+	press(t, tt, "P", "a", "r", "i", "x", "backspace", "s")
+	if got := lineWith(tt.scr, "City:"); !strings.Contains(got, "Paris") {
+		t.Errorf("input row = %q, want Paris", got)
+	}
+	press(t, tt, "enter")
+	if !strings.Contains(tt.scr.Line(0), "PARIS") || tt.switching {
+		t.Errorf("enter did not load Paris: title %q, switching %v", tt.scr.Line(0), tt.switching)
+	}
+
+	// Enter on an empty input loads the highlighted favorite.
+	press(t, tt, "c", "enter")
+	if !strings.Contains(tt.scr.Line(0), "LONDON") {
+		t.Errorf("favorite not loaded: title %q", tt.scr.Line(0))
+	}
+	if want := []string{"London", "Paris", "London"}; !slices.Equal(*fetched, want) {
+		t.Errorf("fetched %v, want %v", *fetched, want)
+	}
+
+	// A failed fetch keeps the old forecast and shows the error.
+	press(t, tt, "c", "N", "o", "w", "h", "e", "r", "e", "enter")
+	if !strings.Contains(tt.scr.Line(0), "LONDON") || !strings.Contains(tt.scr.Line(23), "no such place") {
+		t.Errorf("after failed fetch: title %q, footer %q", tt.scr.Line(0), tt.scr.Line(23))
+	}
+
+	press(t, tt, "f")
+	if len(saved) != 2 || len(saved[1]) != 0 {
+		t.Errorf("saved favorites after second f = %v, want London removed", saved)
+	}
+	press(t, tt, "c", "esc")
+	if tt.switching {
+		t.Error("esc did not close the switcher")
+	}
+	if !tt.key("q") {
+		t.Error("q did not quit")
+	}
+}
+
+func TestDecodeKeys(t *testing.T) {
+	tests := []struct {
+		name  string
+		reads []string
+		want  []string
+	}{
+		{"plain", []string{"ju\tq"}, []string{"j", "u", "tab", "q"}},
+		{"arrows", []string{"\x1b[A\x1bOB\x1b[5~"}, []string{"up", "down", "pgup"}},
+		{"lone esc", []string{"\x1b"}, nil},
+		{"esc then letter", []string{"\x1bx"}, []string{"esc", "x"}},
+		{"split after esc", []string{"\x1b", "[B"}, []string{"down"}},
+		{"split in sequence", []string{"j\x1b[", "3~k"}, []string{"j", "delete", "k"}},
+		{"split rune", []string{"\xc3", "\xa9\r"}, []string{"é", "enter"}},
+		{"ctrl-c", []string{"\x03"}, []string{"ctrl-c"}},
+	}
+	for _, tt := range tests {
+		var got []string
+		var rest []byte
+		for _, r := range tt.reads {
+			var keys []string
+			keys, rest = decodeKeys(append(rest, r...))
+			got = append(got, keys...)
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("%s: keys = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestReadKeysLoneEsc(t *testing.T) {
+	r, w := io.Pipe()
+	keys := make(chan string)
+	go readKeys(r, keys)
+
+	w.Write([]byte("\x1b"))
+	select {
+	case k := <-keys:
+		if k != "esc" {
+			t.Errorf("key = %q, want esc", k)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("lone Esc was never delivered")
+	}
+
+	w.Write([]byte("\x1b["))
+	w.Write([]byte("A"))
+	if k := <-keys; k != "up" {
+		t.Errorf("split arrow: key = %q, want up", k)
+	}
+	w.Close()
+	if _, ok := <-keys; ok {
+		t.Error("keys not closed at EOF")
+	}
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"WeatherApp/weather"
+)
+
+// printUVExposure renders the UV Exposure box: burn times for the chosen
+// skin type and sunscreen, the safe windows, a vitamin D estimate and the
+// day's UV curve as UVBar rows.
+func printUVExposure(x *weather.UVExposure) {
+	title := fmt.Sprintf("UV Exposure · %s · Skin %s", x.Day, x.Skin.Roman)
+	if x.SPF > 1 {
+		title += fmt.Sprintf(" · SPF %.0f", x.SPF)
+	}
+	fmt.Println(topBar(title))
+	fmt.Println(row(clr(dim, x.Skin.Name)))
+	fmt.Println(blankRow())
+
+	burn := clr(dim, "no risk")
+	if x.BurnPeak > 0 {
+		burn = clr(uvColor(x.Peak.UV), weather.FormatExposure(x.BurnPeak)) +
+			clr(dim, fmt.Sprintf(" at peak (%s, UV %.0f)", x.Peak.Time, x.Peak.UV))
+		if x.Day == "Today" && x.BurnNow > 0 {
+			burn = clr(white, weather.FormatExposure(x.BurnNow)) + clr(dim, " now · ") + burn
+		}
+	}
+	fmt.Println(row(clr(dim+cyan, "Burn time ") + " " + burn))
+
+	safe := clr(dim, "none")
+	if len(x.Safe) > 0 {
+		ws := make([]string, len(x.Safe))
+		for i, w := range x.Safe {
+			ws[i] = w.String()
+		}
+		safe = clr(green, strings.Join(ws, ", "))
+	}
+	fmt.Println(row(clr(dim+cyan, "Safe hours") + " " + safe + clr(dim, fmt.Sprintf("  (UV < %.0f)", x.SafeBelow))))
+
+	vitD := clr(dim, "UV too low for much vitamin D")
+	if x.VitaminD > 0 {
+		vitD = clr(white, weather.FormatExposure(x.VitaminD)) +
+			clr(dim, fmt.Sprintf(" of %s sun on face and arms ≈ 1000 IU", x.Peak.Time))
+	}
+	fmt.Println(row(clr(dim+cyan, "Vitamin D ") + " " + vitD))
+	fmt.Println(row(strings.Repeat("─", W-10)))
+
+	// Daylight hours only; the night is all zeros.
+	for _, h := range x.Hours {
+		if !h.Daylight {
+			continue
+		}
+		mark := " "
+		if h.UV < x.SafeBelow {
+			mark = clr(green, "✓")
+		}
+		burn := ""
+		if b := x.Burn(h); b > 0 && h.UV >= 1 {
+			burn = clr(dim, "burn "+weather.FormatExposure(b))
+		}
+		line := fmt.Sprintf("%s  %s %s  %s", h.Time, clr(uvColor(h.UV), weather.UVBar(h.UV, 24)), mark, burn)
+		if h.Past {
+			line = clr(dim, fmt.Sprintf("%s  %s", h.Time, weather.UVBar(h.UV, 24)))
+		}
+		fmt.Println(row(line))
+	}
+	fmt.Println(botBar())
+	fmt.Println()
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"WeatherApp/weather"
+)
+
+// openVerifyStore opens the verification store at $WEATHER_VERIFY_FILE or the
+// default XDG path. It returns nil, nil when WEATHER_VERIFY_FILE is "off".
+func openVerifyStore() (*weather.VerifyStore, error) {
+	path := os.Getenv("WEATHER_VERIFY_FILE")
+	switch path {
+	case "off":
+		return nil, nil
+	case "":
+		path = weather.DefaultVerifyPath()
+	}
+	return weather.OpenVerifyStore(path)
+}
+
+// runVerify implements `weather-cli verify [-location name]`: a table of each
+// model's rolling temperature MAE and bias, per location and lead time.
+func runVerify(args []string) {
+	fs := flag.NewFlagSet("verify", flag.ExitOnError)
+	location := fs.String("location", "", "Only show locations whose name contains this text")
+	fs.Parse(args)
+	if *location == "" && fs.NArg() > 0 {
+		*location = strings.Join(fs.Args(), " ")
+	}
+
+	store, err := openVerifyStore()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "\n  %sError:%s %v\n\n", red+bold, reset, err)
+		os.Exit(1)
+	}
+	if store == nil {
+		fmt.Fprintf(os.Stderr, "\n  %sError:%s verification is disabled (WEATHER_VERIFY_FILE=off)\n\n", red+bold, reset)
+		os.Exit(1)
+	}
+
+	scores := store.Report(*location)
+	fmt.Println()
+	fmt.Println(topBar("MODEL VERIFICATION"))
+	fmt.Println(blankRow())
+	if len(scores) == 0 {
+		fmt.Println(row(clr(dim, "No verified forecasts yet — run weather-cli for a city, then again once")))
+		fmt.Println(row(clr(dim, "the forecast hours (6 h to 4 days ahead) have passed.")))
+		fmt.Println(blankRow())
+		fmt.Println(botBar())
+		fmt.Println()
+		return
+	}
+
+	current := ""
+	for _, sc := range scores {
+		if sc.Key != current {
+			if current != "" {
+				fmt.Println(blankRow())
+			}
+			current = sc.Key
+			fmt.Println(row(clr(bold+white, sc.Location)))
+			fmt.Println(row(clr(dim, fmt.Sprintf("%-14s %6s %5s %8s %8s", "MODEL", "LEAD", "N", "MAE", "BIAS"))))
+		}
+		maeClr := green
+		switch {
+		case sc.MAE >= 3:
+			maeClr = red
+		case sc.MAE >= 1.5:
+			maeClr = yellow
+		}
+		fmt.Println(row(fmt.Sprintf("%-14s %5dh %5d %s %s",
+			sc.Model, sc.LeadH, sc.Samples,
+			clr(maeClr, fmt.Sprintf("%6.2f°C", sc.MAE)),
+			fmt.Sprintf("%+7.2f°C", sc.Bias))))
+	}
+	fmt.Println(blankRow())
+	fmt.Println(row(clr(dim, "MAE = mean absolute error · BIAS > 0 = forecasts too warm · rolling 30 days")))
+	fmt.Println(botBar())
+	fmt.Println()
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"WeatherApp/i18n"
+)
+
+const (
+	clearScreen = "\033[H\033[2J"
+	hideCursor  = "\033[?25l"
+	showCursor  = "\033[?25h"
+	bell        = "\a"
+)
+
+// watchState remembers what the previous frame drew so a redraw can
+// highlight the values that moved and the alerts that are new. A nil
+// *watchState, as outside -watch, draws everything plainly.
+type watchState struct {
+	prev, cur map[string]string
+	fresh     []string // titles of alerts first seen in this frame
+}
+
+// watch is set by runWatch for the renderers to consult.
+var watch *watchState
+
+// frame starts a redraw: the last frame becomes the one compared against.
+func (w *watchState) frame() {
+	if w == nil {
+		return
+	}
+	w.prev, w.cur = w.cur, map[string]string{}
+	w.fresh = nil
+}
+
+// clr colors s like clr, in reverse video when the value drawn under key
+// last frame was different.
+func (w *watchState) clr(key, color, s string) string {
+	if w == nil {
+		return clr(color, s)
+	}
+	w.cur[key] = s
+	if old, ok := w.prev[key]; ok && old != s {
+		return clr(reverse+color, s)
+	}
+	return clr(color, s)
+}
+
+// newAlert reports whether the alert under key was absent last frame. The
+// first frame has nothing to compare with, so nothing in it is new.
+func (w *watchState) newAlert(key, title string) bool {
+	if w == nil {
+		return false
+	}
+	w.cur["alert "+key] = title
+	if _, seen := w.prev["alert "+key]; w.prev == nil || seen {
+		return false
+	}
+	w.fresh = append(w.fresh, title)
+	return true
+}
+
+// runWatch fetches and redraws in place every interval until Ctrl-C. fetch
+// returns the function that draws the frame; when it fails the last frame
+// stays up with the error under it. New alerts ring the terminal bell and,
+// with notifyAlerts, send a desktop notification.
+func runWatch(every time.Duration, what string, notifyAlerts bool, fetch func() (func(), error)) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	watch = &watchState{}
+	fmt.Print(hideCursor + clearScreen)
+	defer fmt.Print(showCursor)
+
+	type result struct {
+		draw func()
+		err  error
+	}
+	for {
+		done := startSpinner("Fetching weather for " + clr(bold+white, what) + " ...")
+		ch := make(chan result, 1)
+		go func() {
+			draw, err := fetch()
+			ch <- result{draw, err}
+		}()
+		var r result
+		select {
+		case r = <-ch:
+		case <-ctx.Done():
+			close(done)
+			time.Sleep(20 * time.Millisecond)
+			return
+		}
+		close(done)
+		time.Sleep(20 * time.Millisecond) // let spinner goroutine clear line
+
+		status := ""
+		if r.err != nil {
+			status = clr(red+bold, "Error:") + " " + clip(r.err.Error(), 40) + clr(dim, " · ")
+		} else {
+			watch.frame()
+			fmt.Print(clearScreen)
+			r.draw()
+			if len(watch.fresh) > 0 {
+				fmt.Print(bell)
+				if notifyAlerts {
+					if err := notify(tr("Weather alert")+" · "+what, strings.Join(watch.fresh, ", ")); err != nil {
+						status = clr(yellow+bold, "Warning:") + " " + clip(err.Error(), 40) + clr(dim, " · ")
+					}
+				}
+			}
+		}
+		if !countdown(ctx, every, status) {
+			return
+		}
+	}
+}
+
+// countdown ticks the time left to the next refresh on the bottom line,
+// after status. It reports false when Ctrl-C came first.
+func countdown(ctx context.Context, d time.Duration, status string) bool {
+	next := time.Now().Add(d)
+	tick := time.NewTicker(time.Second)
+	defer tick.Stop()
+	for {
+		left := time.Until(next).Round(time.Second)
+		if left <= 0 {
+			fmt.Print("\r\033[K")
+			return true
+		}
+		mmss := fmt.Sprintf("%d:%02d", int(left.Minutes()), int(left.Seconds())%60)
+		fmt.Printf("\r\033[K  %s%s", status, clr(dim, i18n.Sprintf(lang, "Next refresh in %s · Ctrl-C to quit", mmss)))
+		select {
+		case <-ctx.Done():
+			fmt.Print("\r\033[K")
+			return false
+		case <-tick.C:
+		}
+	}
+}
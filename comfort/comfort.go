@@ -0,0 +1,229 @@
+// Package comfort computes thermal stress indices — NWS heat index,
+// humidex, an approximate outdoor WBGT and wind chill — from basic weather
+// observations, each with a risk level and guidance for people working
+// outdoors. All inputs and outputs are metric.
+package comfort
+
+import (
+	"math"
+)
+
+// Conditions are the observations the indices are computed from.
+type Conditions struct {
+	TempC     float64
+	RH        float64 // relative humidity, %
+	DewPointC float64
+	WindKmh   float64 // 10 m wind
+	SolarWm2  float64 // global horizontal (shortwave) radiation
+}
+
+// Level grades an index from no stress to extreme.
+type Level int
+
+const (
+	None Level = iota
+	Caution
+	ExtremeCaution
+	Danger
+	ExtremeDanger
+)
+
+// Index is one computed index with its risk reading.
+type Index struct {
+	Name     string  // "Heat Index", "Humidex", "WBGT", "Wind Chill"
+	Value    float64 // °C (humidex is unitless but on the same scale)
+	Applies  bool    // false outside the range the index is defined for
+	Level    Level
+	Label    string // e.g. "Extreme caution"
+	Guidance string // work/rest and protective advice
+}
+
+// In returns Value in °F when unit is "°F", otherwise unchanged.
+func (i Index) In(unit string) float64 {
+	if unit == "°F" {
+		return i.Value*9/5 + 32
+	}
+	return i.Value
+}
+
+// Indices holds every index for one set of conditions.
+type Indices struct {
+	HeatIndex Index
+	Humidex   Index
+	WBGT      Index
+	WindChill Index
+}
+
+// Compute returns all four indices for c.
+func Compute(c Conditions) Indices {
+	return Indices{
+		HeatIndex: heatIndexReading(HeatIndex(c.TempC, c.RH), c.TempC),
+		Humidex:   humidexReading(Humidex(c.TempC, c.DewPointC)),
+		WBGT:      wbgtReading(WBGT(c.TempC, c.RH, c.WindKmh, c.SolarWm2)),
+		WindChill: windChillReading(WindChill(c.TempC, c.WindKmh), c.TempC, c.WindKmh),
+	}
+}
+
+// All returns the indices in display order.
+func (x Indices) All() []Index {
+	return []Index{x.HeatIndex, x.Humidex, x.WBGT, x.WindChill}
+}
+
+// Worst returns the applicable index with the highest level, preferring
+// WBGT on ties since it is the one work/rest schedules are built on. Its
+// Level is None when there is no stress to report.
+func (x Indices) Worst() Index {
+	var worst Index
+	for _, i := range []Index{x.WBGT, x.HeatIndex, x.Humidex, x.WindChill} {
+		if i.Applies && i.Level > worst.Level {
+			worst = i
+		}
+	}
+	return worst
+}
+
+// HeatIndex is the NWS heat index: the Rothfusz regression with its low
+// humidity and high humidity adjustments, falling back to Steadman's simple
+// formula below 80 °F as the NWS does.
+func HeatIndex(tempC, rh float64) float64 {
+	t := tempC*9/5 + 32
+	hi := 0.5 * (t + 61.0 + (t-68.0)*1.2 + rh*0.094)
+	if (hi+t)/2 >= 80 {
+		hi = -42.379 + 2.04901523*t + 10.14333127*rh -
+			0.22475541*t*rh - 0.00683783*t*t - 0.05481717*rh*rh +
+			0.00122874*t*t*rh + 0.00085282*t*rh*rh - 0.00000199*t*t*rh*rh
+		switch {
+		case rh < 13 && t >= 80 && t <= 112:
+			hi -= (13 - rh) / 4 * math.Sqrt((17-math.Abs(t-95))/17)
+		case rh > 85 && t >= 80 && t <= 87:
+			hi += (rh - 85) / 10 * (87 - t) / 5
+		}
+	}
+	return (hi - 32) * 5 / 9
+}
+
+// Humidex is the Environment Canada humidex from temperature and dew point.
+func Humidex(tempC, dewPointC float64) float64 {
+	e := 6.11 * math.Exp(5417.7530*(1/273.16-1/(dewPointC+273.15)))
+	return tempC + 0.5555*(e-10)
+}
+
+// WetBulb is Stull's (2011) psychrometric wet-bulb temperature, good to
+// about ±1 °C for 5–99 % humidity at sea-level pressure.
+func WetBulb(tempC, rh float64) float64 {
+	return tempC*math.Atan(0.151977*math.Sqrt(rh+8.313659)) +
+		math.Atan(tempC+rh) - math.Atan(rh-1.676331) +
+		0.00391838*math.Pow(rh, 1.5)*math.Atan(0.023101*rh) - 4.686035
+}
+
+// WBGT approximates the outdoor wet-bulb globe temperature,
+// 0.7·Tnwb + 0.2·Tg + 0.1·Ta, without a globe thermometer: the natural wet
+// bulb is the psychrometric wet bulb nudged up by sunshine, and the black
+// globe runs above air temperature by an amount that grows with radiation
+// and shrinks with wind. Expect it to be within about 2 °C of a measured
+// WBGT; in the shade (no radiation) it reduces to 0.7·Tw + 0.3·Ta.
+func WBGT(tempC, rh, windKmh, solarWm2 float64) float64 {
+	solar := math.Max(0, solarWm2)
+	windMs := math.Max(0.5, windKmh/3.6) // a globe is never in truly still air
+	nwb := WetBulb(tempC, rh) + 0.0015*solar
+	globe := tempC + 0.019*solar/(1+0.45*math.Sqrt(windMs))
+	return 0.7*nwb + 0.2*globe + 0.1*tempC
+}
+
+// WindChill is the Environment Canada / NWS wind chill index. It is only
+// meaningful at or below 10 °C with wind above walking pace; otherwise it
+// returns the air temperature.
+func WindChill(tempC, windKmh float64) float64 {
+	if tempC > 10 || windKmh < 4.8 {
+		return tempC
+	}
+	v := math.Pow(windKmh, 0.16)
+	return 13.12 + 0.6215*tempC - 11.37*v + 0.3965*tempC*v
+}
+
+func heatIndexReading(hi, tempC float64) Index {
+	i := Index{Name: "Heat Index", Value: hi, Applies: tempC >= 27}
+	switch {
+	case hi >= 54:
+		i.Level, i.Label, i.Guidance = ExtremeDanger, "Extreme danger",
+			"Heat stroke highly likely. Stop strenuous outdoor work."
+	case hi >= 41:
+		i.Level, i.Label, i.Guidance = Danger, "Danger",
+			"Heat cramps and exhaustion likely. Limit outdoor work to short spells with long shaded breaks."
+	case hi >= 32:
+		i.Level, i.Label, i.Guidance = ExtremeCaution, "Extreme caution",
+			"Heat exhaustion possible with prolonged work. Schedule heavy tasks early and drink every 15–20 minutes."
+	case hi >= 27:
+		i.Level, i.Label, i.Guidance = Caution, "Caution",
+			"Fatigue possible with prolonged exposure. Drink water regularly."
+	default:
+		i.Label, i.Guidance = "Comfortable", "No heat stress."
+	}
+	return i
+}
+
+func humidexReading(h float64) Index {
+	i := Index{Name: "Humidex", Value: h, Applies: h >= 25}
+	switch {
+	case h >= 54:
+		i.Level, i.Label, i.Guidance = ExtremeDanger, "Heat stroke imminent",
+			"Stop physical work."
+	case h >= 46:
+		i.Level, i.Label, i.Guidance = Danger, "Dangerous",
+			"Only light work, with frequent breaks in cool shade."
+	case h >= 40:
+		i.Level, i.Label, i.Guidance = ExtremeCaution, "Great discomfort",
+			"Avoid exertion. Reduce work pace and take hourly breaks."
+	case h >= 30:
+		i.Level, i.Label, i.Guidance = Caution, "Some discomfort",
+			"Keep water at hand."
+	default:
+		i.Label, i.Guidance = "Comfortable", "No discomfort."
+	}
+	return i
+}
+
+// wbgtReading follows the US Army TB MED 507 flag categories for moderate
+// work by acclimatised workers, which OSHA points to for outdoor work.
+func wbgtReading(w float64) Index {
+	i := Index{Name: "WBGT", Value: w, Applies: w >= 18}
+	switch {
+	case w >= 32.2:
+		i.Level, i.Label, i.Guidance = ExtremeDanger, "Black flag",
+			"Work 20 min, rest 40 min each hour; drink about 1 L per hour."
+	case w >= 31.1:
+		i.Level, i.Label, i.Guidance = Danger, "Red flag",
+			"Work 30 min, rest 30 min each hour; drink about 0.75 L per hour."
+	case w >= 29.4:
+		i.Level, i.Label, i.Guidance = ExtremeCaution, "Yellow flag",
+			"Work 40 min, rest 20 min each hour; drink about 0.75 L per hour."
+	case w >= 27.8:
+		i.Level, i.Label, i.Guidance = Caution, "Green flag",
+			"Work 50 min, rest 10 min each hour; drink about 0.75 L per hour."
+	default:
+		i.Label, i.Guidance = "White flag", "No work limit. Drink about 0.5 L per hour."
+	}
+	return i
+}
+
+// windChillReading follows Environment Canada's wind chill risk bands.
+func windChillReading(wc, tempC, windKmh float64) Index {
+	i := Index{Name: "Wind Chill", Value: wc, Applies: tempC <= 10 && windKmh >= 4.8}
+	switch {
+	case wc <= -48:
+		i.Level, i.Label, i.Guidance = ExtremeDanger, "Extreme risk",
+			"Exposed skin freezes in under 5 minutes. Postpone outdoor work."
+	case wc <= -40:
+		i.Level, i.Label, i.Guidance = Danger, "Very high risk",
+			"Exposed skin freezes in 5–10 minutes. Work in pairs and warm up indoors every 30 minutes."
+	case wc <= -28:
+		i.Level, i.Label, i.Guidance = ExtremeCaution, "High risk",
+			"Frostbite in 10–30 minutes. Cover all skin and take warm-up breaks every hour."
+	case wc <= -10:
+		i.Level, i.Label, i.Guidance = Caution, "Moderate risk",
+			"Cover up: hat, gloves, insulated layers. Watch for numbness."
+	default:
+		i.Label, i.Guidance = "Low risk", "Dress for the cold."
+	}
+	return i
+}
@@ -0,0 +1,245 @@
+// Package i18n is the message catalog for user-facing text. Messages are
+// keyed by their English source string (or fmt format), gettext style, so
+// English needs no catalog and a missing translation falls back to English.
+// Locales also carry the names and layouts used to format dates and the
+// decimal separator used to format numbers.
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Default is the locale used when nothing else matches.
+const Default = "en"
+
+// Locale is one language's catalog and formatting rules, loaded from
+// locales/<tag>.json.
+type Locale struct {
+	Tag         string            `json:"-"`
+	Name        string            `json:"name"`    // in its own language, e.g. "Deutsch"
+	Decimal     string            `json:"decimal"` // "." or ","
+	Days        [7]string         `json:"days"`    // Sunday first, like time.Weekday
+	ShortDays   [7]string         `json:"short_days"`
+	Months      [12]string        `json:"months"`
+	ShortMonths [12]string        `json:"short_months"`
+	Layouts     map[string]string `json:"layouts"`  // Go time layouts in English order → this locale's
+	Messages    map[string]string `json:"messages"` // English → translation
+
+	dates *strings.Replacer
+}
+
+//go:embed locales/*.json
+var localeFS embed.FS
+
+var locales = map[string]*Locale{}
+
+func init() {
+	files, err := localeFS.ReadDir("locales")
+	if err != nil {
+		panic(err)
+	}
+	for _, f := range files {
+		b, err := localeFS.ReadFile(path.Join("locales", f.Name()))
+		if err != nil {
+			panic(err)
+		}
+		l := &Locale{Tag: strings.TrimSuffix(f.Name(), ".json")}
+		if err := json.Unmarshal(b, l); err != nil {
+			panic(fmt.Sprintf("i18n: %s: %v", f.Name(), err))
+		}
+		l.dates = l.dateReplacer()
+		locales[l.Tag] = l
+	}
+	if locales[Default] == nil {
+		panic("i18n: no " + Default + " locale")
+	}
+}
+
+// Get returns the locale for tag, or the default locale.
+func Get(tag string) *Locale {
+	if l, ok := locales[tag]; ok {
+		return l
+	}
+	return locales[Default]
+}
+
+// Tags lists the supported locale tags, sorted.
+func Tags() []string {
+	tags := make([]string, 0, len(locales))
+	for t := range locales {
+		tags = append(tags, t)
+	}
+	sort.Strings(tags)
+	return tags
+}
+
+// Locales lists the supported locales, sorted by tag.
+func Locales() []*Locale {
+	var ls []*Locale
+	for _, t := range Tags() {
+		ls = append(ls, locales[t])
+	}
+	return ls
+}
+
+// preference is one language range from an Accept-Language header.
+type preference struct {
+	tag    string // lower case, e.g. "en-us"
+	weight float64
+}
+
+// parsePreferences reads a tag ("de", "de_DE.UTF-8") or an Accept-Language
+// header ("de-CH,de;q=0.9,en;q=0.8"), best first.
+func parsePreferences(s string) []preference {
+	var prefs []preference
+	for _, part := range strings.Split(s, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		tag, _, _ = strings.Cut(tag, ".") // POSIX locales: "de_DE.UTF-8"
+		tag = strings.ToLower(strings.ReplaceAll(strings.TrimSpace(tag), "_", "-"))
+		if tag == "" || tag == "*" || tag == "c" || tag == "posix" {
+			continue
+		}
+		w := 1.0
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if v, err := strconv.ParseFloat(q, 64); err == nil {
+				w = v
+			}
+		}
+		if w > 0 {
+			prefs = append(prefs, preference{tag, w})
+		}
+	}
+	sort.SliceStable(prefs, func(i, j int) bool { return prefs[i].weight > prefs[j].weight })
+	return prefs
+}
+
+// Match picks the supported locale for the first of prefs that names one.
+// Each pref may be a tag or an Accept-Language header; empty ones are
+// skipped, so callers can pass ?lang=, a cookie and the header in order.
+func Match(prefs ...string) string {
+	for _, s := range prefs {
+		for _, p := range parsePreferences(s) {
+			base, _, _ := strings.Cut(p.tag, "-")
+			if _, ok := locales[base]; ok {
+				return base
+			}
+		}
+	}
+	return Default
+}
+
+// imperialRegions use °F and mph by default.
+var imperialRegions = map[string]bool{"us": true, "lr": true, "mm": true}
+
+// Units picks "imperial" when the first preference that names a region is
+// in one that uses it, else "metric". prefs are read as for Match.
+func Units(prefs ...string) string {
+	for _, s := range prefs {
+		for _, p := range parsePreferences(s) {
+			if _, region, ok := strings.Cut(p.tag, "-"); ok {
+				if imperialRegions[region] {
+					return "imperial"
+				}
+				return "metric"
+			}
+		}
+	}
+	return "metric"
+}
+
+// T translates msg into lang, or returns it unchanged.
+func T(lang, msg string) string {
+	if s, ok := Get(lang).Messages[msg]; ok && s != "" {
+		return s
+	}
+	return msg
+}
+
+// Sprintf translates format into lang and formats args with it. Floats are
+// written with the locale's decimal separator.
+func Sprintf(lang, format string, args ...any) string {
+	l := Get(lang)
+	if l.Decimal != "" && l.Decimal != "." {
+		args = append([]any(nil), args...)
+		for i, a := range args {
+			if f, ok := a.(float64); ok {
+				args[i] = localFloat{f, l.Decimal}
+			}
+		}
+	}
+	return fmt.Sprintf(T(lang, format), args...)
+}
+
+// localFloat formats like a float64 but with another decimal separator.
+type localFloat struct {
+	v       float64
+	decimal string
+}
+
+func (f localFloat) Format(s fmt.State, verb rune) {
+	spec := "%"
+	for _, flag := range "+-# 0" {
+		if s.Flag(int(flag)) {
+			spec += string(flag)
+		}
+	}
+	if w, ok := s.Width(); ok {
+		spec += strconv.Itoa(w)
+	}
+	if p, ok := s.Precision(); ok {
+		spec += "." + strconv.Itoa(p)
+	}
+	fmt.Fprint(s, strings.Replace(fmt.Sprintf(spec+string(verb), f.v), ".", f.decimal, 1))
+}
+
+// Number formats v with prec decimals in lang, e.g. "12,5" in German.
+func Number(lang string, v float64, prec int) string {
+	s := strconv.FormatFloat(v, 'f', prec, 64)
+	if d := Get(lang).Decimal; d != "" && d != "." {
+		s = strings.Replace(s, ".", d, 1)
+	}
+	return s
+}
+
+// Date formats t with an English-order Go layout, using the locale's own
+// layout for it when it has one and its day and month names.
+func Date(lang string, t time.Time, layout string) string {
+	l := Get(lang)
+	if alt, ok := l.Layouts[layout]; ok {
+		layout = alt
+	}
+	s := t.Format(layout)
+	if l.dates != nil {
+		s = l.dates.Replace(s)
+	}
+	return s
+}
+
+// dateReplacer maps English day and month names to the locale's, full
+// names before abbreviations so "Monday" never becomes "Moday".
+func (l *Locale) dateReplacer() *strings.Replacer {
+	if l.Days[0] == "" {
+		return nil
+	}
+	var pairs []string
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		pairs = append(pairs, d.String(), l.Days[d])
+	}
+	for m := time.January; m <= time.December; m++ {
+		pairs = append(pairs, m.String(), l.Months[m-1])
+	}
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		pairs = append(pairs, d.String()[:3], l.ShortDays[d])
+	}
+	for m := time.January; m <= time.December; m++ {
+		pairs = append(pairs, m.String()[:3], l.ShortMonths[m-1])
+	}
+	return strings.NewReplacer(pairs...)
+}
@@ -7,11 +7,17 @@ import (
 	"log"
 	"math"
 	"net/http"
+	"net/url"
 	"os"
+	"slices"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
+	"unicode"
 
+	"WeatherApp/astro"
+	"WeatherApp/i18n"
 	"WeatherApp/weather"
 )
 
@@ -32,6 +38,11 @@ var outfitIcons = map[string]template.HTML{
 	"hat":         `<svg viewBox="0 0 24 24" fill="none" stroke="currentColor" stroke-width="1.8" stroke-linecap="round" stroke-linejoin="round"><ellipse cx="12" cy="17" rx="9" ry="2.5"/><path d="M8 17V9a4 4 0 0 1 8 0v8"/></svg>`,
 	"boots":       `<svg viewBox="0 0 24 24" fill="none" stroke="currentColor" stroke-width="1.8" stroke-linecap="round" stroke-linejoin="round"><path d="M6 4h6v10l4 2v4H4v-4l2-2V4z"/><path d="M8 4h4"/><path d="M4 20h12"/></svg>`,
 	"sandals":     `<svg viewBox="0 0 24 24" fill="none" stroke="currentColor" stroke-width="1.8" stroke-linecap="round" stroke-linejoin="round"><rect x="3" y="16" width="18" height="4" rx="2"/><path d="M7 16v-3"/><path d="M12 16V10"/><path d="M17 16v-3"/><path d="M7 13h10"/></svg>`,
+	// Activity gear
+	"hivis":  `<svg viewBox="0 0 24 24" fill="none" stroke="currentColor" stroke-width="1.8" stroke-linecap="round" stroke-linejoin="round"><path d="M7 3l-4 4v13h6V9l3 3 3-3v11h6V7l-4-4-5 5-5-5z"/><line x1="3" y1="14" x2="9" y2="14"/><line x1="15" y1="14" x2="21" y2="14"/></svg>`,
+	"gloves": `<svg viewBox="0 0 24 24" fill="none" stroke="currentColor" stroke-width="1.8" stroke-linecap="round" stroke-linejoin="round"><path d="M7 21v-5l-3-4 2-1 3 2V5a1 1 0 0 1 2 0v5-6a1 1 0 0 1 2 0v6-5a1 1 0 0 1 2 0v5-3a1 1 0 0 1 2 0v9l-2 5v4z"/></svg>`,
+	"lights": `<svg viewBox="0 0 24 24" fill="none" stroke="currentColor" stroke-width="1.8" stroke-linecap="round" stroke-linejoin="round"><circle cx="12" cy="12" r="4"/><path d="M12 2v3m0 14v3M2 12h3m14 0h3M5 5l2 2m10 10 2 2M5 19l2-2m10-10 2-2"/></svg>`,
+	"bottle": `<svg viewBox="0 0 24 24" fill="none" stroke="currentColor" stroke-width="1.8" stroke-linecap="round" stroke-linejoin="round"><path d="M10 2h4v3l2 3v12a2 2 0 0 1-2 2h-4a2 2 0 0 1-2-2V8l2-3V2z"/><line x1="8" y1="12" x2="16" y2="12"/></svg>`,
 }
 
 // outfitIconFallback is returned when an icon key is not found.
@@ -103,8 +114,10 @@ func moonPhaseSVG(phase float64) template.HTML {
 var tmpl = template.Must(
 	template.New("").Funcs(template.FuncMap{
 		// Math funcs use float64 for smooth SVG positioning.
-		"subf": func(a, b float64) float64 { return a - b },
-		"mulf": func(a, b float64) float64 { return a * b },
+		"subf":  func(a, b float64) float64 { return a - b },
+		"mulf":  func(a, b float64) float64 { return a * b },
+		"lower": strings.ToLower,
+		"join":  strings.Join,
 		// arcY: maps 0-100 progress to SVG y on a half-ellipse
 		// viewBox 0 0 400 80, horizon y=72, semi-axes rx=190 ry=72
 		"arcY": func(pct float64) float64 {
@@ -118,6 +131,7 @@ var tmpl = template.Must(
 		},
 		"not":          func(b bool) bool { return !b },
 		"uvLevel":      weather.UVLevel,
+		"uvAdvice":     weather.UVAdvice,
 		"uvColorClass": weather.UVColorClass,
 		"windCompass":  weather.WindCompass,
 		"moonPhaseSVG": func(phase float64) template.HTML { return moonPhaseSVG(phase) },
@@ -212,6 +226,261 @@ var tmpl = template.Must(
 			sb.WriteString(`</svg>`)
 			return template.HTML(sb.String())
 		},
+		// uvCurveSVG draws the day's UV index as bars coloured by level, with
+		// the safe threshold dashed and hours already gone faded.
+		"uvCurveSVG": func(x *weather.UVExposure) template.HTML {
+			if x == nil || len(x.Hours) == 0 {
+				return ""
+			}
+			const (
+				slot     = 30
+				barW     = 27
+				baseline = 70
+				maxBarH  = 60
+				labelY   = 88
+				viewH    = 92
+			)
+			top := math.Max(12, x.Peak.UV)
+			barColor := func(uv float64) string {
+				switch {
+				case uv >= 11:
+					return "#a855f7"
+				case uv >= 8:
+					return "#ef4444"
+				case uv >= 6:
+					return "#f97316"
+				case uv >= 3:
+					return "#facc15"
+				default:
+					return "#4ade80"
+				}
+			}
+			n := len(x.Hours)
+			viewW := n * slot
+			var sb strings.Builder
+			sb.Grow(4096)
+			fmt.Fprintf(&sb, `<svg viewBox="0 0 %d %d" width="100%%" preserveAspectRatio="none" class="precip-svg" aria-label="Hourly UV index">`, viewW, viewH)
+			ty := baseline - int(x.SafeBelow/top*maxBarH)
+			fmt.Fprintf(&sb, `<line x1="0" y1="%d" x2="%d" y2="%d" class="pchart-grid"/>`, ty, viewW, ty)
+			fmt.Fprintf(&sb, `<text x="2" y="%d" class="pchart-lbl" text-anchor="start">UV %.0f</text>`, ty-2, x.SafeBelow)
+			for i, h := range x.Hours {
+				bh := int(h.UV / top * maxBarH)
+				if bh == 0 {
+					bh = 1
+				}
+				bx := i * slot
+				opacity := "1"
+				if h.Past {
+					opacity = ".35"
+				}
+				title := fmt.Sprintf("%s · UV %.1f", h.Time, h.UV)
+				if b := x.Burn(h); b > 0 && h.UV >= 1 {
+					title += " · burn in " + weather.FormatExposure(b)
+				}
+				fmt.Fprintf(&sb, `<rect x="%d" y="%d" width="%d" height="%d" fill="%s" opacity="%s" rx="2"><title>%s</title></rect>`,
+					bx, baseline-bh, barW, bh, barColor(h.UV), opacity, template.HTMLEscapeString(title))
+				if i%3 == 0 {
+					fmt.Fprintf(&sb, `<text x="%d" y="%d" class="pchart-lbl" text-anchor="middle">%s</text>`,
+						bx+slot/2, labelY, h.Time)
+				}
+			}
+			fmt.Fprintf(&sb, `<line x1="0" y1="%d" x2="%d" y2="%d" class="pchart-base"/>`,
+				baseline, viewW, baseline)
+			sb.WriteString(`</svg>`)
+			return template.HTML(sb.String())
+		},
+		// waterBalanceSVG draws each day's rain above the baseline and its
+		// evapotranspiration below it; forecast days are paler.
+		"waterBalanceSVG": func(g *weather.GardenReport) template.HTML {
+			if g == nil || len(g.Days) == 0 {
+				return ""
+			}
+			const (
+				slot     = 40
+				barW     = 16
+				baseline = 50
+				maxBarH  = 42
+				labelY   = 106
+				viewH    = 110
+			)
+			top := 5.0 // mm
+			for _, d := range g.Days {
+				top = math.Max(top, math.Max(d.Precip, d.ET0))
+			}
+			viewW := len(g.Days) * slot
+			var sb strings.Builder
+			sb.Grow(4096)
+			fmt.Fprintf(&sb, `<svg viewBox="0 0 %d %d" width="100%%" preserveAspectRatio="none" class="precip-svg" aria-label="Daily water balance">`, viewW, viewH)
+			for i, d := range g.Days {
+				bx := i*slot + (slot-2*barW)/2
+				opacity := "1"
+				if d.Forecast {
+					opacity = ".5"
+				}
+				rh := int(d.Precip / top * maxBarH)
+				eh := int(d.ET0 / top * maxBarH)
+				title := fmt.Sprintf("%s · rain %.1f %s · ET0 %.1f %s · balance %+.1f %s", d.Date,
+					g.Depth(d.Precip), g.DepthUnit(), g.Depth(d.ET0), g.DepthUnit(), g.Depth(d.Balance), g.DepthUnit())
+				fmt.Fprintf(&sb, `<g opacity="%s"><title>%s</title>`, opacity, template.HTMLEscapeString(title))
+				fmt.Fprintf(&sb, `<rect x="%d" y="%d" width="%d" height="%d" fill="#3b82f6" rx="2"/>`, bx, baseline-rh, barW, rh)
+				fmt.Fprintf(&sb, `<rect x="%d" y="%d" width="%d" height="%d" fill="#f97316" rx="2"/>`, bx+barW, baseline, barW, eh)
+				sb.WriteString(`</g>`)
+				label := d.Date[8:]
+				if d.Today {
+					label = "today"
+				}
+				fmt.Fprintf(&sb, `<text x="%d" y="%d" class="pchart-lbl" text-anchor="middle">%s</text>`, i*slot+slot/2, labelY, label)
+			}
+			fmt.Fprintf(&sb, `<line x1="0" y1="%d" x2="%d" y2="%d" class="pchart-base"/>`, baseline, viewW, baseline)
+			sb.WriteString(`</svg>`)
+			return template.HTML(sb.String())
+		},
+		// solarSVG draws hourly PV output as an area across the forecast
+		// days, with each day's total above it and a line at the current hour.
+		"solarSVG": func(f *weather.SolarForecast) template.HTML {
+			if f == nil || len(f.Days) == 0 {
+				return ""
+			}
+			const (
+				step     = 4 // per hour
+				baseline = 96
+				maxH     = 72
+				viewH    = 110
+			)
+			top := f.PeakKW()
+			viewW := len(f.Days) * 24 * step
+			var sb strings.Builder
+			sb.Grow(8192)
+			fmt.Fprintf(&sb, `<svg viewBox="0 0 %d %d" width="100%%" preserveAspectRatio="none" class="precip-svg" aria-label="Hourly solar output">`, viewW, viewH)
+			fmt.Fprintf(&sb, `<path d="M0 %d`, baseline)
+			x := 0
+			for _, d := range f.Days {
+				for _, h := range d.Hours {
+					fmt.Fprintf(&sb, ` L%d %.1f`, x+step/2, baseline-h.KWh/top*maxH)
+					x += step
+				}
+			}
+			fmt.Fprintf(&sb, ` L%d %d Z" fill="#facc15" fill-opacity=".55" stroke="#ca8a04" stroke-width="1.5" stroke-linejoin="round"/>`, x, baseline)
+			x = 0
+			for i, d := range f.Days {
+				if i > 0 {
+					fmt.Fprintf(&sb, `<line x1="%d" y1="4" x2="%d" y2="%d" stroke="currentColor" stroke-opacity=".15"/>`, x, x, baseline)
+				}
+				title := fmt.Sprintf("%s · %.1f kWh · peak %.2f kW at %s", d.Date, d.KWh, d.PeakKW, d.PeakAt)
+				fmt.Fprintf(&sb, `<g><title>%s</title><rect x="%d" y="0" width="%d" height="%d" fill="transparent"/></g>`,
+					template.HTMLEscapeString(title), x, 24*step, baseline)
+				fmt.Fprintf(&sb, `<text x="%d" y="%d" class="pchart-lbl" text-anchor="middle">%s · %.1f kWh</text>`,
+					x+12*step, 12, template.HTMLEscapeString(d.Label), d.KWh)
+				for j, h := range d.Hours {
+					if h.Time[:13] == f.Now[:13] {
+						nx := x + j*step + step/2
+						fmt.Fprintf(&sb, `<line x1="%d" y1="18" x2="%d" y2="%d" stroke="#ef4444" stroke-width="1.5" stroke-dasharray="3 2"/>`, nx, nx, baseline)
+					}
+				}
+				x += 24 * step
+			}
+			fmt.Fprintf(&sb, `<line x1="0" y1="%d" x2="%d" y2="%d" class="pchart-base"/>`, baseline, viewW, baseline)
+			sb.WriteString(`</svg>`)
+			return template.HTML(sb.String())
+		},
+		"monthDay":    weather.MonthDay,
+		"fmtExposure": weather.FormatExposure,
+		"clock":       astro.Clock,
+		// dayPct and spanPct place times and spans on a 24-hour track.
+		"dayPct": func(t time.Time) float64 {
+			return (float64(t.Hour()) + float64(t.Minute())/60) / 24 * 100
+		},
+		"spanPct": func(s astro.Span) float64 {
+			from := (float64(s.From.Hour()) + float64(s.From.Minute())/60) / 24 * 100
+			return math.Max(0, math.Min(s.To.Sub(s.From).Hours()/24*100, 100-from))
+		},
+		"span": func(from, to time.Time) astro.Span { return astro.Span{From: from, To: to} },
+		// bestStarNight and starLevel pick the highlighted night and the
+		// 0–4 colour step for a stargazing score.
+		"bestStarNight": weather.BestStarNight,
+		"starLevel": func(score int) int {
+			return min(4, score/20)
+		},
+		// hourlyTempSVG draws the hourly temperature line with the model spread
+		// (min–max across consensus models) as a shaded uncertainty ribbon.
+		"hourlyTempSVG": func(hourly []weather.HourlyPoint, unit string) template.HTML {
+			n := len(hourly)
+			if n > 24 {
+				n = 24
+			}
+			if n < 2 {
+				return ""
+			}
+			const (
+				slot   = 30  // px per hour, matches the precipitation chart
+				top    = 8   // y of the warmest value
+				bottom = 70  // y of the coldest value
+				labelY = 88  // y of time labels
+				viewH  = 92  // total SVG height
+				viewW  = 720 // 24 × 30
+			)
+			lo, hi := hourly[0].Temp, hourly[0].Temp
+			for _, h := range hourly[:n] {
+				lo = math.Min(lo, h.Temp)
+				hi = math.Max(hi, h.Temp)
+				if h.TempBand.Models > 0 {
+					lo = math.Min(lo, h.TempBand.Min)
+					hi = math.Max(hi, h.TempBand.Max)
+				}
+			}
+			if hi-lo < 1 {
+				hi, lo = hi+0.5, lo-0.5
+			}
+			y := func(t float64) float64 { return bottom - (t-lo)/(hi-lo)*(bottom-top) }
+			x := func(i int) float64 { return float64(i*slot + slot/2) }
+
+			var sb strings.Builder
+			sb.Grow(4096)
+			fmt.Fprintf(&sb, `<svg viewBox="0 0 %d %d" width="100%%" preserveAspectRatio="none" class="precip-svg" aria-label="Hourly temperature with model spread">`, viewW, viewH)
+
+			// Ribbon: upper edge left→right, lower edge right→left. Hours
+			// without a band collapse onto the line.
+			var upper, lower []string
+			for i, h := range hourly[:n] {
+				bmax, bmin := h.Temp, h.Temp
+				if h.TempBand.Models > 0 {
+					bmax, bmin = h.TempBand.Max, h.TempBand.Min
+				}
+				upper = append(upper, fmt.Sprintf("%.1f,%.1f", x(i), y(bmax)))
+				lower = append([]string{fmt.Sprintf("%.1f,%.1f", x(i), y(bmin))}, lower...)
+			}
+			fmt.Fprintf(&sb, `<polygon points="%s %s" class="tchart-band"/>`,
+				strings.Join(upper, " "), strings.Join(lower, " "))
+
+			var line []string
+			for i, h := range hourly[:n] {
+				line = append(line, fmt.Sprintf("%.1f,%.1f", x(i), y(h.Temp)))
+			}
+			fmt.Fprintf(&sb, `<polyline points="%s" class="tchart-line"/>`, strings.Join(line, " "))
+
+			for i, h := range hourly[:n] {
+				if i%4 != 0 {
+					continue
+				}
+				fmt.Fprintf(&sb, `<text x="%.0f" y="%.1f" class="pchart-lbl" text-anchor="middle">%.0f%s</text>`,
+					x(i), y(h.Temp)-4, h.Temp, unit)
+				fmt.Fprintf(&sb, `<text x="%.0f" y="%d" class="pchart-lbl" text-anchor="middle">%s</text>`,
+					x(i), labelY, h.Time)
+			}
+			sb.WriteString(`</svg>`)
+			return template.HTML(sb.String())
+		},
+		// confColor maps a consensus confidence label to the agreement palette.
+		"confColor": func(label string) string {
+			switch label {
+			case "High":
+				return "#22c55e"
+			case "Moderate":
+				return "#f59e0b"
+			default:
+				return "#ef4444"
+			}
+		},
 		"agreeColor": func(pct int) string {
 			switch {
 			case pct >= 80:
@@ -237,93 +506,519 @@ var tmpl = template.Must(
 			}
 			return v
 		},
-	}).ParseFiles("templates/index.html"),
+		"seriesColor": func(i int) string { return seriesColors[i%len(seriesColors)] },
+		// compareTempSVG draws each city's next 24 hours on one temperature
+		// scale, so the lines can be read against each other.
+		"compareTempSVG": func(cmp *weather.Comparison, lang string) template.HTML {
+			const (
+				slot   = 30
+				top    = 10
+				bottom = 130
+				labelY = 150
+				viewH  = 156
+				viewW  = 720
+			)
+			lo, hi := math.Inf(1), math.Inf(-1)
+			for _, c := range cmp.Cities {
+				if c.Info == nil {
+					continue
+				}
+				for _, h := range c.Info.Hourly[:min(24, len(c.Info.Hourly))] {
+					lo, hi = math.Min(lo, h.Temp), math.Max(hi, h.Temp)
+				}
+			}
+			if math.IsInf(lo, 1) {
+				return ""
+			}
+			if hi-lo < 1 {
+				hi, lo = hi+0.5, lo-0.5
+			}
+			y := func(t float64) float64 { return bottom - (t-lo)/(hi-lo)*(bottom-top) }
+			x := func(i int) float64 { return float64(i*slot + slot/2) }
+
+			var sb strings.Builder
+			fmt.Fprintf(&sb, `<svg viewBox="0 0 %d %d" width="100%%" preserveAspectRatio="none" class="precip-svg" aria-label="Hourly temperature by city">`, viewW, viewH)
+			for _, t := range []float64{hi, (hi + lo) / 2, lo} {
+				fmt.Fprintf(&sb, `<line x1="0" x2="%d" y1="%.1f" y2="%.1f" class="cmp-grid"/>`, viewW, y(t), y(t))
+				fmt.Fprintf(&sb, `<text x="2" y="%.1f" class="pchart-lbl" text-anchor="start">%.0f&#176;</text>`, y(t)-3, t)
+			}
+			for ci, c := range cmp.Cities {
+				if c.Info == nil {
+					continue
+				}
+				var line []string
+				for i, h := range c.Info.Hourly[:min(24, len(c.Info.Hourly))] {
+					line = append(line, fmt.Sprintf("%.1f,%.1f", x(i), y(h.Temp)))
+				}
+				fmt.Fprintf(&sb, `<polyline points="%s" class="cmp-line" stroke="%s"/>`,
+					strings.Join(line, " "), seriesColors[ci%len(seriesColors)])
+			}
+			for i := 0; i < 24; i += 6 {
+				label := i18n.T(lang, "now")
+				if i > 0 {
+					label = fmt.Sprintf("+%dh", i)
+				}
+				fmt.Fprintf(&sb, `<text x="%.0f" y="%d" class="pchart-lbl" text-anchor="middle">%s</text>`, x(i), labelY, label)
+			}
+			sb.WriteString(`</svg>`)
+			return template.HTML(sb.String())
+		},
+		// compareDailySVG draws one low–high bar per city for each date,
+		// grouped by date on a shared scale.
+		"compareDailySVG": func(cmp *weather.Comparison, lang string) template.HTML {
+			if len(cmp.Dates) == 0 {
+				return ""
+			}
+			const (
+				top    = 18
+				bottom = 150
+				labelY = 170
+				viewH  = 176
+				viewW  = 720
+			)
+			lo, hi := math.Inf(1), math.Inf(-1)
+			for _, c := range cmp.Cities {
+				for _, date := range cmp.Dates {
+					if d := c.Day(date); d != nil {
+						lo, hi = math.Min(lo, d.TempMin), math.Max(hi, d.TempMax)
+					}
+				}
+			}
+			if math.IsInf(lo, 1) {
+				return ""
+			}
+			if hi-lo < 1 {
+				hi, lo = hi+0.5, lo-0.5
+			}
+			y := func(t float64) float64 { return bottom - (t-lo)/(hi-lo)*(bottom-top) }
+			group := float64(viewW) / float64(len(cmp.Dates))
+			bar := group * 0.7 / float64(len(cmp.Cities))
+
+			var sb strings.Builder
+			fmt.Fprintf(&sb, `<svg viewBox="0 0 %d %d" width="100%%" preserveAspectRatio="none" class="precip-svg" aria-label="Daily highs and lows by city">`, viewW, viewH)
+			for gi, date := range cmp.Dates {
+				x0 := float64(gi)*group + group*0.15
+				for ci, c := range cmp.Cities {
+					d := c.Day(date)
+					if d == nil {
+						continue
+					}
+					x := x0 + float64(ci)*bar
+					fmt.Fprintf(&sb, `<rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" rx="3" fill="%s"><title>%s: %.0f&#176; / %.0f&#176;</title></rect>`,
+						x+1, y(d.TempMax), bar-2, math.Max(2, y(d.TempMin)-y(d.TempMax)), seriesColors[ci%len(seriesColors)],
+						template.HTMLEscapeString(c.Name()), d.TempMax, d.TempMin)
+					fmt.Fprintf(&sb, `<text x="%.1f" y="%.1f" class="pchart-lbl" text-anchor="middle">%.0f</text>`, x+bar/2, y(d.TempMax)-3, d.TempMax)
+				}
+				label := date
+				if t, err := time.Parse("2006-01-02", date); err == nil {
+					label = i18n.Date(lang, t, "Mon 2")
+				}
+				fmt.Fprintf(&sb, `<text x="%.1f" y="%d" class="pchart-lbl" text-anchor="middle">%s</text>`, float64(gi)*group+group/2, labelY, label)
+			}
+			sb.WriteString(`</svg>`)
+			return template.HTML(sb.String())
+		},
+	}).Funcs(localeFuncs(i18n.Default)).ParseFiles("templates/index.html", "templates/pack.html", "templates/compare.html"),
 )
 
+// seriesColors tell cities apart in the comparison charts.
+var seriesColors = []string{"#ff3e00", "#2563eb", "#16a34a", "#9333ea", "#f59e0b", "#0891b2"}
+
+// localeFuncs are the template funcs that depend on the page's locale:
+// t translates a message, tf a format, num and date write numbers and
+// "2006-01-02" dates the locale's way.
+func localeFuncs(lang string) template.FuncMap {
+	return template.FuncMap{
+		"lang": func() string { return lang },
+		"t":    func(msg string) string { return i18n.T(lang, msg) },
+		"tf":   func(format string, args ...any) string { return i18n.Sprintf(lang, format, args...) },
+		"num":  func(v float64, prec int) string { return i18n.Number(lang, v, prec) },
+		"date": func(date, layout string) string {
+			d, err := time.Parse("2006-01-02", date)
+			if err != nil {
+				return date
+			}
+			return i18n.Date(lang, d, layout)
+		},
+	}
+}
+
+// pages holds a clone of tmpl per locale with localeFuncs bound to it.
+// tmpl itself is never executed, so it can always be cloned.
+var pages = func() map[string]*template.Template {
+	m := make(map[string]*template.Template)
+	for _, tag := range i18n.Tags() {
+		m[tag] = template.Must(tmpl.Clone()).Funcs(localeFuncs(tag))
+	}
+	return m
+}()
+
+// page returns the templates for lang.
+func page(lang string) *template.Template {
+	if t, ok := pages[lang]; ok {
+		return t
+	}
+	return pages[i18n.Default]
+}
+
 const (
 	cacheTTL     = 10 * time.Minute
 	cacheMaxSize = 200             // max entries before oldest-first eviction
 	cacheCleanup = 5 * time.Minute // how often to sweep expired entries
 )
 
-type cacheEntry struct {
-	info      *weather.WeatherInfo
+type cacheEntry[V any] struct {
+	val       V
 	expires   time.Time
 	createdAt time.Time
 }
 
-var (
-	cacheMu sync.RWMutex
-	cache   = make(map[string]*cacheEntry)
-)
+// ttlCache keeps values for cacheTTL and holds at most cacheMaxSize of
+// them, evicting the oldest first; startCacheCleanup sweeps out the expired.
+type ttlCache[V any] struct {
+	mu      sync.RWMutex
+	entries map[string]*cacheEntry[V]
+}
 
-func cacheKey(city, units string) string { return strings.ToLower(city) + "|" + units }
+func newTTLCache[V any]() *ttlCache[V] {
+	return &ttlCache[V]{entries: make(map[string]*cacheEntry[V])}
+}
 
-func cacheGet(key string) *weather.WeatherInfo {
-	cacheMu.RLock()
-	defer cacheMu.RUnlock()
-	if e, ok := cache[key]; ok && time.Now().Before(e.expires) {
-		return e.info
+func (c *ttlCache[V]) get(key string) (V, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if e, ok := c.entries[key]; ok && time.Now().Before(e.expires) {
+		return e.val, true
 	}
-	return nil
+	var zero V
+	return zero, false
 }
 
-func cacheSet(key string, info *weather.WeatherInfo) {
-	cacheMu.Lock()
-	defer cacheMu.Unlock()
+func (c *ttlCache[V]) set(key string, val V) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 
 	// Evict oldest entry if at capacity (before inserting new one).
-	if _, exists := cache[key]; !exists && len(cache) >= cacheMaxSize {
+	if _, exists := c.entries[key]; !exists && len(c.entries) >= cacheMaxSize {
 		var oldest string
 		var oldestTime time.Time
-		for k, e := range cache {
+		for k, e := range c.entries {
 			if oldest == "" || e.createdAt.Before(oldestTime) {
 				oldest = k
 				oldestTime = e.createdAt
 			}
 		}
-		delete(cache, oldest)
+		delete(c.entries, oldest)
 	}
 
-	cache[key] = &cacheEntry{
-		info:      info,
+	c.entries[key] = &cacheEntry[V]{
+		val:       val,
 		expires:   time.Now().Add(cacheTTL),
 		createdAt: time.Now(),
 	}
 }
 
+// sweep removes the entries that expired before now.
+func (c *ttlCache[V]) sweep(now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for k, e := range c.entries {
+		if now.After(e.expires) {
+			delete(c.entries, k)
+		}
+	}
+}
+
+var (
+	cache = newTTLCache[*weather.WeatherInfo]()
+
+	// gardenCache keeps garden reports, which take up to four requests
+	// including months of hourly history, out of every page load.
+	gardenCache = newTTLCache[*weather.GardenReport]()
+
+	// solarCache keeps PV forecasts, so a home automation system polling
+	// /api/v1/solar does not refetch the irradiance every time.
+	solarCache = newTTLCache[*weather.SolarForecast]()
+
+	// sweptCaches are the caches startCacheCleanup keeps in check.
+	sweptCaches = []interface{ sweep(time.Time) }{cache, gardenCache, solarCache}
+)
+
+func cacheKey(city, units, lang string) string {
+	return strings.ToLower(city) + "|" + units + "|" + lang
+}
+
+func cacheGet(key string) *weather.WeatherInfo {
+	info, _ := cache.get(key)
+	return info
+}
+
+func cacheSet(key string, info *weather.WeatherInfo) {
+	cache.set(key, info)
+}
+
 // startCacheCleanup launches a background goroutine that periodically removes
-// expired entries so the map does not grow without bound.
+// expired entries so the maps do not grow without bound.
 func startCacheCleanup() {
 	go func() {
 		ticker := time.NewTicker(cacheCleanup)
 		defer ticker.Stop()
 		for range ticker.C {
 			now := time.Now()
-			cacheMu.Lock()
-			for k, e := range cache {
-				if now.After(e.expires) {
-					delete(cache, k)
-				}
+			for _, c := range sweptCaches {
+				c.sweep(now)
 			}
-			cacheMu.Unlock()
 		}
 	}()
 }
 
 type PageData struct {
-	City   string
-	Units  string
-	Info   *weather.WeatherInfo
-	Alerts []weather.Alert
-	Quote  string
-	Advice string
-	Error  string
+	City      string
+	Units     string
+	Info      *weather.WeatherInfo
+	Alerts    []weather.Alert
+	Quote     string
+	Advice    string
+	Narrative string // lead paragraph from the hourly and daily forecast
+	Error     string
+
+	// Lang is the page's locale, from ?lang= (saved in a cookie), the
+	// cookie, or Accept-Language; Locales fill the language switcher.
+	Lang    string
+	Locales []*i18n.Locale
+
+	// Activity is the ?activity= key; Outfit is built for it per request
+	// because Info is shared through the cache.
+	Activity   string
+	Activities []weather.Activity
+	Outfit     weather.OutfitAdvice
+
+	// Comfort is this browser's profile from the comfort cookie; feedback
+	// forms post to /comfort and come back to ReturnURL.
+	Comfort   *weather.ComfortProfile
+	ReturnURL string
+
+	// Commute windows come from ?commute= (which also saves them in a
+	// cookie) or the saved cookie.
+	CommuteSpec  string
+	CommuteError string
+	Commute      []weather.CommuteOutfit
+
+	// UV is the exposure report for the skin type and SPF from ?skin=&spf=
+	// (also saved in a cookie) or the saved cookie.
+	SkinTypes []weather.SkinType
+	UVOpts    weather.UVOptions
+	UV        *weather.UVExposure
+
+	// Garden is the gardening report for ?since=&base= (saved in a cookie);
+	// base is in the page's units. A failed fetch only hides the card.
+	GardenSince string
+	GardenBase  string
+	Garden      *weather.GardenReport
+	GardenError string
+
+	// Solar is the PV forecast for the system in ?kwp=&tilt=&azimuth=&losses=
+	// (saved in a cookie).
+	SolarSys   weather.SolarSystem
+	Solar      *weather.SolarForecast
+	SolarError string
 }
 
+// PackPageData is the /pack trip planner page.
+type PackPageData struct {
+	Legs       string // itinerary as typed, one "City:FROM[:TO]" per line
+	Units      string
+	Activity   string
+	Activities []weather.Activity
+	Plan       *weather.PackingList
+	Error      string
+}
+
+// ComparePageData is the /compare page.
+type ComparePageData struct {
+	Cities  []string // as typed, one per ?city=
+	Units   string
+	Compare *weather.Comparison
+	Error   string
+}
+
+const (
+	commuteCookie = "commute"
+	langCookie    = "lang"
+	gardenCookie  = "garden" // "since:base"
+	solarCookie   = "solar"  // "kwp:tilt:azimuth:losses"
+	comfortCookie = "comfort"
+	uvCookie      = "uv" // "<skin type>:<spf>", e.g. "2:30"
+)
+
+// uvOptionsFrom parses a uvCookie value; bad parts fall back to defaults.
+func uvOptionsFrom(skin, spf string) weather.UVOptions {
+	opts := weather.UVOptions{Skin: weather.DefaultSkinType}
+	if st, err := weather.ParseSkinType(skin); err == nil {
+		opts.Skin = st
+	}
+	if v, err := strconv.ParseFloat(spf, 64); err == nil && v >= 0 && v <= 100 {
+		opts.SPF = v
+	}
+	return opts
+}
+
+// gardenOptionsFrom parses the garden form or gardenCookie values. base is
+// in the page's units; empty values keep the seasonal defaults.
+func gardenOptionsFrom(since, base, units string) (weather.GardenOptions, error) {
+	var opts weather.GardenOptions
+	if since != "" {
+		d, err := time.Parse("2006-01-02", since)
+		if err != nil {
+			return opts, fmt.Errorf("bad start date %q", since)
+		}
+		opts.Since = d
+	}
+	if base != "" {
+		v, err := strconv.ParseFloat(base, 64)
+		if err != nil || v < -50 || v > 120 {
+			return opts, fmt.Errorf("bad base temperature %q", base)
+		}
+		if units == "imperial" {
+			v = (v - 32) * 5 / 9
+		}
+		opts.BaseC = &v
+	}
+	return opts, nil
+}
+
+// cachedGarden returns the garden report for city from gardenCache, or
+// fetches and stores it. Reports are kept per start date and base too, as
+// they change the history fetched.
+func cachedGarden(client *weather.Client, city, units, since, base string) (*weather.GardenReport, error) {
+	key := strings.ToLower(city) + "|" + units + "|" + since + "|" + base
+	if g, ok := gardenCache.get(key); ok {
+		return g, nil
+	}
+	opts, err := gardenOptionsFrom(since, base, units)
+	if err != nil {
+		return nil, err
+	}
+	g, err := client.Garden(city, units, opts)
+	if err != nil {
+		return nil, err
+	}
+	gardenCache.set(key, g)
+	return g, nil
+}
+
+// solarSpecFrom joins the solar form fields the way ParseSolarSystem reads
+// them, or returns "" when none is set.
+func solarSpecFrom(q url.Values) string {
+	if !q.Has("kwp") && !q.Has("tilt") && !q.Has("azimuth") && !q.Has("losses") {
+		return ""
+	}
+	return strings.Join([]string{q.Get("kwp"), q.Get("tilt"), q.Get("azimuth"), q.Get("losses")}, ":")
+}
+
+// cachedSolar returns the PV forecast for city and sys from solarCache, or
+// fetches and stores it.
+func cachedSolar(client *weather.Client, city string, sys weather.SolarSystem, days int) (*weather.SolarForecast, error) {
+	key := fmt.Sprintf("%s|%s|%d", strings.ToLower(city), sys, days)
+	if f, ok := solarCache.get(key); ok {
+		return f, nil
+	}
+	f, err := client.Solar(city, sys, days)
+	if err != nil {
+		return nil, err
+	}
+	solarCache.set(key, f)
+	return f, nil
+}
+
+// langFrom picks the request's locale from ?lang=, which it saves in
+// langCookie, then the cookie, then Accept-Language.
+func langFrom(w http.ResponseWriter, r *http.Request) string {
+	if q := r.URL.Query().Get("lang"); q != "" {
+		lang := i18n.Match(q)
+		http.SetCookie(w, &http.Cookie{Name: langCookie, Value: lang, Path: "/",
+			MaxAge: 365 * 24 * 3600, SameSite: http.SameSiteLaxMode})
+		return lang
+	}
+	var saved string
+	if c, err := r.Cookie(langCookie); err == nil {
+		saved = c.Value
+	}
+	return i18n.Match(saved, r.Header.Get("Accept-Language"))
+}
+
+// comfortFromRequest reads the browser's comfort profile, or nil.
+func comfortFromRequest(r *http.Request) *weather.ComfortProfile {
+	c, err := r.Cookie(comfortCookie)
+	if err != nil {
+		return nil
+	}
+	p, err := weather.DecodeComfortProfile(c.Value)
+	if err != nil {
+		return nil
+	}
+	return p
+}
+
+// sameOrigin reports whether a POST came from one of this server's own pages.
+// Browsers label cross-site requests in Sec-Fetch-Site; older ones still send
+// Origin or Referer, which a page on another site can't forge. A request with
+// none of them (curl, say) is refused too.
+func sameOrigin(r *http.Request) bool {
+	if site := r.Header.Get("Sec-Fetch-Site"); site != "" {
+		return site == "same-origin" || site == "none"
+	}
+	src := r.Header.Get("Origin")
+	if src == "" || src == "null" {
+		src = r.Header.Get("Referer")
+	}
+	u, err := url.Parse(src)
+	return src != "" && err == nil && u.Host == r.Host
+}
 
 func main() {
 	client := weather.NewClient()
+	// WEATHER_MODELS overrides the consensus model set, e.g. "ecmwf,icon,gfs,jma".
+	if spec := os.Getenv("WEATHER_MODELS"); spec != "" {
+		models, err := weather.ParseModels(spec)
+		if err != nil {
+			log.Fatalf("WEATHER_MODELS: %v", err)
+		}
+		client.Models = models
+	}
+	// WEATHER_CONSENSUS picks the consensus aggregation: mean, median or trimmed.
+	agg, err := weather.ParseAggregation(os.Getenv("WEATHER_CONSENSUS"))
+	if err != nil {
+		log.Fatalf("WEATHER_CONSENSUS: %v", err)
+	}
+	client.Aggregation = agg
+	// WEATHER_ENSEMBLE_MODELS overrides the ensemble set, e.g. "ecmwf,gefs,icon".
+	if spec := os.Getenv("WEATHER_ENSEMBLE_MODELS"); spec != "" {
+		models, err := weather.ParseEnsembleModels(spec)
+		if err != nil {
+			log.Fatalf("WEATHER_ENSEMBLE_MODELS: %v", err)
+		}
+		client.EnsembleModels = models
+	}
+	// WEATHER_VERIFY_FILE moves the forecast verification store; "off" disables it.
+	if path := os.Getenv("WEATHER_VERIFY_FILE"); path != "off" {
+		if path == "" {
+			path = weather.DefaultVerifyPath()
+		}
+		store, err := weather.OpenVerifyStore(path)
+		if err != nil {
+			log.Fatalf("WEATHER_VERIFY_FILE: %v", err)
+		}
+		client.Verify = store
+	}
+	// WEATHER_SKILL_WEIGHTS=1 scales consensus weights by verified model skill.
+	client.SkillWeighting = os.Getenv("WEATHER_SKILL_WEIGHTS") == "1" && client.Verify != nil
+	// WEATHER_QUOTES picks the quote packs: built-in names, pack files or
+	// directories, comma-separated; "off" hides the quote.
+	quotes, err := weather.LoadQuotes(os.Getenv("WEATHER_QUOTES"))
+	if err != nil {
+		log.Fatalf("WEATHER_QUOTES: %v", err)
+	}
 	startCacheCleanup()
 
 	http.Handle("/static/", http.StripPrefix("/static/", http.FileServer(http.Dir("static"))))
@@ -352,6 +1047,272 @@ func main() {
 		fmt.Fprintf(w, `{"city":%q}`, city)
 	})
 
+	// /api/v1/verification?location=... — rolling model skill (MAE, bias) per location and lead
+	http.HandleFunc("/api/v1/verification", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if client.Verify == nil {
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(map[string]string{"error": "verification is disabled"})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]any{
+			"scores": client.Verify.Report(strings.TrimSpace(r.FormValue("location"))),
+		})
+	})
+
+	// /api/v1/solar?city=...&kwp=&tilt=&azimuth=&losses=&days= — hourly and daily
+	// PV output forecast; omitted system fields take the defaults.
+	http.HandleFunc("/api/v1/solar", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fail := func(status int, err error) {
+			w.WriteHeader(status)
+			json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		}
+		city := strings.TrimSpace(r.FormValue("city"))
+		if city == "" || len(city) > 100 {
+			fail(http.StatusBadRequest, fmt.Errorf("missing or too long city"))
+			return
+		}
+		sys, err := weather.ParseSolarSystem(solarSpecFrom(r.URL.Query()))
+		if err != nil {
+			fail(http.StatusBadRequest, err)
+			return
+		}
+		days := 0
+		if v := r.FormValue("days"); v != "" {
+			if days, err = strconv.Atoi(v); err != nil || days < 1 || days >= weather.MaxSolarDays {
+				fail(http.StatusBadRequest, fmt.Errorf("days must be 1-%d", weather.MaxSolarDays-1))
+				return
+			}
+		}
+		f, err := cachedSolar(client, city, sys, days)
+		if err != nil {
+			status := http.StatusBadGateway
+			if strings.Contains(err.Error(), "not found") {
+				status = http.StatusNotFound
+			}
+			fail(status, err)
+			return
+		}
+		json.NewEncoder(w).Encode(f)
+	})
+
+	// /api/v1/degreedays?city=...&units=&heat=&cool=&past=&days=&format=csv —
+	// daily heating and cooling degree days against the same dates last year.
+	http.HandleFunc("/api/v1/degreedays", func(w http.ResponseWriter, r *http.Request) {
+		fail := func(status int, err error) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(status)
+			json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		}
+		city := strings.TrimSpace(r.FormValue("city"))
+		if city == "" || len(city) > 100 {
+			fail(http.StatusBadRequest, fmt.Errorf("missing or too long city"))
+			return
+		}
+		var opts weather.DegreeDayOptions
+		for _, f := range []struct {
+			name string
+			dst  **float64
+		}{{"heat", &opts.HeatBase}, {"cool", &opts.CoolBase}} {
+			if v := r.FormValue(f.name); v != "" {
+				n, err := strconv.ParseFloat(v, 64)
+				if err != nil {
+					fail(http.StatusBadRequest, fmt.Errorf("bad %s base %q", f.name, v))
+					return
+				}
+				*f.dst = &n
+			}
+		}
+		for _, f := range []struct {
+			name string
+			dst  *int
+		}{{"past", &opts.Past}, {"days", &opts.Days}} {
+			if v := r.FormValue(f.name); v != "" {
+				n, err := strconv.Atoi(v)
+				if err != nil {
+					fail(http.StatusBadRequest, fmt.Errorf("bad %s %q", f.name, v))
+					return
+				}
+				*f.dst = n
+			}
+		}
+		report, err := client.DegreeDays(city, r.FormValue("units"), opts)
+		if err != nil {
+			status := http.StatusBadGateway
+			switch msg := err.Error(); {
+			case strings.Contains(msg, "not found"):
+				status = http.StatusNotFound
+			case strings.Contains(msg, "must be"):
+				status = http.StatusBadRequest
+			}
+			fail(status, err)
+			return
+		}
+		if r.FormValue("format") == "csv" {
+			w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+			name := strings.Map(func(r rune) rune {
+				if r < 128 && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+					return unicode.ToLower(r)
+				}
+				return '-'
+			}, report.City)
+			w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="degree-days-%s-%s.csv"`, name, report.Today))
+			report.WriteCSV(w)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(report)
+	})
+
+	// POST /comfort — record "too cold / just right / too hot" feedback,
+	// prefer/avoid (with prefs=1) or reset=1 in this browser's comfort cookie,
+	// then go back to the page. Only same-origin posts are accepted.
+	http.HandleFunc("/comfort", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		if !sameOrigin(r) {
+			http.Error(w, "Forbidden", http.StatusForbidden)
+			return
+		}
+		profile := comfortFromRequest(r)
+		if profile == nil || r.FormValue("reset") == "1" {
+			profile = &weather.ComfortProfile{}
+		}
+		// The preferences form sends both fields in full, so they replace
+		// the saved ones rather than merging like the CLI's -prefer.
+		if r.FormValue("prefs") == "1" {
+			prefer, avoid := strings.TrimSpace(r.FormValue("prefer")), r.FormValue("avoid")
+			if len(prefer) > 500 || len(avoid) > 200 {
+				http.Error(w, "preferences too long", http.StatusBadRequest)
+				return
+			}
+			profile.Prefer = nil
+			if prefer != "" {
+				if err := profile.SetPrefer(prefer); err != nil {
+					http.Error(w, err.Error(), http.StatusBadRequest)
+					return
+				}
+			}
+			profile.SetAvoid(avoid)
+		}
+		if v := r.FormValue("feel"); v != "" {
+			feel, err := weather.ParseFeel(v)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			profile.AddFeedback(feel, time.Now())
+		}
+		http.SetCookie(w, &http.Cookie{Name: comfortCookie, Value: profile.Encode(), Path: "/",
+			MaxAge: 365 * 24 * 3600, SameSite: http.SameSiteLaxMode})
+
+		// Only same-site paths, never an absolute URL.
+		back := r.FormValue("return")
+		if !strings.HasPrefix(back, "/") || strings.HasPrefix(back, "//") || strings.HasPrefix(back, "/\\") {
+			back = "/"
+		}
+		http.Redirect(w, r, back, http.StatusSeeOther)
+	})
+
+	// /pack?legs=Berlin:2026-05-01:2026-05-03;Lisbon:2026-05-04 — trip packing list
+	http.HandleFunc("/pack", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		lang := langFrom(w, r)
+		data := PackPageData{Legs: strings.TrimSpace(r.FormValue("legs")), Units: r.FormValue("units")}
+		if !r.URL.Query().Has("units") {
+			data.Units = i18n.Units(r.Header.Get("Accept-Language"))
+		}
+		if data.Units != "imperial" {
+			data.Units = "metric"
+		}
+		for _, k := range weather.ActivityKeys() {
+			data.Activities = append(data.Activities, weather.Activities[k])
+		}
+		activity, err := weather.ParseActivity(r.FormValue("activity"))
+		if err != nil {
+			activity = weather.DefaultActivity
+		}
+		data.Activity = activity.Key
+
+		if data.Legs != "" {
+			legs, err := weather.ParseItinerary(data.Legs)
+			switch {
+			case err != nil:
+				w.WriteHeader(http.StatusBadRequest)
+				data.Error = err.Error()
+			case len(legs) > 10:
+				w.WriteHeader(http.StatusBadRequest)
+				data.Error = i18n.T(lang, "Too many stops (max 10).")
+			default:
+				opts := weather.OutfitOptions{Activity: activity, Comfort: comfortFromRequest(r)}
+				localClient := *client
+				localClient.Lang = lang
+				data.Plan = localClient.PlanTrip(legs, data.Units, opts)
+			}
+		}
+
+		if err := page(lang).ExecuteTemplate(w, "pack.html", data); err != nil {
+			http.Error(w, "Template error: "+err.Error(), http.StatusInternalServerError)
+		}
+	})
+
+	// /compare?city=a&city=b — several cities side by side, fetched
+	// concurrently through the same cache as the main page.
+	http.HandleFunc("/compare", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		lang := langFrom(w, r)
+		data := ComparePageData{Units: r.FormValue("units")}
+		if !r.URL.Query().Has("units") {
+			data.Units = i18n.Units(r.Header.Get("Accept-Language"))
+		}
+		if data.Units != "imperial" {
+			data.Units = "metric"
+		}
+		for _, c := range r.URL.Query()["city"] {
+			if c = strings.TrimSpace(c); c != "" {
+				data.Cities = append(data.Cities, c)
+			}
+		}
+
+		tooLong := slices.ContainsFunc(data.Cities, func(c string) bool { return len(c) > 100 })
+		switch {
+		case len(data.Cities) > weather.MaxCompareCities:
+			w.WriteHeader(http.StatusBadRequest)
+			data.Error = fmt.Sprintf("Too many cities (max %d).", weather.MaxCompareCities)
+		case tooLong:
+			w.WriteHeader(http.StatusBadRequest)
+			data.Error = "City name is too long (max 100 characters)."
+		case len(data.Cities) > 0:
+			data.Compare = weather.Compare(data.Cities, func(city string) (*weather.WeatherInfo, error) {
+				key := cacheKey(city, data.Units, lang)
+				if info := cacheGet(key); info != nil {
+					return info, nil
+				}
+				localClient := *client
+				localClient.Lang = lang
+				info, err := localClient.GetWeather(city, data.Units)
+				if err != nil {
+					return nil, err
+				}
+				cacheSet(key, info)
+				return info, nil
+			})
+		}
+
+		if err := page(lang).ExecuteTemplate(w, "compare.html", data); err != nil {
+			http.Error(w, "Template error: "+err.Error(), http.StatusInternalServerError)
+		}
+	})
+
 	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
 		// Only allow GET and HEAD
 		if r.Method != http.MethodGet && r.Method != http.MethodHead {
@@ -360,29 +1321,125 @@ func main() {
 		}
 
 		city := strings.TrimSpace(r.FormValue("city"))
+		lang := langFrom(w, r)
 		units := r.FormValue("units")
+		if !r.URL.Query().Has("units") {
+			units = i18n.Units(r.Header.Get("Accept-Language"))
+		}
 		if units != "imperial" {
 			units = "metric"
 		}
+		pg := page(lang)
 
-		data := PageData{City: city, Units: units}
+		data := PageData{City: city, Units: units, Lang: lang, Locales: i18n.Locales()}
+		for _, k := range weather.ActivityKeys() {
+			data.Activities = append(data.Activities, weather.Activities[k])
+		}
+		activity, err := weather.ParseActivity(r.FormValue("activity"))
+		if err != nil {
+			activity = weather.DefaultActivity
+		}
+		data.Activity = activity.Key
+		data.Comfort = comfortFromRequest(r)
+		data.ReturnURL = r.URL.RequestURI()
+		outfitOpts := weather.OutfitOptions{Activity: activity, Comfort: data.Comfort}
+
+		data.SkinTypes = weather.SkinTypes
+		if q := r.URL.Query(); q.Has("skin") || q.Has("spf") {
+			data.UVOpts = uvOptionsFrom(q.Get("skin"), q.Get("spf"))
+			http.SetCookie(w, &http.Cookie{Name: uvCookie, Path: "/", MaxAge: 365 * 24 * 3600, SameSite: http.SameSiteLaxMode,
+				Value: fmt.Sprintf("%d:%g", data.UVOpts.Skin.Type, data.UVOpts.SPF)})
+		} else if c, err := r.Cookie(uvCookie); err == nil {
+			skin, spf, _ := strings.Cut(c.Value, ":")
+			data.UVOpts = uvOptionsFrom(skin, spf)
+		} else {
+			data.UVOpts = uvOptionsFrom("", "")
+		}
+
+		var windows []weather.TimeWindow
+		if r.URL.Query().Has("commute") {
+			data.CommuteSpec = strings.TrimSpace(r.FormValue("commute"))
+			ws, err := weather.ParseWindows(data.CommuteSpec)
+			if err != nil {
+				data.CommuteError = err.Error()
+			} else {
+				windows = ws
+				cookie := &http.Cookie{Name: commuteCookie, Value: weather.FormatWindows(ws), Path: "/",
+					MaxAge: 365 * 24 * 3600, SameSite: http.SameSiteLaxMode}
+				if len(ws) == 0 {
+					cookie.MaxAge = -1
+				}
+				http.SetCookie(w, cookie)
+			}
+		} else if c, err := r.Cookie(commuteCookie); err == nil {
+			if ws, err := weather.ParseWindows(c.Value); err == nil {
+				windows = ws
+				data.CommuteSpec = weather.FormatWindows(ws)
+			}
+		}
+
+		if q := r.URL.Query(); q.Has("since") || q.Has("base") {
+			data.GardenSince, data.GardenBase = strings.TrimSpace(q.Get("since")), strings.TrimSpace(q.Get("base"))
+			http.SetCookie(w, &http.Cookie{Name: gardenCookie, Path: "/", MaxAge: 365 * 24 * 3600, SameSite: http.SameSiteLaxMode,
+				Value: data.GardenSince + ":" + data.GardenBase})
+		} else if c, err := r.Cookie(gardenCookie); err == nil {
+			data.GardenSince, data.GardenBase, _ = strings.Cut(c.Value, ":")
+		}
+
+		data.SolarSys = weather.DefaultSolarSystem
+		if spec := solarSpecFrom(r.URL.Query()); spec != "" {
+			sys, err := weather.ParseSolarSystem(spec)
+			if err != nil {
+				data.SolarError = err.Error()
+			} else {
+				data.SolarSys = sys
+				http.SetCookie(w, &http.Cookie{Name: solarCookie, Value: sys.String(), Path: "/",
+					MaxAge: 365 * 24 * 3600, SameSite: http.SameSiteLaxMode})
+			}
+		} else if c, err := r.Cookie(solarCookie); err == nil {
+			if sys, err := weather.ParseSolarSystem(c.Value); err == nil {
+				data.SolarSys = sys
+			}
+		}
 
 		if city != "" {
 			// Input validation
 			if len(city) > 100 {
 				w.WriteHeader(http.StatusBadRequest)
 				data.Error = "City name is too long (max 100 characters)."
-				_ = tmpl.ExecuteTemplate(w, "index.html", data)
+				_ = pg.ExecuteTemplate(w, "index.html", data)
 				return
 			}
 
+			// The garden report and PV forecast have their own requests;
+			// fetch them alongside.
+			var garden *weather.GardenReport
+			var gardenErr error
+			gardenDone := make(chan struct{})
+			go func(since, base string) {
+				defer close(gardenDone)
+				garden, gardenErr = cachedGarden(client, city, units, since, base)
+			}(data.GardenSince, data.GardenBase)
+			var solar *weather.SolarForecast
+			var solarErr error
+			solarDone := make(chan struct{})
+			go func(sys weather.SolarSystem, skip bool) {
+				defer close(solarDone)
+				if !skip {
+					solar, solarErr = cachedSolar(client, city, sys, 0)
+				}
+			}(data.SolarSys, data.SolarError != "")
+
 			// Check cache first
-			key := cacheKey(city, units)
+			key := cacheKey(city, units, lang)
 			info := cacheGet(key)
 
 			if info == nil {
+				// A shallow copy shares the HTTP client and verify store.
+				localClient := *client
+				localClient.Lang = lang
 				var err error
-				info, err = client.GetWeather(city, units)
+				info, err = localClient.GetWeather(city, units)
 				if err != nil {
 					// Distinguish not-found from network/server errors
 					status := http.StatusBadGateway
@@ -399,7 +1456,7 @@ func main() {
 					}
 					w.WriteHeader(status)
 					data.Error = errMsg
-					_ = tmpl.ExecuteTemplate(w, "index.html", data)
+					_ = pg.ExecuteTemplate(w, "index.html", data)
 					return
 				}
 				cacheSet(key, info)
@@ -407,11 +1464,25 @@ func main() {
 
 			data.Info = info
 			data.Alerts = weather.Alerts(info)
-			data.Quote = weather.QuoteFromIcon(info.Current.Icon)
-			data.Advice = weather.Advice(info.Current.FeelsLike, info.TempUnit)
+			data.Narrative = weather.Narrative(info)
+			data.Quote = i18n.T(lang, quotes.For(info))
+			data.Advice = i18n.T(lang, data.Comfort.Advice(info.Current.FeelsLike, info.TempUnit))
+			data.Outfit = outfitOpts.Build(info)
+			data.Commute = weather.CommuteOutfits(info, windows, outfitOpts)
+			data.UV = data.UVOpts.Report(info)
+			<-gardenDone
+			data.Garden = garden
+			if gardenErr != nil {
+				data.GardenError = gardenErr.Error()
+			}
+			<-solarDone
+			data.Solar = solar
+			if solarErr != nil {
+				data.SolarError = solarErr.Error()
+			}
 		}
 
-		if err := tmpl.ExecuteTemplate(w, "index.html", data); err != nil {
+		if err := pg.ExecuteTemplate(w, "index.html", data); err != nil {
 			http.Error(w, "Template error: "+err.Error(), http.StatusInternalServerError)
 		}
 	})
@@ -0,0 +1,204 @@
+package weather
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"WeatherApp/comfort"
+	"WeatherApp/i18n"
+)
+
+// Activity tunes the outfit rules and suitability score for what the user is
+// doing outdoors. Thresholds are in metric units.
+type Activity struct {
+	Key  string // "walk", "cycle", "run", "work"
+	Name string
+
+	SpeedKmh float64 // own speed, added to the wind for wind chill
+	WarmthC  float64 // metabolic heat: dress as if it were this much warmer
+
+	IdealMinC, IdealMaxC float64 // comfortable feels-like range for the score
+	WindLimitKmh         float64 // wind above this costs suitability
+	RainWeight           float64 // suitability points lost per % chance of rain
+}
+
+// Activities are the built-in profiles, keyed by Activity.Key.
+var Activities = map[string]Activity{
+	"walk": {
+		Key: "walk", Name: "Walking",
+		IdealMinC: 12, IdealMaxC: 24, WindLimitKmh: 30, RainWeight: 0.5,
+	},
+	"cycle": {
+		Key: "cycle", Name: "Cycling",
+		SpeedKmh: 20, WarmthC: 4,
+		IdealMinC: 12, IdealMaxC: 24, WindLimitKmh: 20, RainWeight: 0.6,
+	},
+	"run": {
+		Key: "run", Name: "Running",
+		SpeedKmh: 10, WarmthC: 8,
+		IdealMinC: 5, IdealMaxC: 18, WindLimitKmh: 30, RainWeight: 0.35,
+	},
+	"work": {
+		Key: "work", Name: "Outdoor work",
+		WarmthC:   2,
+		IdealMinC: 8, IdealMaxC: 24, WindLimitKmh: 40, RainWeight: 0.5,
+	},
+}
+
+// DefaultActivity is the generic walk that BuildOutfit has always assumed.
+var DefaultActivity = Activities["walk"]
+
+// ParseActivity looks up an activity by key; "" is DefaultActivity.
+func ParseActivity(key string) (Activity, error) {
+	key = strings.ToLower(strings.TrimSpace(key))
+	if key == "" {
+		return DefaultActivity, nil
+	}
+	if a, ok := Activities[key]; ok {
+		return a, nil
+	}
+	return Activity{}, fmt.Errorf("unknown activity %q (want one of %s)", key, strings.Join(ActivityKeys(), ", "))
+}
+
+// ActivityKeys returns the activity keys in a stable order.
+func ActivityKeys() []string {
+	keys := make([]string, 0, len(Activities))
+	for k := range Activities {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Suitability rates how good conditions are for an activity.
+type Suitability struct {
+	Score   int      // 0-100
+	Label   string   // "Great", "Good", "Fair", "Poor"
+	Reasons []string // biggest penalties first
+}
+
+// effectiveC is the temperature the activity's outfit is dressed for: the
+// feels-like, lowered to the wind chill at the activity's speed, plus the
+// warmth the activity itself produces.
+func (a Activity) effectiveC(airC, feelsC, windKmh float64) float64 {
+	eff := feelsC
+	if a.SpeedKmh > 0 && airC <= 10 {
+		eff = math.Min(eff, comfort.WindChill(airC, windKmh+a.SpeedKmh))
+	}
+	return eff + a.WarmthC
+}
+
+// suitability scores conditions for the activity, starting from 100 and
+// subtracting penalties for temperature, rain, wind, UV and heat.
+func (a Activity) suitability(c outfitConditions) Suitability {
+	type penalty struct {
+		points float64
+		reason string
+	}
+	var ps []penalty
+
+	switch {
+	case c.feelsC < a.IdealMinC:
+		d := a.IdealMinC - c.feelsC
+		ps = append(ps, penalty{4 * d, i18n.Sprintf(c.lang, "%s colder than ideal", tempDeltaLabel(d, c.tempUnit))})
+	case c.feelsC > a.IdealMaxC:
+		d := c.feelsC - a.IdealMaxC
+		factor := 4.0
+		if a.Key == "run" || a.Key == "work" {
+			factor = 5 // heat load builds faster with exertion
+		}
+		ps = append(ps, penalty{factor * d, i18n.Sprintf(c.lang, "%s warmer than ideal", tempDeltaLabel(d, c.tempUnit))})
+	}
+	if c.precipProb > 0 {
+		ps = append(ps, penalty{a.RainWeight * float64(c.precipProb), i18n.Sprintf(c.lang, "%d%% chance of rain", c.precipProb)})
+	}
+	if c.windKmh > a.WindLimitKmh {
+		factor := 1.5
+		if a.Key == "cycle" {
+			factor = 2
+		}
+		ps = append(ps, penalty{factor * (c.windKmh - a.WindLimitKmh), i18n.Sprintf(c.lang, "wind %s", windLabel(c.windKmh, c.windUnit))})
+	}
+	if c.uv >= 6 {
+		ps = append(ps, penalty{3 * (c.uv - 5), i18n.Sprintf(c.lang, "UV %.0f", c.uv)})
+	}
+
+	sort.SliceStable(ps, func(i, j int) bool { return ps[i].points > ps[j].points })
+	score := 100.0
+	var reasons []string
+	for _, p := range ps {
+		score -= p.points
+		if p.points >= 5 {
+			reasons = append(reasons, p.reason)
+		}
+	}
+	n := int(math.Round(math.Max(0, math.Min(100, score))))
+	return Suitability{Score: n, Label: suitabilityLabel(n), Reasons: reasons}
+}
+
+func suitabilityLabel(score int) string {
+	switch {
+	case score >= 80:
+		return "Great"
+	case score >= 60:
+		return "Good"
+	case score >= 40:
+		return "Fair"
+	default:
+		return "Poor"
+	}
+}
+
+// activityItems returns the gear an activity needs before the generic items.
+func (a Activity) activityItems(c outfitConditions, effC float64) []OutfitItem {
+	var items []OutfitItem
+	tr := func(s string) string { return i18n.T(c.lang, s) }
+	switch a.Key {
+	case "work":
+		items = append(items, OutfitItem{
+			Icon: "hivis", Label: "Hi-Vis Vest", Color: "oi-amber",
+			Note: tr("Stay visible to plant and traffic"),
+		})
+		if effC < 8 {
+			items = append(items, OutfitItem{
+				Icon: "gloves", Label: "Insulated Gloves", Color: "oi-indigo",
+				Note: tr("Cold hands lose grip and dexterity"),
+			})
+		}
+		if effC >= 27 {
+			items = append(items, OutfitItem{
+				Icon: "bottle", Label: "Water + Shade Breaks", Color: "oi-orange",
+				Note: tr("Drink every 15–20 minutes in the heat"),
+			})
+		}
+	case "cycle":
+		if effC < 10 {
+			items = append(items, OutfitItem{
+				Icon: "gloves", Label: "Full-Finger Gloves", Color: "oi-indigo",
+				Note: i18n.Sprintf(c.lang, "Wind chill at %.0f km/h bites fingers first", a.SpeedKmh),
+			})
+		}
+		if !c.isDay {
+			items = append(items, OutfitItem{
+				Icon: "lights", Label: "Lights + Reflective", Color: "oi-amber",
+				Note: tr("Front and rear lights after dark"),
+			})
+		}
+	case "run":
+		if effC >= 25 {
+			items = append(items, OutfitItem{
+				Icon: "bottle", Label: "Hydration", Color: "oi-orange",
+				Note: tr("Carry water or plan a route past a fountain"),
+			})
+		}
+		if !c.isDay {
+			items = append(items, OutfitItem{
+				Icon: "lights", Label: "Reflective Gear", Color: "oi-amber",
+				Note: tr("Be seen by drivers in the dark"),
+			})
+		}
+	}
+	return items
+}
@@ -1,5 +1,10 @@
 package weather
 
+import (
+	"WeatherApp/comfort"
+	"WeatherApp/i18n"
+)
+
 // AlertLevel classifies the severity of a weather alert.
 type AlertLevel string
 
@@ -33,21 +38,28 @@ func toKmh(speed float64, unitLabel string) float64 {
 	return speed
 }
 
-// Alerts analyses a WeatherInfo and returns triggered alerts ordered by severity.
+// Alerts analyses a WeatherInfo and returns triggered alerts ordered by
+// severity, in info.Lang.
 func Alerts(info *WeatherInfo) []Alert {
 	var alerts []Alert
+	lang := info.Lang
+	tr := func(s string) string { return i18n.T(lang, s) }
 
 	cur := info.Current
 	tempC := toCelsius(cur.Temp, info.TempUnit)
 	feelsC := toCelsius(cur.FeelsLike, info.TempUnit)
 	windKmh := toKmh(cur.WindSpeed, info.WindUnit)
+	// Heat alerts key off WBGT; the feels-like alerts are the fallback for
+	// when it was not computed, so the two never double up.
+	wbgt := cur.Stress.WBGT
+	wbgtMsg := i18n.Sprintf(lang, "WBGT %.1f%s (%s). %s", wbgt.In(info.TempUnit), info.TempUnit, tr(wbgt.Label), tr(wbgt.Guidance))
 
-	if isThunder(cur.Icon) {
+	if cur.Condition.Thunder {
 		alerts = append(alerts, Alert{
 			Level:   AlertDanger,
 			Icon:    "wi-thunderstorm",
-			Title:   "THUNDERSTORM ACTIVE",
-			Message: "Lightning risk. Stay indoors. Unplug electronics. Avoid open areas.",
+			Title:   tr("THUNDERSTORM ACTIVE"),
+			Message: tr("Lightning risk. Stay indoors. Unplug electronics. Avoid open areas."),
 		})
 	}
 
@@ -55,17 +67,24 @@ func Alerts(info *WeatherInfo) []Alert {
 		alerts = append(alerts, Alert{
 			Level:   AlertDanger,
 			Icon:    "wi-snowflake-cold",
-			Title:   "EXTREME COLD",
-			Message: "Dangerously cold. Risk of frostbite in under 30 minutes. Limit time outdoors.",
+			Title:   tr("EXTREME COLD"),
+			Message: tr("Dangerously cold. Risk of frostbite in under 30 minutes. Limit time outdoors."),
 		})
 	}
 
-	if feelsC >= 40 {
+	if wbgt.Level >= comfort.Danger {
 		alerts = append(alerts, Alert{
 			Level:   AlertDanger,
 			Icon:    "wi-hot",
-			Title:   "EXTREME HEAT",
-			Message: "Heat index critical. Risk of heat stroke. Stay in the shade and hydrate constantly.",
+			Title:   tr("EXTREME HEAT STRESS"),
+			Message: wbgtMsg,
+		})
+	} else if feelsC >= 40 && !wbgt.Applies {
+		alerts = append(alerts, Alert{
+			Level:   AlertDanger,
+			Icon:    "wi-hot",
+			Title:   tr("EXTREME HEAT"),
+			Message: tr("Heat index critical. Risk of heat stroke. Stay in the shade and hydrate constantly."),
 		})
 	}
 
@@ -73,26 +92,26 @@ func Alerts(info *WeatherInfo) []Alert {
 		alerts = append(alerts, Alert{
 			Level:   AlertDanger,
 			Icon:    "wi-strong-wind",
-			Title:   "HURRICANE-FORCE WIND",
-			Message: "Extremely dangerous winds. Take shelter immediately. Do not drive.",
+			Title:   tr("HURRICANE-FORCE WIND"),
+			Message: tr("Extremely dangerous winds. Take shelter immediately. Do not drive."),
 		})
 	}
 
-	if isHeavyRain(cur.Icon) {
+	if cond := cur.Condition; cond.Precip == PrecipRain && cond.Intensity == IntensityHeavy && !cond.Thunder {
 		alerts = append(alerts, Alert{
 			Level:   AlertWarning,
 			Icon:    "wi-rain-wind",
-			Title:   "HEAVY RAIN",
-			Message: "Reduced visibility and possible flash flooding. Drive carefully.",
+			Title:   tr("HEAVY RAIN"),
+			Message: tr("Reduced visibility and possible flash flooding. Drive carefully."),
 		})
 	}
 
-	if isHeavySnow(cur.Icon) {
+	if cond := cur.Condition; cond.Precip == PrecipSnow && cond.Intensity == IntensityHeavy {
 		alerts = append(alerts, Alert{
 			Level:   AlertWarning,
 			Icon:    "wi-snow-wind",
-			Title:   "HEAVY SNOW",
-			Message: "Roads may be impassable. Allow extra travel time and check road conditions.",
+			Title:   tr("HEAVY SNOW"),
+			Message: tr("Roads may be impassable. Allow extra travel time and check road conditions."),
 		})
 	}
 
@@ -101,18 +120,25 @@ func Alerts(info *WeatherInfo) []Alert {
 		alerts = append(alerts, Alert{
 			Level:   AlertWarning,
 			Icon:    "wi-thermometer-exterior",
-			Title:   "FREEZING CONDITIONS",
-			Message: "Black ice possible on roads. Wrap up warm and watch your step.",
+			Title:   tr("FREEZING CONDITIONS"),
+			Message: tr("Black ice possible on roads. Wrap up warm and watch your step."),
 		})
 	}
 
 	// Heatwave: 35 °C ≤ feelsC < 40 °C (extreme heat covers ≥ 40)
-	if feelsC >= 35 && feelsC < 40 {
+	if wbgt.Level == comfort.ExtremeCaution {
+		alerts = append(alerts, Alert{
+			Level:   AlertWarning,
+			Icon:    "wi-day-sunny",
+			Title:   tr("HEAT STRESS"),
+			Message: wbgtMsg,
+		})
+	} else if feelsC >= 35 && feelsC < 40 && !wbgt.Applies {
 		alerts = append(alerts, Alert{
 			Level:   AlertWarning,
 			Icon:    "wi-day-sunny",
-			Title:   "HEATWAVE WARNING",
-			Message: "Dangerously warm. Drink water, avoid peak sun hours (11am–3pm), check on vulnerable people.",
+			Title:   tr("HEATWAVE WARNING"),
+			Message: tr("Dangerously warm. Drink water, avoid peak sun hours (11am–3pm), check on vulnerable people."),
 		})
 	}
 
@@ -120,17 +146,36 @@ func Alerts(info *WeatherInfo) []Alert {
 		alerts = append(alerts, Alert{
 			Level:   AlertWarning,
 			Icon:    "wi-strong-wind",
-			Title:   "STRONG WIND WARNING",
-			Message: "Gale-force winds. Secure loose outdoor objects. Drive with care.",
+			Title:   tr("STRONG WIND WARNING"),
+			Message: tr("Gale-force winds. Secure loose outdoor objects. Drive with care."),
 		})
 	}
 
-	if isFog(cur.Icon) {
+	// Ensemble-driven: frost tonight while it's still above freezing now
+	if ens := info.Ensemble; ens != nil && tempC >= 0 && ens.FrostTonightPct >= 50 {
+		alerts = append(alerts, Alert{
+			Level:   AlertWarning,
+			Icon:    "wi-snowflake-cold",
+			Title:   tr("FROST LIKELY TONIGHT"),
+			Message: i18n.Sprintf(lang, "%d%% of ensemble members drop below freezing overnight. Protect plants and expect icy surfaces by morning.", ens.FrostTonightPct),
+		})
+	}
+
+	if cur.Condition.Sky == SkyFog {
 		alerts = append(alerts, Alert{
 			Level:   AlertInfo,
 			Icon:    "wi-fog",
-			Title:   "FOG ADVISORY",
-			Message: "Low visibility on roads. Use fog lights and reduce speed.",
+			Title:   tr("FOG ADVISORY"),
+			Message: tr("Low visibility on roads. Use fog lights and reduce speed."),
+		})
+	}
+
+	if wbgt.Level == comfort.Caution {
+		alerts = append(alerts, Alert{
+			Level:   AlertInfo,
+			Icon:    "wi-thermometer",
+			Title:   tr("ELEVATED HEAT STRESS"),
+			Message: wbgtMsg,
 		})
 	}
 
@@ -138,8 +183,8 @@ func Alerts(info *WeatherInfo) []Alert {
 		alerts = append(alerts, Alert{
 			Level:   AlertInfo,
 			Icon:    "wi-humidity",
-			Title:   "HIGH HUMIDITY",
-			Message: "Air feels heavy and muggy. Stay hydrated and take it easy outdoors.",
+			Title:   tr("HIGH HUMIDITY"),
+			Message: tr("Air feels heavy and muggy. Stay hydrated and take it easy outdoors."),
 		})
 	}
 
@@ -147,15 +192,19 @@ func Alerts(info *WeatherInfo) []Alert {
 		alerts = append(alerts, Alert{
 			Level:   AlertInfo,
 			Icon:    "wi-windy",
-			Title:   "WINDY CONDITIONS",
-			Message: "Fresh to strong breeze. Hold onto your hat — literally.",
+			Title:   tr("WINDY CONDITIONS"),
+			Message: tr("Fresh to strong breeze. Hold onto your hat — literally."),
+		})
+	}
+
+	if ens := info.Ensemble; ens != nil && ens.HeavyRainTomorrowPct >= 60 {
+		alerts = append(alerts, Alert{
+			Level:   AlertInfo,
+			Icon:    "wi-rain",
+			Title:   tr("HEAVY RAIN LIKELY TOMORROW"),
+			Message: i18n.Sprintf(lang, "%d%% chance of more than 5 mm tomorrow across the ensemble. Plan for wet travel.", ens.HeavyRainTomorrowPct),
 		})
 	}
 
 	return alerts
 }
-
-func isThunder(icon string) bool   { return icon == "wi-thunderstorm" || icon == "wi-storm-showers" }
-func isHeavyRain(icon string) bool { return icon == "wi-rain-wind" }
-func isHeavySnow(icon string) bool { return icon == "wi-snow-wind" }
-func isFog(icon string) bool       { return icon == "wi-fog" }
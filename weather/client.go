@@ -9,7 +9,12 @@ import (
 	"net"
 	"net/http"
 	"net/url"
+	"sync"
 	"time"
+
+	"WeatherApp/astro"
+	"WeatherApp/comfort"
+	"WeatherApp/i18n"
 )
 
 const (
@@ -21,6 +26,28 @@ const (
 // Client calls Open-Meteo APIs (no API key required).
 type Client struct {
 	HTTP *http.Client
+
+	// Models is the set of forecast models compared by FetchConsensus.
+	// When empty, DefaultModels is used.
+	Models []ForecastModel
+
+	// Aggregation picks the headline consensus value; empty means AggMean.
+	Aggregation Aggregation
+
+	// Verify, when set, records each fetch's model forecasts and later
+	// observations for skill scoring. SkillWeighting additionally scales
+	// consensus model weights by their verified skill at the location.
+	Verify         *VerifyStore
+	SkillWeighting bool
+
+	// EnsembleModels feed FetchEnsemble. When empty, DefaultEnsembleModels
+	// is used.
+	EnsembleModels []ForecastModel
+
+	// Lang is the i18n locale for place names from geocoding and for the
+	// text WeatherInfo builds (alerts, outfit notes, the narrative). Empty
+	// means English.
+	Lang string
 }
 
 // NewClient returns a Client with a 30-second timeout and a resilient DNS
@@ -45,7 +72,11 @@ func NewClient() *Client {
 			Resolver: resolver,
 		}).DialContext,
 	}
-	return &Client{HTTP: &http.Client{Timeout: 30 * time.Second, Transport: transport}}
+	return &Client{
+		HTTP:           &http.Client{Timeout: 30 * time.Second, Transport: transport},
+		Models:         DefaultModels,
+		EnsembleModels: DefaultEnsembleModels,
+	}
 }
 
 // --- internal API types ---
@@ -75,6 +106,7 @@ type currentRaw struct {
 	Pressure    float64 `json:"pressure_msl"`
 	DewPoint    float64 `json:"dew_point_2m"`
 	UVIndex     float64 `json:"uv_index"`
+	Radiation   float64 `json:"shortwave_radiation"`
 }
 
 type dailyRaw struct {
@@ -94,6 +126,14 @@ type hourlyRaw struct {
 	PrecipProb  []int     `json:"precipitation_probability"`
 	WeatherCode []int     `json:"weather_code"`
 	WindSpeed   []float64 `json:"wind_speed_10m"`
+	WindGust    []float64 `json:"wind_gusts_10m"`
+	FeelsLike   []float64 `json:"apparent_temperature"`
+	UVIndex     []float64 `json:"uv_index"`
+	CloudCover  []int     `json:"cloud_cover"`
+	Humidity    []int     `json:"relative_humidity_2m"`
+	CloudLow    []int     `json:"cloud_cover_low"`
+	CloudMid    []int     `json:"cloud_cover_mid"`
+	CloudHigh   []int     `json:"cloud_cover_high"`
 }
 
 type forecastRaw struct {
@@ -109,6 +149,8 @@ type CurrentDisplay struct {
 	Temp        float64
 	FeelsLike   float64
 	Humidity    int
+	Code        int // WMO weather code
+	Condition   Condition
 	Description string
 	Icon        string
 	CloudCover  int
@@ -117,26 +159,42 @@ type CurrentDisplay struct {
 	Pressure    float64
 	DewPoint    float64
 	UVIndex     float64
+	Radiation   float64         // shortwave, W/m²
+	Stress      comfort.Indices // heat index, humidex, WBGT, wind chill
 }
 
 type ForecastDay struct {
 	Date        string
+	Code        int // WMO weather code
+	Condition   Condition
 	Description string
 	Icon        string
 	TempMax     float64
 	TempMin     float64
 	WindMax     float64
 	PrecipProb  int // 0-100 percent probability of precipitation
+
+	// Model spread for the high and low, set when consensus is available.
+	TempMaxBand Band
+	TempMinBand Band
+	Confidence  string // weaker of the two bands' confidence
 }
 
 // HourlyPoint holds weather data for one hour.
 type HourlyPoint struct {
 	Time        string // "HH:MM"
+	DateTime    string // "2006-01-02T15:04", local time
 	Temp        float64
+	FeelsLike   float64
 	PrecipProb  int
+	Code        int // WMO weather code
+	Condition   Condition
 	Description string
 	Icon        string
 	WindSpeed   float64
+	WindGust    float64
+	UVIndex     float64
+	TempBand    Band // model spread, set when consensus is available
 }
 
 // SunBar holds values needed to render the sunrise/sunset arc.
@@ -149,20 +207,35 @@ type SunBar struct {
 	DaylightHours  string
 	MoonPhase      float64 // 0.0 = new moon, 0.5 = full moon, 1.0 = new moon
 	MoonPhaseName  string  // e.g. "Waxing Crescent"
+
+	// Polar is "Polar day" or "Polar night" when the sun does not rise or
+	// set; SunriseTime and SunsetTime are then "—" and the arc tracks the
+	// whole day.
+	Polar     string
+	Times     astro.SunTimes // twilights, golden and blue hour
+	Moon      astro.MoonTimes
+	MoonIllum float64 // 0-1 fraction of the disc lit
 }
 
 type WeatherInfo struct {
 	CityName    string
 	Country     string
 	CountryCode string
+	Latitude    float64
+	Longitude   float64
 	TempUnit    string
 	WindUnit    string
 	Current     CurrentDisplay
 	Forecast    []ForecastDay
 	Hourly      []HourlyPoint // next 24 hours
+	UVCurve     []UVHour      // today's hours (tomorrow's after dark)
+	Stars       []StarNight   // stargazing outlook, one entry per night
+	Photo       []PhotoDay    // golden and blue hours per forecast day
 	Sun         SunBar
 	Consensus   *ConsensusInfo
+	Ensemble    *EnsembleInfo // nil when the ensemble API is unavailable
 	Outfit      OutfitAdvice
+	Lang        string // locale for alerts, outfit notes and the narrative; Description stays English
 }
 
 // getJSON makes a GET request with one automatic retry on timeout/connection error.
@@ -195,9 +268,18 @@ func (c *Client) getJSON(rawURL string, v any) error {
 	return lastErr
 }
 
-// Geocode resolves a city name to coordinates.
+// lang is c.Lang, or the default locale.
+func (c *Client) lang() string {
+	if c.Lang == "" {
+		return i18n.Default
+	}
+	return c.Lang
+}
+
+// Geocode resolves a city name to coordinates, with the place and country
+// names in c.Lang where the geocoder has them.
 func (c *Client) Geocode(city string) (*GeoLocation, error) {
-	u := fmt.Sprintf("%s?name=%s&count=1&language=en&format=json", geoURL, url.QueryEscape(city))
+	u := fmt.Sprintf("%s?name=%s&count=1&language=%s&format=json", geoURL, url.QueryEscape(city), c.lang())
 	var geo geoResponse
 	if err := c.getJSON(u, &geo); err != nil {
 		return nil, fmt.Errorf("geocode: %w", err)
@@ -220,7 +302,7 @@ func (c *Client) ReverseGeocode(lat, lon float64) (string, error) {
 	}
 	// Nominatim policy requires a descriptive User-Agent
 	req.Header.Set("User-Agent", "GoWeatherApp/1.0")
-	req.Header.Set("Accept-Language", "en")
+	req.Header.Set("Accept-Language", c.lang())
 
 	resp, err := c.HTTP.Do(req)
 	if err != nil {
@@ -282,8 +364,8 @@ func (c *Client) GetWeather(city, units string) (*WeatherInfo, error) {
 
 	u := fmt.Sprintf(
 		"%s?latitude=%.4f&longitude=%.4f"+
-			"&current=temperature_2m,apparent_temperature,relative_humidity_2m,weather_code,cloud_cover,wind_speed_10m,wind_direction_10m,pressure_msl,dew_point_2m,uv_index"+
-			"&hourly=temperature_2m,precipitation_probability,weather_code,wind_speed_10m"+
+			"&current=temperature_2m,apparent_temperature,relative_humidity_2m,weather_code,cloud_cover,wind_speed_10m,wind_direction_10m,pressure_msl,dew_point_2m,uv_index,shortwave_radiation"+
+			"&hourly=temperature_2m,precipitation_probability,weather_code,wind_speed_10m,wind_gusts_10m,apparent_temperature,uv_index,cloud_cover,relative_humidity_2m,cloud_cover_low,cloud_cover_mid,cloud_cover_high"+
 			"&daily=weather_code,temperature_2m_max,temperature_2m_min,wind_speed_10m_max,precipitation_probability_max,sunrise,sunset"+
 			"&temperature_unit=%s&wind_speed_unit=%s&timezone=%s&forecast_days=5",
 		forecastURL, loc.Latitude, loc.Longitude,
@@ -299,13 +381,18 @@ func (c *Client) GetWeather(city, units string) (*WeatherInfo, error) {
 		CityName:    loc.Name,
 		Country:     loc.Country,
 		CountryCode: loc.CountryCode,
+		Latitude:    loc.Latitude,
+		Longitude:   loc.Longitude,
 		TempUnit:    TempUnitSymbol(units),
 		WindUnit:    WindUnitLabel(units),
+		Lang:        c.lang(),
 		Current: CurrentDisplay{
 			Time:        raw.Current.Time,
 			Temp:        raw.Current.Temperature,
 			FeelsLike:   raw.Current.FeelsLike,
 			Humidity:    raw.Current.Humidity,
+			Code:        raw.Current.WeatherCode,
+			Condition:   WMOCondition(raw.Current.WeatherCode),
 			Description: WMODescription(raw.Current.WeatherCode),
 			Icon:        WMOIconClass(raw.Current.WeatherCode),
 			CloudCover:  raw.Current.CloudCover,
@@ -314,8 +401,16 @@ func (c *Client) GetWeather(city, units string) (*WeatherInfo, error) {
 			Pressure:    raw.Current.Pressure,
 			DewPoint:    raw.Current.DewPoint,
 			UVIndex:     raw.Current.UVIndex,
+			Radiation:   raw.Current.Radiation,
 		},
 	}
+	info.Current.Stress = comfort.Compute(comfort.Conditions{
+		TempC:     toCelsius(raw.Current.Temperature, info.TempUnit),
+		RH:        float64(raw.Current.Humidity),
+		DewPointC: toCelsius(raw.Current.DewPoint, info.TempUnit),
+		WindKmh:   toKmh(raw.Current.WindSpeed, info.WindUnit),
+		SolarWm2:  raw.Current.Radiation,
+	})
 
 	for i, date := range raw.Daily.Time {
 		if i >= len(raw.Daily.WeatherCode) || i >= len(raw.Daily.TempMax) {
@@ -327,6 +422,8 @@ func (c *Client) GetWeather(city, units string) (*WeatherInfo, error) {
 		}
 		info.Forecast = append(info.Forecast, ForecastDay{
 			Date:        date,
+			Code:        raw.Daily.WeatherCode[i],
+			Condition:   WMOCondition(raw.Daily.WeatherCode[i]),
 			Description: WMODescription(raw.Daily.WeatherCode[i]),
 			Icon:        WMOIconClass(raw.Daily.WeatherCode[i]),
 			TempMax:     raw.Daily.TempMax[i],
@@ -337,63 +434,92 @@ func (c *Client) GetWeather(city, units string) (*WeatherInfo, error) {
 	}
 
 	if len(raw.Daily.Sunrise) > 0 && len(raw.Daily.Sunset) > 0 {
-		info.Sun = buildSunBar(raw.Current.Time, raw.Daily.Sunrise[0], raw.Daily.Sunset[0], loc.Timezone)
+		info.Sun = buildSunBar(raw.Current.Time, loc.Latitude, loc.Longitude, loc.Timezone)
 	}
 
 	// Parse next 24 hourly points starting from the current hour.
 	info.Hourly = parseHourly(raw.Hourly, raw.Current.Time, loc.Timezone)
+	info.UVCurve = parseUVCurve(raw.Hourly, raw.Daily, raw.Current.Time)
+	info.Photo = parsePhotoPlan(raw.Hourly, info.Sun, raw.Current.Time, loc.Latitude, loc.Longitude, loc.Timezone)
+	info.Stars = parseStargazing(raw.Hourly, raw.Current.Time, loc.Latitude, loc.Longitude, loc.Timezone)
 
 	// Build outfit advice from current conditions.
 	info.Outfit = BuildOutfit(info)
 
-	// Fetch multi-model consensus in parallel (non-fatal if it fails)
-	info.Consensus = c.FetchConsensus(loc.Latitude, loc.Longitude, loc.Timezone, tempUnit, windUnit)
+	// Fetch multi-model consensus and the ensemble side by side (both non-fatal)
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		info.Consensus = c.FetchConsensus(loc.Latitude, loc.Longitude, loc.Timezone, tempUnit, windUnit)
+	}()
+	go func() {
+		defer wg.Done()
+		if ens, err := c.FetchEnsemble(loc.Latitude, loc.Longitude, loc.Timezone, tempUnit, raw.Current.Time); err == nil {
+			info.Ensemble = ens
+		}
+	}()
+	wg.Wait()
+	info.Consensus.ApplyBands(info, tempUnit)
+
+	// Record forecasts for verification (non-fatal: skill tracking must never
+	// stop the weather from showing).
+	if c.Verify != nil {
+		var observed map[string]float64
+		if c.Verify.HasDue(loc.Latitude, loc.Longitude, raw.Current.Time) {
+			observed, _ = fetchObserved(loc.Latitude, loc.Longitude, loc.Timezone, tempUnit)
+		}
+		_ = c.Verify.Record(info, loc.Latitude, loc.Longitude, observed)
+	}
 
 	return info, nil
 }
 
-// buildSunBar computes all values for the sunrise/sunset progress bar.
-func buildSunBar(currentTimeStr, sunriseStr, sunsetStr, timezone string) SunBar {
-	const layout = "2006-01-02T15:04"
-
+// buildSunBar computes all values for the sunrise/sunset progress bar from
+// the offline astronomy engine, so it works for any day and at the poles.
+func buildSunBar(currentTimeStr string, lat, lon float64, timezone string) SunBar {
 	tz, err := time.LoadLocation(timezone)
 	if err != nil {
 		tz = time.UTC
 	}
-
-	parse := func(s string) time.Time {
-		t, _ := time.ParseInLocation(layout, s, tz)
-		return t
+	now, err := time.ParseInLocation("2006-01-02T15:04", currentTimeStr, tz)
+	if err != nil {
+		return SunBar{}
 	}
 
-	sunrise := parse(sunriseStr)
-	sunset := parse(sunsetStr)
-	now := parse(currentTimeStr)
-
-	daylightMins := sunset.Sub(sunrise).Minutes()
-	h := int(daylightMins) / 60
-	m := int(daylightMins) % 60
-
-	isDay := now.After(sunrise) && now.Before(sunset)
-
-	var positionPct float64
-	if daylightMins > 0 {
-		elapsed := now.Sub(sunrise).Minutes()
-		positionPct = elapsed / daylightMins * 100
-		positionPct = math.Max(0, math.Min(100, positionPct))
+	times := astro.Sun(now, lat, lon)
+	daylight := times.Daylight()
+	bar := SunBar{
+		SunriseTime:   astro.Clock(times.Sunrise),
+		SunsetTime:    astro.Clock(times.Sunset),
+		CurrentTime:   now.Format("15:04"),
+		DaylightHours: fmt.Sprintf("%dh %dm", int(daylight.Hours()), int(daylight.Minutes())%60),
+		Times:         times,
+		Moon:          astro.Moon(now, lat, lon),
 	}
 
-	mp := moonPhase(now.UTC())
-	return SunBar{
-		SunriseTime:    sunrise.Format("15:04"),
-		SunsetTime:     sunset.Format("15:04"),
-		CurrentTime:    now.Format("15:04"),
-		SunPositionPct: positionPct,
-		IsDay:          isDay,
-		DaylightHours:  fmt.Sprintf("%dh %dm", h, m),
-		MoonPhase:      mp,
-		MoonPhaseName:  moonPhaseName(mp),
+	switch {
+	case times.PolarDay || times.PolarNight:
+		// No horizon crossings: the arc runs midnight to midnight.
+		bar.Polar = "Polar night"
+		if times.PolarDay {
+			bar.Polar = "Polar day"
+		}
+		bar.IsDay = times.PolarDay
+		midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, tz)
+		bar.SunPositionPct = now.Sub(midnight).Hours() / 24 * 100
+	default:
+		bar.IsDay = now.After(times.Sunrise) && now.Before(times.Sunset)
+		if mins := daylight.Minutes(); mins > 0 {
+			bar.SunPositionPct = math.Max(0, math.Min(100, now.Sub(times.Sunrise).Minutes()/mins*100))
+		}
 	}
+
+	illum := astro.MoonIllumination(now)
+	bar.MoonPhase = illum.Phase
+	bar.MoonPhaseName = moonPhaseName(illum.Phase)
+	bar.MoonIllum = illum.Fraction
+	return bar
 }
 
 // parseHourly extracts the next 24 hourly points starting from currentTimeStr.
@@ -426,11 +552,17 @@ func parseHourly(h hourlyRaw, currentTimeStr, timezone string) []HourlyPoint {
 		temp := safeFloat(h.Temperature, i)
 		points = append(points, HourlyPoint{
 			Time:        t.Format("15:04"),
+			DateTime:    ts,
 			Temp:        temp,
+			FeelsLike:   safeFloat(h.FeelsLike, i),
 			PrecipProb:  pp,
+			Code:        wc,
+			Condition:   WMOCondition(wc),
 			Description: WMODescription(wc),
 			Icon:        WMOIconClass(wc),
 			WindSpeed:   ws,
+			WindGust:    safeFloat(h.WindGust, i),
+			UVIndex:     safeFloat(h.UVIndex, i),
 		})
 	}
 	return points
@@ -483,12 +615,16 @@ func WMODescription(code int) string {
 		return "Light drizzle"
 	case code == 55:
 		return "Dense drizzle"
+	case code == 56 || code == 57:
+		return "Freezing drizzle"
 	case code == 61:
 		return "Slight rain"
 	case code == 63:
 		return "Moderate rain"
 	case code == 65:
 		return "Heavy rain"
+	case code == 66 || code == 67:
+		return "Freezing rain"
 	case code == 71:
 		return "Slight snow"
 	case code == 73:
@@ -527,12 +663,16 @@ func WMOIconClass(code int) string {
 		return "wi-sprinkle"
 	case code == 55:
 		return "wi-rain-mix"
+	case code == 56 || code == 57:
+		return "wi-sleet"
 	case code == 61:
 		return "wi-rain-mix"
 	case code == 63:
 		return "wi-rain"
 	case code == 65:
 		return "wi-rain-wind"
+	case code == 66 || code == 67:
+		return "wi-sleet"
 	case code == 71:
 		return "wi-snow"
 	case code == 73:
@@ -563,23 +703,6 @@ func WindCompass(deg int) string {
 
 // ── Moon phase ────────────────────────────────────────────────────────────────
 
-// synodicMonth is the average length of a lunar cycle in days.
-const synodicMonth = 29.530589
-
-// referenceNewMoon is a known new moon date used as the calculation epoch.
-var referenceNewMoon = time.Date(2000, 1, 6, 18, 14, 0, 0, time.UTC)
-
-// moonPhase returns a value in [0, 1) representing the current lunar phase:
-// 0 = new moon, 0.25 = first quarter, 0.5 = full moon, 0.75 = last quarter.
-func moonPhase(t time.Time) float64 {
-	days := t.Sub(referenceNewMoon).Hours() / 24
-	phase := math.Mod(days, synodicMonth) / synodicMonth
-	if phase < 0 {
-		phase += 1
-	}
-	return phase
-}
-
 // moonPhaseName maps a phase fraction to a human-readable name.
 func moonPhaseName(p float64) string {
 	switch {
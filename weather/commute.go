@@ -0,0 +1,117 @@
+package weather
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// TimeWindow is a named daily time range such as a commute. From and To are
+// local "HH:MM"; a To earlier than From ends the next day.
+type TimeWindow struct {
+	Name string // optional, e.g. "work"
+	From string
+	To   string
+}
+
+// String formats the window as ParseWindows accepts it, e.g. "work=08:00-18:00".
+func (w TimeWindow) String() string {
+	if w.Name == "" {
+		return w.From + "-" + w.To
+	}
+	return w.Name + "=" + w.From + "-" + w.To
+}
+
+// Label is the display form, e.g. "work 08:00–18:00".
+func (w TimeWindow) Label() string {
+	span := w.From + "–" + w.To
+	if w.Name == "" {
+		return span
+	}
+	return w.Name + " " + span
+}
+
+// ParseWindows parses a comma-separated list of windows such as
+// "08:00-18:00" or "work=08:00-18:00,gym=19:00-21:00".
+func ParseWindows(spec string) ([]TimeWindow, error) {
+	var windows []TimeWindow
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		var w TimeWindow
+		if name, rest, ok := strings.Cut(part, "="); ok {
+			w.Name = strings.TrimSpace(name)
+			part = strings.TrimSpace(rest)
+		}
+		from, to, ok := strings.Cut(part, "-")
+		if !ok {
+			return nil, fmt.Errorf("time window %q: want HH:MM-HH:MM", part)
+		}
+		for _, t := range []*string{&from, &to} {
+			parsed, err := time.Parse("15:04", strings.TrimSpace(*t))
+			if err != nil {
+				return nil, fmt.Errorf("time window %q: bad time %q", part, strings.TrimSpace(*t))
+			}
+			*t = parsed.Format("15:04")
+		}
+		if from == to {
+			return nil, fmt.Errorf("time window %q is empty", part)
+		}
+		w.From, w.To = from, to
+		windows = append(windows, w)
+	}
+	return windows, nil
+}
+
+// FormatWindows is the inverse of ParseWindows.
+func FormatWindows(windows []TimeWindow) string {
+	parts := make([]string, len(windows))
+	for i, w := range windows {
+		parts[i] = w.String()
+	}
+	return strings.Join(parts, ",")
+}
+
+// Next returns the next occurrence of the window relative to now, which is
+// still today's if it has not ended yet.
+func (w TimeWindow) Next(now time.Time) (from, to time.Time) {
+	f, _ := time.Parse("15:04", w.From)
+	t, _ := time.Parse("15:04", w.To)
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	from = day.Add(time.Duration(f.Hour())*time.Hour + time.Duration(f.Minute())*time.Minute)
+	to = day.Add(time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute)
+	if !to.After(from) {
+		to = to.AddDate(0, 0, 1)
+	}
+	if !to.After(now) {
+		from, to = from.AddDate(0, 0, 1), to.AddDate(0, 0, 1)
+	}
+	return from, to
+}
+
+// CommuteOutfit pairs a saved window with the outfit for its next occurrence.
+type CommuteOutfit struct {
+	Window TimeWindow
+	Day    string // "Today" or "Tomorrow"
+	Outfit OutfitAdvice
+}
+
+// CommuteOutfits runs opts.BuildFor for the next occurrence of each window.
+func CommuteOutfits(info *WeatherInfo, windows []TimeWindow, opts OutfitOptions) []CommuteOutfit {
+	now, err := time.Parse("2006-01-02T15:04", info.Current.Time)
+	if err != nil {
+		return nil
+	}
+	out := make([]CommuteOutfit, 0, len(windows))
+	for _, w := range windows {
+		from, to := w.Next(now)
+		day := "Today"
+		if from.YearDay() != now.YearDay() {
+			day = "Tomorrow"
+		}
+		out = append(out, CommuteOutfit{Window: w, Day: day, Outfit: opts.BuildFor(info, from, to)})
+	}
+	return out
+}
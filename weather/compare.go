@@ -0,0 +1,95 @@
+package weather
+
+import (
+	"slices"
+	"sync"
+)
+
+// MaxCompareCities caps a comparison so a request can't fan out into
+// dozens of forecast fetches.
+const MaxCompareCities = 6
+
+// CityWeather is one column of a comparison: the city as asked for and its
+// forecast, or the error that replaced it.
+type CityWeather struct {
+	Query string
+	Info  *WeatherInfo
+	Err   string
+}
+
+// Comparison lines several cities up side by side.
+type Comparison struct {
+	Cities []CityWeather
+	Dates  []string // forecast dates, "2006-01-02", shared by the table and charts
+}
+
+// Compare fetches cities concurrently with fetch and keeps them in the order
+// given. Failures stay in place with Err set so the other columns still show.
+func Compare(cities []string, fetch func(city string) (*WeatherInfo, error)) *Comparison {
+	cmp := &Comparison{Cities: make([]CityWeather, len(cities))}
+	var wg sync.WaitGroup
+	for i, city := range cities {
+		wg.Add(1)
+		go func(i int, city string) {
+			defer wg.Done()
+			cw := CityWeather{Query: city}
+			if info, err := fetch(city); err != nil {
+				cw.Err = err.Error()
+			} else {
+				cw.Info = info
+			}
+			cmp.Cities[i] = cw
+		}(i, city)
+	}
+	wg.Wait()
+
+	// Cities east of the date line are a day ahead, so take the union of
+	// dates and keep the first five.
+	for _, c := range cmp.Cities {
+		if c.Info == nil {
+			continue
+		}
+		for _, d := range c.Info.Forecast {
+			if !slices.Contains(cmp.Dates, d.Date) {
+				cmp.Dates = append(cmp.Dates, d.Date)
+			}
+		}
+	}
+	slices.Sort(cmp.Dates)
+	if len(cmp.Dates) > 5 {
+		cmp.Dates = cmp.Dates[:5]
+	}
+	return cmp
+}
+
+// Day returns the city's forecast for date, or nil when it has none.
+func (c CityWeather) Day(date string) *ForecastDay {
+	if c.Info == nil {
+		return nil
+	}
+	for i := range c.Info.Forecast {
+		if c.Info.Forecast[i].Date == date {
+			return &c.Info.Forecast[i]
+		}
+	}
+	return nil
+}
+
+// Name is the resolved city name, or the query when the fetch failed.
+func (c CityWeather) Name() string {
+	if c.Info == nil {
+		return c.Query
+	}
+	return c.Info.CityName
+}
+
+// MaxPrecipProb is the highest chance of precipitation in the next 24 hours.
+func (c CityWeather) MaxPrecipProb() int {
+	p := 0
+	if c.Info != nil {
+		for _, h := range c.Info.Hourly {
+			p = max(p, h.PrecipProb)
+		}
+	}
+	return p
+}
@@ -0,0 +1,102 @@
+package weather
+
+// Sky is the cloud state of a dry WMO code.
+type Sky string
+
+const (
+	SkyClear        Sky = "clear"
+	SkyMainlyClear  Sky = "mainly clear"
+	SkyPartlyCloudy Sky = "partly cloudy"
+	SkyOvercast     Sky = "overcast"
+	SkyFog          Sky = "fog"
+)
+
+// PrecipKind is what is falling.
+type PrecipKind string
+
+const (
+	PrecipNone    PrecipKind = ""
+	PrecipDrizzle PrecipKind = "drizzle"
+	PrecipRain    PrecipKind = "rain"
+	PrecipSnow    PrecipKind = "snow"
+)
+
+// Intensity grades precipitation as WMO codes do: slight, moderate, heavy.
+type Intensity int
+
+const (
+	IntensityNone Intensity = iota
+	IntensityLight
+	IntensityModerate
+	IntensityHeavy
+)
+
+func (i Intensity) String() string {
+	return [...]string{"none", "light", "moderate", "heavy"}[i]
+}
+
+// Condition is a WMO weather code broken down into what it describes, so
+// rules can ask "heavy snow?" instead of matching descriptions or icons.
+type Condition struct {
+	Code      int
+	Sky       Sky        // set for dry codes, empty when something is falling
+	Precip    PrecipKind // PrecipNone when dry
+	Intensity Intensity  // of Precip
+	Showers   bool       // convective: showers and thunderstorms
+	Freezing  bool       // freezing drizzle or rain, or rime fog
+	Thunder   bool
+	Hail      bool
+}
+
+// Wet reports whether anything is falling.
+func (c Condition) Wet() bool { return c.Precip != PrecipNone }
+
+// WMOCondition decodes a WMO weather code. Unknown codes give a Condition
+// with only Code set.
+func WMOCondition(code int) Condition {
+	c := Condition{Code: code}
+	switch code {
+	case 0:
+		c.Sky = SkyClear
+	case 1:
+		c.Sky = SkyMainlyClear
+	case 2:
+		c.Sky = SkyPartlyCloudy
+	case 3:
+		c.Sky = SkyOvercast
+	case 45, 48:
+		c.Sky, c.Freezing = SkyFog, code == 48
+	case 51, 53, 55:
+		c.Precip, c.Intensity = PrecipDrizzle, Intensity((code-49)/2)
+	case 56, 57:
+		c.Precip, c.Intensity, c.Freezing = PrecipDrizzle, lightOrHeavy(code == 57), true
+	case 61, 63, 65:
+		c.Precip, c.Intensity = PrecipRain, Intensity((code-59)/2)
+	case 66, 67:
+		c.Precip, c.Intensity, c.Freezing = PrecipRain, lightOrHeavy(code == 67), true
+	case 71, 73, 75:
+		c.Precip, c.Intensity = PrecipSnow, Intensity((code-69)/2)
+	case 77:
+		c.Precip, c.Intensity = PrecipSnow, IntensityLight
+	case 80, 81, 82:
+		c.Precip, c.Intensity, c.Showers = PrecipRain, Intensity(code-79), true
+	case 85, 86:
+		c.Precip, c.Intensity, c.Showers = PrecipSnow, lightOrHeavy(code == 86), true
+	case 95:
+		c.Precip, c.Intensity, c.Showers, c.Thunder = PrecipRain, IntensityModerate, true, true
+	case 96, 99:
+		c.Precip, c.Intensity, c.Showers, c.Thunder, c.Hail = PrecipRain, IntensityModerate, true, true, true
+		if code == 99 {
+			c.Intensity = IntensityHeavy
+		}
+	}
+	return c
+}
+
+// lightOrHeavy grades the codes WMO splits only into slight and heavy.
+func lightOrHeavy(heavy bool) Intensity {
+	if heavy {
+		return IntensityHeavy
+	}
+	return IntensityLight
+}
@@ -5,19 +5,104 @@ import (
 	"math"
 	"net/http"
 	"net/url"
+	"sort"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 )
 
-// forecastModels lists the free Open-Meteo models used for consensus.
-var forecastModels = []struct {
-	Name  string
-	Param string
-}{
-	{"ECMWF", "ecmwf_ifs025"},
-	{"ICON", "icon_seamless"},
-	{"Météo-France", "meteofrance_seamless"},
-	{"MET Norway", "metno_seamless"},
+// ForecastModel identifies one Open-Meteo forecast model.
+type ForecastModel struct {
+	Name   string  // display name, e.g. "ECMWF"
+	Param  string  // value of the Open-Meteo models= parameter
+	Weight float64 // relative weight in consensus; 0 is treated as 1
+}
+
+// Aggregation selects how the headline consensus value is computed.
+type Aggregation string
+
+const (
+	AggMean    Aggregation = "mean"    // weighted mean of included models
+	AggMedian  Aggregation = "median"  // median of included models
+	AggTrimmed Aggregation = "trimmed" // weighted mean without the highest and lowest model
+)
+
+// ParseAggregation validates an aggregation name. Empty means AggMean.
+func ParseAggregation(s string) (Aggregation, error) {
+	switch a := Aggregation(strings.ToLower(strings.TrimSpace(s))); a {
+	case "":
+		return AggMean, nil
+	case AggMean, AggMedian, AggTrimmed:
+		return a, nil
+	default:
+		return "", fmt.Errorf("unknown aggregation %q (want mean, median or trimmed)", s)
+	}
+}
+
+// KnownModels maps a short key to the free Open-Meteo models that can take
+// part in consensus. Keys are what users pass to ParseModels.
+var KnownModels = map[string]ForecastModel{
+	"ecmwf":       {"ECMWF", "ecmwf_ifs025", 1},
+	"icon":        {"ICON", "icon_seamless", 1},
+	"meteofrance": {"Météo-France", "meteofrance_seamless", 1},
+	"metno":       {"MET Norway", "metno_seamless", 1},
+	"gfs":         {"GFS", "gfs_seamless", 1},
+	"jma":         {"JMA", "jma_seamless", 1},
+	"gem":         {"GEM", "gem_seamless", 1},
+	"ukmo":        {"UKMO", "ukmo_seamless", 1},
+}
+
+// DefaultModels is the model set used when a Client has none configured.
+var DefaultModels = []ForecastModel{
+	KnownModels["ecmwf"],
+	KnownModels["icon"],
+	KnownModels["meteofrance"],
+	KnownModels["metno"],
+}
+
+// ParseModels turns a comma-separated list such as "ecmwf,gfs,jma" into a
+// model set. Entries may be a KnownModels key or a raw Open-Meteo model
+// parameter (e.g. "knmi_seamless"), which is used as its own display name.
+// A ":weight" suffix sets a static weight, e.g. "ecmwf:2,icon,gfs:0.5".
+func ParseModels(spec string) ([]ForecastModel, error) {
+	return parseModelList(spec, KnownModels)
+}
+
+func parseModelList(spec string, catalog map[string]ForecastModel) ([]ForecastModel, error) {
+	var models []ForecastModel
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(spec, ",") {
+		key := strings.ToLower(strings.TrimSpace(part))
+		if key == "" {
+			continue
+		}
+		weight := 1.0
+		if name, w, ok := strings.Cut(key, ":"); ok {
+			v, err := strconv.ParseFloat(w, 64)
+			if err != nil || v <= 0 {
+				return nil, fmt.Errorf("invalid weight in %q", part)
+			}
+			key, weight = name, v
+		}
+		m, ok := catalog[key]
+		if !ok {
+			if strings.ContainsAny(key, " &?=/") {
+				return nil, fmt.Errorf("invalid model %q", part)
+			}
+			m = ForecastModel{Name: key, Param: key}
+		}
+		m.Weight = weight
+		if seen[m.Param] {
+			continue
+		}
+		seen[m.Param] = true
+		models = append(models, m)
+	}
+	if len(models) == 0 {
+		return nil, fmt.Errorf("no models in %q", spec)
+	}
+	return models, nil
 }
 
 // ModelReading is current weather data from a single forecast model.
@@ -30,29 +115,79 @@ type ModelReading struct {
 	WeatherCode int
 	Available   bool
 	Err         string
+
+	Weight   float64 // relative weight in consensus (1 = equal)
+	Excluded bool    // dropped from consensus as an outlier
+	Reason   string  // why the model was excluded or discounted
+
+	// Forecast horizon from the same model. HourlyTemp is keyed by local
+	// "2006-01-02T15:04", DailyMax/DailyMin by "2006-01-02".
+	HourlyTemp map[string]float64
+	DailyMax   map[string]float64
+	DailyMin   map[string]float64
 }
 
-// ConsensusInfo holds per-model readings and derived consensus statistics.
-type ConsensusInfo struct {
-	Models     []ModelReading
-	AvailCount int
+// Used reports whether the reading contributes to consensus statistics.
+func (r ModelReading) Used() bool { return r.Available && !r.Excluded }
 
-	// Averages across all available models
-	AvgTemp     float64
-	AvgHumidity int
-	AvgWind     float64
-	AvgPressure float64
+// Discounted reports whether the reading counts for less than a full model.
+func (r ModelReading) Discounted() bool { return r.Used() && r.Weight < 1 }
 
-	// Temperature spread (max - min) as a disagreement measure
-	MinTemp float64
-	MaxTemp float64
-	Spread  float64
+// Band is the spread of model forecasts for one variable at one point in time.
+type Band struct {
+	Min    float64
+	Max    float64
+	Spread float64 // Max - Min
+	Models int     // models contributing; 0 means no band
+
+	Confidence string // "High" / "Moderate" / "Low"
+}
+
+// VarStats summarises one variable across all available models.
+type VarStats struct {
+	Value   float64 // headline value, per the consensus Aggregation
+	Mean    float64 // weighted
+	Median  float64
+	Trimmed float64 // weighted mean without the extremes (when 4+ models)
+	StdDev  float64 // weighted
+	Min     float64
+	Max     float64
+	Spread  float64 // Max - Min
 
-	// Agreement score
 	Agreement string // "High" / "Moderate" / "Low"
 	AgreePct  int    // 0-100
 }
 
+// CodeVote is the consensus weather code, chosen by majority vote.
+type CodeVote struct {
+	Code        int
+	Description string
+	Icon        string
+	Votes       int // models voting for Code
+
+	Agreement string
+	AgreePct  int // weighted share of models voting for Code
+}
+
+// ConsensusInfo holds per-model readings and derived consensus statistics.
+type ConsensusInfo struct {
+	Models     []ModelReading
+	AvailCount int         // models that returned data
+	UsedCount  int         // models left after outlier exclusion
+	Method     Aggregation // how VarStats.Value was computed
+
+	Temp     VarStats
+	Humidity VarStats
+	Wind     VarStats
+	Pressure VarStats
+	Weather  CodeVote
+
+	// Agreement/AgreePct are the weakest of the numeric per-variable scores,
+	// so one variable the models disagree on is enough to lower them.
+	Agreement string
+	AgreePct  int
+}
+
 // modelCurrentRaw uses pointers so null JSON fields don't cause decode errors.
 type modelCurrentRaw struct {
 	Temp     *float64 `json:"temperature_2m"`
@@ -61,15 +196,27 @@ type modelCurrentRaw struct {
 	Pressure *float64 `json:"pressure_msl"`
 	WMOCode  *int     `json:"weather_code"`
 }
+type modelHourlyRaw struct {
+	Time []string   `json:"time"`
+	Temp []*float64 `json:"temperature_2m"`
+}
+type modelDailyRaw struct {
+	Time    []string   `json:"time"`
+	TempMax []*float64 `json:"temperature_2m_max"`
+	TempMin []*float64 `json:"temperature_2m_min"`
+}
 type modelResponse struct {
 	Current modelCurrentRaw `json:"current"`
+	Hourly  modelHourlyRaw  `json:"hourly"`
+	Daily   modelDailyRaw   `json:"daily"`
 }
 
 // consensusClient is a separate HTTP client with a shorter timeout so slow
 // model fetches never block the main request beyond 6 seconds.
 var consensusClient = &Client{HTTP: &http.Client{Timeout: 6 * time.Second}}
 
-// fetchModel fetches current conditions from one Open-Meteo model.
+// fetchModel fetches current conditions and the hourly/daily temperature
+// forecast from one Open-Meteo model.
 // tempUnit must be "celsius" or "fahrenheit"; windUnit "kmh" or "mph".
 func fetchModel(name, modelParam string, lat, lon float64, timezone, tempUnit, windUnit string) ModelReading {
 	r := ModelReading{Model: name}
@@ -77,9 +224,10 @@ func fetchModel(name, modelParam string, lat, lon float64, timezone, tempUnit, w
 	u := fmt.Sprintf(
 		"%s?latitude=%.4f&longitude=%.4f"+
 			"&current=temperature_2m,relative_humidity_2m,wind_speed_10m,pressure_msl,weather_code"+
-			"&temperature_unit=%s&wind_speed_unit=%s&timezone=%s&models=%s",
+			"&hourly=temperature_2m&daily=temperature_2m_max,temperature_2m_min"+
+			"&temperature_unit=%s&wind_speed_unit=%s&timezone=%s&models=%s&forecast_days=5",
 		forecastURL, lat, lon, tempUnit, windUnit,
-		url.QueryEscape(timezone), modelParam,
+		url.QueryEscape(timezone), url.QueryEscape(modelParam),
 	)
 
 	var resp modelResponse
@@ -105,77 +253,351 @@ func fetchModel(name, modelParam string, lat, lon float64, timezone, tempUnit, w
 	if cur.WMOCode != nil {
 		r.WeatherCode = *cur.WMOCode
 	}
+	r.HourlyTemp = seriesMap(resp.Hourly.Time, resp.Hourly.Temp)
+	r.DailyMax = seriesMap(resp.Daily.Time, resp.Daily.TempMax)
+	r.DailyMin = seriesMap(resp.Daily.Time, resp.Daily.TempMin)
 	r.Available = true
 	return r
 }
 
-// FetchConsensus fetches 4 weather models in parallel and computes agreement stats.
+// seriesMap zips a time axis with nullable values, skipping nulls.
+func seriesMap(times []string, vals []*float64) map[string]float64 {
+	m := make(map[string]float64, len(times))
+	for i, t := range times {
+		if i < len(vals) && vals[i] != nil {
+			m[t] = *vals[i]
+		}
+	}
+	return m
+}
+
+// FetchConsensus fetches the client's models in parallel and computes agreement stats.
 // tempUnit must be "celsius"/"fahrenheit"; windUnit "kmh"/"mph".
 func (c *Client) FetchConsensus(lat, lon float64, timezone, tempUnit, windUnit string) *ConsensusInfo {
-	readings := make([]ModelReading, len(forecastModels))
+	models := c.Models
+	if len(models) == 0 {
+		models = DefaultModels
+	}
+
+	var skill map[string]float64
+	if c.SkillWeighting {
+		skill = c.Verify.SkillWeights(lat, lon)
+	}
+
+	readings := make([]ModelReading, len(models))
 	var wg sync.WaitGroup
 
-	for i, m := range forecastModels {
+	for i, m := range models {
 		wg.Add(1)
-		go func(idx int, name, param string) {
+		go func(idx int, m ForecastModel) {
 			defer wg.Done()
-			readings[idx] = fetchModel(name, param, lat, lon, timezone, tempUnit, windUnit)
-		}(i, m.Name, m.Param)
+			readings[idx] = fetchModel(m.Name, m.Param, lat, lon, timezone, tempUnit, windUnit)
+			readings[idx].Weight = m.Weight
+			if w, ok := skill[m.Name]; ok {
+				if readings[idx].Weight <= 0 {
+					readings[idx].Weight = 1
+				}
+				readings[idx].Weight *= w
+			}
+		}(i, m)
 	}
 	wg.Wait()
 
-	cons := &ConsensusInfo{Models: readings}
+	return buildConsensus(readings, c.Aggregation, tempUnit, windUnit)
+}
 
-	var sumTemp, sumWind, sumPressure float64
-	var sumHumidity, count int
-	minTemp := math.MaxFloat64
-	maxTemp := -math.MaxFloat64
+// buildConsensus flags outliers, then computes per-variable statistics from
+// the remaining model readings.
+func buildConsensus(readings []ModelReading, method Aggregation, tempUnit, windUnit string) *ConsensusInfo {
+	if method == "" {
+		method = AggMean
+	}
+	cons := &ConsensusInfo{Models: readings, Method: method}
 
-	for _, r := range readings {
-		if !r.Available {
-			continue
+	// Agreement penalties and outlier floors are per unit in metric units,
+	// so the same disagreement scores the same whichever units were requested.
+	tempScale, windScale := 1.0, 1.0
+	tempSym, windSym := "°C", "km/h"
+	if tempUnit == "fahrenheit" {
+		tempScale, tempSym = 5.0/9.0, "°F"
+	}
+	if windUnit == "mph" {
+		windScale, windSym = 1.60934, "mph"
+	}
+
+	for i := range readings {
+		if readings[i].Weight <= 0 {
+			readings[i].Weight = 1
 		}
-		count++
-		sumTemp += r.Temp
-		sumHumidity += r.Humidity
-		sumWind += r.WindSpeed
-		sumPressure += r.Pressure
-		if r.Temp < minTemp {
-			minTemp = r.Temp
+		if readings[i].Available {
+			cons.AvailCount++
 		}
-		if r.Temp > maxTemp {
-			maxTemp = r.Temp
+	}
+	flagOutliers(readings, []outlierCheck{
+		{"temp", tempSym, 3 / tempScale, func(r ModelReading) float64 { return r.Temp }},
+		{"humidity", "%", 20, func(r ModelReading) float64 { return float64(r.Humidity) }},
+		{"wind", " " + windSym, 15 / windScale, func(r ModelReading) float64 { return r.WindSpeed }},
+		{"pressure", " hPa", 5, func(r ModelReading) float64 { return r.Pressure }},
+	})
+
+	var temps, hums, winds, pressures, weights []float64
+	var codes []int
+	for i, r := range readings {
+		if !r.Used() {
+			continue
 		}
+		if r.Discounted() {
+			readings[i].Reason = fmt.Sprintf("weight %.2g×", r.Weight)
+		}
+		temps = append(temps, r.Temp)
+		hums = append(hums, float64(r.Humidity))
+		winds = append(winds, r.WindSpeed)
+		pressures = append(pressures, r.Pressure)
+		weights = append(weights, r.Weight)
+		codes = append(codes, r.WeatherCode)
 	}
 
-	if count == 0 {
+	if len(temps) == 0 {
 		// No models responded; all stats stay zero
 		return cons
 	}
 
-	cons.AvailCount = count
-	cons.MinTemp = math.Round(minTemp*10) / 10
-	cons.MaxTemp = math.Round(maxTemp*10) / 10
-	cons.AvgTemp = math.Round(sumTemp/float64(count)*10) / 10
-	cons.AvgHumidity = sumHumidity / count
-	cons.AvgWind = math.Round(sumWind/float64(count)*10) / 10
-	cons.AvgPressure = math.Round(sumPressure/float64(count)*10) / 10
-	cons.Spread = math.Round((cons.MaxTemp-cons.MinTemp)*10) / 10
+	cons.UsedCount = len(temps)
+	cons.Temp = summarize(temps, weights, 12*tempScale, method) // −12% per °C
+	cons.Humidity = summarize(hums, weights, 2.5, method)       // −2.5% per % RH
+	cons.Wind = summarize(winds, weights, 4*windScale, method)  // −4% per km/h
+	cons.Pressure = summarize(pressures, weights, 10, method)   // −10% per hPa
+	cons.Weather = voteCode(codes, weights)
+
+	cons.AgreePct = 100
+	for _, pct := range []int{
+		cons.Temp.AgreePct, cons.Humidity.AgreePct, cons.Wind.AgreePct,
+		cons.Pressure.AgreePct,
+	} {
+		if pct < cons.AgreePct {
+			cons.AgreePct = pct
+		}
+	}
+	cons.Agreement = agreementLabel(cons.AgreePct)
+
+	return cons
+}
+
+// outlierCheck describes one variable screened by flagOutliers. floor is the
+// smallest deviation from the median, in request units, that can count as an
+// outlier; it keeps near-identical models from being flagged when MAD is tiny.
+type outlierCheck struct {
+	name  string
+	unit  string
+	floor float64
+	get   func(ModelReading) float64
+}
+
+// flagOutliers marks readings whose value strays from the median by more than
+// 3.5 scaled median absolute deviations (and the check's floor) on any
+// variable. At least three models are needed for a median to mean anything.
+func flagOutliers(readings []ModelReading, checks []outlierCheck) {
+	var avail []int
+	for i, r := range readings {
+		if r.Available {
+			avail = append(avail, i)
+		}
+	}
+	if len(avail) < 3 {
+		return
+	}
+
+	for _, c := range checks {
+		vals := make([]float64, len(avail))
+		for j, i := range avail {
+			vals[j] = c.get(readings[i])
+		}
+		med := median(vals)
+		devs := make([]float64, len(vals))
+		for j, v := range vals {
+			devs[j] = math.Abs(v - med)
+		}
+		limit := math.Max(3.5*1.4826*median(devs), c.floor)
+
+		for j, i := range avail {
+			if readings[i].Excluded || devs[j] <= limit {
+				continue
+			}
+			readings[i].Excluded = true
+			readings[i].Reason = fmt.Sprintf("outlier: %s %+.1f%s from median", c.name, vals[j]-med, c.unit)
+		}
+	}
+}
+
+// summarize computes VarStats for vals with matching weights. penalty is the
+// agreement percentage lost per unit of spread.
+func summarize(vals, weights []float64, penalty float64, method Aggregation) VarStats {
+	type pair struct{ v, w float64 }
+	pairs := make([]pair, len(vals))
+	for i := range vals {
+		pairs[i] = pair{vals[i], weights[i]}
+	}
+	sort.Slice(pairs, func(i, j int) bool { return pairs[i].v < pairs[j].v })
+	n := len(pairs)
+
+	wmean := func(ps []pair) float64 {
+		var sum, wsum float64
+		for _, p := range ps {
+			sum += p.v * p.w
+			wsum += p.w
+		}
+		return sum / wsum
+	}
+
+	mean := wmean(pairs)
+	var sq, wsum float64
+	for _, p := range pairs {
+		sq += p.w * (p.v - mean) * (p.v - mean)
+		wsum += p.w
+	}
+
+	trimmed := mean
+	if n >= 4 {
+		trimmed = wmean(pairs[1 : n-1])
+	}
+
+	s := VarStats{
+		Mean:    round1(mean),
+		Median:  round1(median(vals)),
+		Trimmed: round1(trimmed),
+		StdDev:  round1(math.Sqrt(sq / wsum)),
+		Min:     round1(pairs[0].v),
+		Max:     round1(pairs[n-1].v),
+	}
+	s.Spread = round1(s.Max - s.Min)
+	switch method {
+	case AggMedian:
+		s.Value = s.Median
+	case AggTrimmed:
+		s.Value = s.Trimmed
+	default:
+		s.Value = s.Mean
+	}
 
-	// Agreement: 100% at 0°C spread, −12% per degree of spread
-	pct := 100 - int(cons.Spread*12)
+	pct := 100 - int(s.Spread*penalty)
 	if pct < 0 {
 		pct = 0
 	}
-	cons.AgreePct = pct
+	s.AgreePct = pct
+	s.Agreement = agreementLabel(pct)
+	return s
+}
+
+func median(vals []float64) float64 {
+	sorted := append([]float64(nil), vals...)
+	sort.Float64s(sorted)
+	n := len(sorted)
+	if n%2 == 0 {
+		return (sorted[n/2-1] + sorted[n/2]) / 2
+	}
+	return sorted[n/2]
+}
+
+// voteCode picks the weather code with the most (weighted) votes. Ties go
+// to the more severe (higher) code so a single thunderstorm forecast is not
+// outvoted by drizzle.
+func voteCode(codes []int, weights []float64) CodeVote {
+	counts := make(map[int]int)
+	tally := make(map[int]float64)
+	var total float64
+	for i, c := range codes {
+		counts[c]++
+		tally[c] += weights[i]
+		total += weights[i]
+	}
+	v := CodeVote{Code: -1}
+	best := 0.0
+	for code, w := range tally {
+		if w > best || (w == best && code > v.Code) {
+			v.Code, best = code, w
+		}
+	}
+	v.Votes = counts[v.Code]
+	v.Description = WMODescription(v.Code)
+	v.Icon = WMOIconClass(v.Code)
+	v.AgreePct = int(best * 100 / total)
+	v.Agreement = agreementLabel(v.AgreePct)
+	return v
+}
+
+// ApplyBands attaches a model spread band and confidence label to every
+// hourly point and forecast day in info. tempUnit is "celsius"/"fahrenheit".
+func (cons *ConsensusInfo) ApplyBands(info *WeatherInfo, tempUnit string) {
+	if cons == nil {
+		return
+	}
+	penalty := 12.0 // −12% per °C, as for current temperature
+	if tempUnit == "fahrenheit" {
+		penalty *= 5.0 / 9.0
+	}
+
+	band := func(key string, series func(ModelReading) map[string]float64) Band {
+		var vals []float64
+		for _, r := range cons.Models {
+			if !r.Used() {
+				continue
+			}
+			if v, ok := series(r)[key]; ok {
+				vals = append(vals, v)
+			}
+		}
+		return newBand(vals, penalty)
+	}
+
+	for i := range info.Hourly {
+		h := &info.Hourly[i]
+		h.TempBand = band(h.DateTime, func(r ModelReading) map[string]float64 { return r.HourlyTemp })
+	}
+	for i := range info.Forecast {
+		d := &info.Forecast[i]
+		d.TempMaxBand = band(d.Date, func(r ModelReading) map[string]float64 { return r.DailyMax })
+		d.TempMinBand = band(d.Date, func(r ModelReading) map[string]float64 { return r.DailyMin })
+		d.Confidence = d.TempMaxBand.Confidence
+		if d.TempMinBand.Spread > d.TempMaxBand.Spread {
+			d.Confidence = d.TempMinBand.Confidence
+		}
+	}
+}
+
+// newBand builds a Band from model values. A band needs at least two models;
+// with fewer there is nothing to compare and the zero Band is returned.
+func newBand(vals []float64, penalty float64) Band {
+	if len(vals) < 2 {
+		return Band{}
+	}
+	b := Band{Min: vals[0], Max: vals[0], Models: len(vals)}
+	for _, v := range vals[1:] {
+		b.Min = math.Min(b.Min, v)
+		b.Max = math.Max(b.Max, v)
+	}
+	b.Min, b.Max = round1(b.Min), round1(b.Max)
+	b.Spread = round1(b.Max - b.Min)
+
+	pct := 100 - int(b.Spread*penalty)
+	if pct < 0 {
+		pct = 0
+	}
+	b.Confidence = agreementLabel(pct)
+	return b
+}
+
+// HalfSpread returns the ± range around the band centre.
+func (b Band) HalfSpread() float64 { return b.Spread / 2 }
+
+func agreementLabel(pct int) string {
 	switch {
 	case pct >= 80:
-		cons.Agreement = "High"
+		return "High"
 	case pct >= 50:
-		cons.Agreement = "Moderate"
+		return "Moderate"
 	default:
-		cons.Agreement = "Low"
+		return "Low"
 	}
-
-	return cons
 }
+
+func round1(v float64) float64 { return math.Round(v*10) / 10 }
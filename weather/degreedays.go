@@ -0,0 +1,250 @@
+package weather
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Degree-day defaults: the UK's 15.5 °C heating base and a 22 °C cooling
+// base, or the US convention of 65 °F for both.
+const (
+	DefaultHeatBaseC = 15.5
+	DefaultCoolBaseC = 22.0
+	DefaultDDBaseF   = 65.0
+	maxDDDays        = 16
+)
+
+// DegreeDayOptions configures a degree-day report. Bases are in the
+// report's units; nil picks the defaults. Past and Days count the days
+// before today and from today on.
+type DegreeDayOptions struct {
+	HeatBase *float64
+	CoolBase *float64
+	Past     int // 1–92, default 7
+	Days     int // 1–16, default 7
+}
+
+// DegreeDay is one day's heating and cooling degree days, with the same
+// calendar day a year earlier.
+type DegreeDay struct {
+	Date     string  `json:"date"` // "2006-01-02"
+	TMax     float64 `json:"tmax"`
+	TMin     float64 `json:"tmin"`
+	HDD      float64 `json:"hdd"`
+	CDD      float64 `json:"cdd"`
+	Forecast bool    `json:"forecast"` // today or later
+
+	LastYearDate string   `json:"last_year_date"`
+	LastYearHDD  *float64 `json:"last_year_hdd"` // nil when the archive has no data
+	LastYearCDD  *float64 `json:"last_year_cdd"`
+}
+
+// DegreeDayTotals sums a run of days, this year and last.
+type DegreeDayTotals struct {
+	Days        int     `json:"days"`
+	HDD         float64 `json:"hdd"`
+	CDD         float64 `json:"cdd"`
+	LastYearHDD float64 `json:"last_year_hdd"` // over the days last year has data for
+	LastYearCDD float64 `json:"last_year_cdd"`
+	LastYearN   int     `json:"last_year_days"`
+}
+
+// HDDChange is the change in heating degree days from last year, in %.
+// ok is false when last year is missing days or had none.
+func (t DegreeDayTotals) HDDChange() (pct float64, ok bool) {
+	return t.change(t.HDD, t.LastYearHDD)
+}
+
+// CDDChange is HDDChange for cooling degree days.
+func (t DegreeDayTotals) CDDChange() (pct float64, ok bool) {
+	return t.change(t.CDD, t.LastYearCDD)
+}
+
+func (t DegreeDayTotals) change(this, last float64) (float64, bool) {
+	if last == 0 || t.LastYearN < t.Days {
+		return 0, false
+	}
+	return (this - last) / last * 100, true
+}
+
+// DegreeDayReport holds daily heating and cooling degree days for the
+// recent past and the forecast, against the same dates last year.
+type DegreeDayReport struct {
+	City     string          `json:"city"`
+	Country  string          `json:"country"`
+	Units    string          `json:"units"`     // "metric" or "imperial"
+	TempUnit string          `json:"temp_unit"` // "°C" or "°F"
+	HeatBase float64         `json:"heat_base"`
+	CoolBase float64         `json:"cool_base"`
+	Today    string          `json:"today"`
+	Days     []DegreeDay     `json:"days"`
+	Past     DegreeDayTotals `json:"past"`
+	Ahead    DegreeDayTotals `json:"ahead"` // today and the forecast
+}
+
+// degreeDays uses the mean-temperature method: HDD is how far the day's
+// mean falls below heat, CDD how far it rises above cool, to 0.1.
+func degreeDays(tmax, tmin, heat, cool float64) (hdd, cdd float64) {
+	mean := (tmax + tmin) / 2
+	return round1(math.Max(0, heat-mean)), round1(math.Max(0, mean-cool))
+}
+
+// DegreeDays fetches the report for city.
+func (c *Client) DegreeDays(city, units string, opts DegreeDayOptions) (*DegreeDayReport, error) {
+	if opts.Past == 0 {
+		opts.Past = 7
+	}
+	if opts.Days == 0 {
+		opts.Days = 7
+	}
+	if opts.Past < 1 || opts.Past > maxPastDays {
+		return nil, fmt.Errorf("degree days: past days must be 1-%d", maxPastDays)
+	}
+	if opts.Days < 1 || opts.Days > maxDDDays {
+		return nil, fmt.Errorf("degree days: forecast days must be 1-%d", maxDDDays)
+	}
+	tempUnit, heat, cool := "celsius", DefaultHeatBaseC, DefaultCoolBaseC
+	if units != "imperial" {
+		units = "metric"
+	} else {
+		tempUnit, heat, cool = "fahrenheit", DefaultDDBaseF, DefaultDDBaseF
+	}
+	if opts.HeatBase != nil {
+		heat = *opts.HeatBase
+	}
+	if opts.CoolBase != nil {
+		cool = *opts.CoolBase
+	}
+
+	loc, err := c.Geocode(city)
+	if err != nil {
+		return nil, err
+	}
+	tz, err := time.LoadLocation(loc.Timezone)
+	if err != nil {
+		tz = time.UTC
+	}
+	now := time.Now().In(tz)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, tz)
+
+	type daily struct {
+		Daily struct {
+			Time []string   `json:"time"`
+			TMax []*float64 `json:"temperature_2m_max"`
+			TMin []*float64 `json:"temperature_2m_min"`
+		} `json:"daily"`
+	}
+	var (
+		recent, lastYear daily
+		lastErr          error
+		wg               sync.WaitGroup
+	)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		from := today.AddDate(-1, 0, -opts.Past)
+		to := today.AddDate(-1, 0, opts.Days-1)
+		u := fmt.Sprintf("%s?latitude=%.4f&longitude=%.4f&start_date=%s&end_date=%s"+
+			"&daily=temperature_2m_max,temperature_2m_min&temperature_unit=%s&timezone=%s",
+			archiveURL, loc.Latitude, loc.Longitude, from.Format("2006-01-02"), to.Format("2006-01-02"),
+			tempUnit, url.QueryEscape(loc.Timezone))
+		lastErr = c.getJSON(u, &lastYear)
+	}()
+	u := fmt.Sprintf("%s?latitude=%.4f&longitude=%.4f&daily=temperature_2m_max,temperature_2m_min"+
+		"&past_days=%d&forecast_days=%d&temperature_unit=%s&timezone=%s",
+		forecastURL, loc.Latitude, loc.Longitude, opts.Past, opts.Days, tempUnit, url.QueryEscape(loc.Timezone))
+	err = c.getJSON(u, &recent)
+	wg.Wait()
+	if err != nil {
+		return nil, fmt.Errorf("degree days: %w", err)
+	}
+	if lastErr != nil {
+		return nil, fmt.Errorf("degree days last year: %w", lastErr)
+	}
+
+	r := &DegreeDayReport{
+		City: loc.Name, Country: loc.Country,
+		Units: units, TempUnit: TempUnitSymbol(units),
+		HeatBase: heat, CoolBase: cool,
+		Today: today.Format("2006-01-02"),
+	}
+	r.build(recent.Daily.Time,
+		seriesMap(recent.Daily.Time, recent.Daily.TMax), seriesMap(recent.Daily.Time, recent.Daily.TMin),
+		seriesMap(lastYear.Daily.Time, lastYear.Daily.TMax), seriesMap(lastYear.Daily.Time, lastYear.Daily.TMin))
+	return r, nil
+}
+
+// build computes each date's degree days and the last-year comparison,
+// and sums them before and from today.
+func (r *DegreeDayReport) build(dates []string, tmax, tmin, lyMax, lyMin map[string]float64) {
+	for _, ds := range dates {
+		hi, ok1 := tmax[ds]
+		lo, ok2 := tmin[ds]
+		d, err := time.Parse("2006-01-02", ds)
+		if !ok1 || !ok2 || err != nil {
+			continue
+		}
+		dd := DegreeDay{
+			Date: ds, TMax: hi, TMin: lo,
+			Forecast:     ds >= r.Today,
+			LastYearDate: d.AddDate(-1, 0, 0).Format("2006-01-02"),
+		}
+		dd.HDD, dd.CDD = degreeDays(hi, lo, r.HeatBase, r.CoolBase)
+		lyHi, ok1 := lyMax[dd.LastYearDate]
+		lyLo, ok2 := lyMin[dd.LastYearDate]
+		if ok1 && ok2 {
+			h, c := degreeDays(lyHi, lyLo, r.HeatBase, r.CoolBase)
+			dd.LastYearHDD, dd.LastYearCDD = &h, &c
+		}
+		r.Days = append(r.Days, dd)
+		t := &r.Past
+		if dd.Forecast {
+			t = &r.Ahead
+		}
+		t.Days++
+		t.HDD += dd.HDD
+		t.CDD += dd.CDD
+		if dd.LastYearHDD != nil {
+			t.LastYearN++
+			t.LastYearHDD += *dd.LastYearHDD
+			t.LastYearCDD += *dd.LastYearCDD
+		}
+	}
+	for _, t := range []*DegreeDayTotals{&r.Past, &r.Ahead} {
+		t.HDD, t.CDD = round1(t.HDD), round1(t.CDD)
+		t.LastYearHDD, t.LastYearCDD = round1(t.LastYearHDD), round1(t.LastYearCDD)
+	}
+}
+
+// WriteCSV writes one row per day: date, highs and lows, HDD and CDD, and
+// last year's date and degree days (blank when unknown).
+func (r *DegreeDayReport) WriteCSV(w io.Writer) error {
+	num := func(v float64) string { return strconv.FormatFloat(v, 'f', 1, 64) }
+	unit := "c"
+	if r.Units == "imperial" {
+		unit = "f"
+	}
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"date", "kind", "tmax_" + unit, "tmin_" + unit,
+		fmt.Sprintf("hdd_base_%g", r.HeatBase), fmt.Sprintf("cdd_base_%g", r.CoolBase),
+		"last_year_date", "last_year_hdd", "last_year_cdd"})
+	for _, d := range r.Days {
+		kind := "observed"
+		if d.Forecast {
+			kind = "forecast"
+		}
+		lyH, lyC := "", ""
+		if d.LastYearHDD != nil {
+			lyH, lyC = num(*d.LastYearHDD), num(*d.LastYearCDD)
+		}
+		cw.Write([]string{d.Date, kind, num(d.TMax), num(d.TMin), num(d.HDD), num(d.CDD), d.LastYearDate, lyH, lyC})
+	}
+	cw.Flush()
+	return cw.Error()
+}
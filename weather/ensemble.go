@@ -0,0 +1,365 @@
+package weather
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+const ensembleURL = "https://ensemble-api.open-meteo.com/v1/ensemble"
+
+// KnownEnsembleModels maps a short key to the Open-Meteo ensemble models that
+// can feed EnsembleInfo. Keys are what users pass to ParseEnsembleModels.
+var KnownEnsembleModels = map[string]ForecastModel{
+	"ecmwf": {"ECMWF ENS", "ecmwf_ifs025", 1},
+	"gefs":  {"GEFS", "gfs025", 1},
+	"icon":  {"ICON-EPS", "icon_seamless", 1},
+	"gem":   {"GEM-EPS", "gem_global", 1},
+}
+
+// DefaultEnsembleModels is the ensemble set used when a Client has none configured.
+var DefaultEnsembleModels = []ForecastModel{
+	KnownEnsembleModels["ecmwf"],
+	KnownEnsembleModels["gefs"],
+}
+
+// ParseEnsembleModels is ParseModels for the ensemble catalog.
+func ParseEnsembleModels(spec string) ([]ForecastModel, error) {
+	return parseModelList(spec, KnownEnsembleModels)
+}
+
+// Percentiles summarises an ensemble distribution.
+type Percentiles struct {
+	P10 float64
+	P50 float64
+	P90 float64
+}
+
+// EnsembleHour is the ensemble distribution for one hour.
+type EnsembleHour struct {
+	Time     string // "HH:MM"
+	DateTime string // "2006-01-02T15:04", local time
+	Temp     Percentiles
+	Precip   Percentiles // mm in the preceding hour
+}
+
+// EnsembleDay is the ensemble distribution for one calendar day.
+type EnsembleDay struct {
+	Date      string
+	TempMax   Percentiles
+	TempMin   Percentiles
+	PrecipSum Percentiles // mm
+
+	FrostPct int // members with a minimum below freezing
+	RainPct  int // members with more than 1 mm
+}
+
+// Probability is a headline exceedance probability such as
+// "P(temp < 0 °C) tonight = 35%".
+type Probability struct {
+	Label string // "temp < 0 °C"
+	When  string // "tonight", "tomorrow", ...
+	Pct   int    // 0-100
+}
+
+// EnsembleInfo holds probabilistic forecasts from one or more ensembles.
+type EnsembleInfo struct {
+	Models  []string
+	Members int
+
+	Hourly        []EnsembleHour // next 24 hours
+	Daily         []EnsembleDay
+	Probabilities []Probability
+
+	// FrostTonightPct and HeavyRainTomorrowPct back the probability alerts.
+	FrostTonightPct      int
+	HeavyRainTomorrowPct int
+}
+
+// ensembleClient allows a little longer than consensusClient because
+// ensemble responses carry every member and are much larger.
+var ensembleClient = &Client{HTTP: &http.Client{Timeout: 10 * time.Second}}
+
+// member is one ensemble member's hourly series, aligned with the time axis.
+type member struct {
+	temp   []float64 // NaN where the member has no value
+	precip []float64
+}
+
+// FetchEnsemble fetches the client's ensemble models and derives percentiles
+// and exceedance probabilities. currentTime is the local "2006-01-02T15:04"
+// that "tonight" and "tomorrow" are relative to.
+func (c *Client) FetchEnsemble(lat, lon float64, timezone, tempUnit, currentTime string) (*EnsembleInfo, error) {
+	models := c.EnsembleModels
+	if len(models) == 0 {
+		models = DefaultEnsembleModels
+	}
+	params := make([]string, len(models))
+	names := make([]string, len(models))
+	for i, m := range models {
+		params[i] = m.Param
+		names[i] = m.Name
+	}
+
+	u := fmt.Sprintf(
+		"%s?latitude=%.4f&longitude=%.4f&hourly=temperature_2m,precipitation"+
+			"&temperature_unit=%s&timezone=%s&models=%s&forecast_days=5",
+		ensembleURL, lat, lon, tempUnit,
+		url.QueryEscape(timezone), url.QueryEscape(strings.Join(params, ",")),
+	)
+
+	var raw struct {
+		Hourly map[string]json.RawMessage `json:"hourly"`
+	}
+	if err := ensembleClient.getJSON(u, &raw); err != nil {
+		return nil, fmt.Errorf("ensemble: %w", err)
+	}
+
+	var times []string
+	if err := json.Unmarshal(raw.Hourly["time"], &times); err != nil || len(times) == 0 {
+		return nil, fmt.Errorf("ensemble: missing time axis")
+	}
+
+	// Every key other than "time" is one member's series, named e.g.
+	// "temperature_2m_member07_ecmwf_ifs025". Pair temperature and
+	// precipitation by their shared suffix.
+	byID := make(map[string]*member)
+	for key, msg := range raw.Hourly {
+		var variable string
+		switch {
+		case strings.HasPrefix(key, "temperature_2m"):
+			variable = "temperature_2m"
+		case strings.HasPrefix(key, "precipitation"):
+			variable = "precipitation"
+		default:
+			continue
+		}
+		var vals []*float64
+		if err := json.Unmarshal(msg, &vals); err != nil {
+			continue
+		}
+		id := strings.TrimPrefix(key, variable)
+		m := byID[id]
+		if m == nil {
+			m = &member{}
+			byID[id] = m
+		}
+		series := make([]float64, len(times))
+		for i := range series {
+			series[i] = math.NaN()
+			if i < len(vals) && vals[i] != nil {
+				series[i] = *vals[i]
+			}
+		}
+		if variable == "temperature_2m" {
+			m.temp = series
+		} else {
+			m.precip = series
+		}
+	}
+
+	var members []member
+	for _, m := range byID {
+		if m.temp != nil && m.precip != nil {
+			members = append(members, *m)
+		}
+	}
+	if len(members) == 0 {
+		return nil, fmt.Errorf("ensemble: no members returned")
+	}
+
+	return buildEnsemble(names, times, members, tempUnit, currentTime, timezone), nil
+}
+
+// buildEnsemble reduces member series to hourly/daily percentiles and the
+// headline probabilities.
+func buildEnsemble(models, times []string, members []member, tempUnit, currentTime, timezone string) *EnsembleInfo {
+	const layout = "2006-01-02T15:04"
+	tz, err := time.LoadLocation(timezone)
+	if err != nil {
+		tz = time.UTC
+	}
+	now, err := time.ParseInLocation(layout, currentTime, tz)
+	if err != nil {
+		now = time.Now().In(tz)
+	}
+
+	freezing, unit := 0.0, "°C"
+	if tempUnit == "fahrenheit" {
+		freezing, unit = 32, "°F"
+	}
+
+	ens := &EnsembleInfo{Models: models, Members: len(members)}
+
+	// Hourly percentiles for the next 24 hours.
+	parsed := make([]time.Time, len(times))
+	for i, ts := range times {
+		parsed[i], _ = time.ParseInLocation(layout, ts, tz)
+	}
+	for i, t := range parsed {
+		if t.Before(now.Truncate(time.Hour)) || len(ens.Hourly) >= 24 {
+			continue
+		}
+		ens.Hourly = append(ens.Hourly, EnsembleHour{
+			Time:     t.Format("15:04"),
+			DateTime: times[i],
+			Temp:     percentiles(column(members, i, func(m member) []float64 { return m.temp })),
+			Precip:   percentiles(column(members, i, func(m member) []float64 { return m.precip })),
+		})
+	}
+
+	// Daily aggregates per member, then percentiles across members.
+	type agg struct {
+		max, min, sum float64
+		ok            bool
+	}
+	var dates []string
+	perDay := make(map[string][]agg)
+	for i, t := range parsed {
+		date := t.Format("2006-01-02")
+		if _, seen := perDay[date]; !seen {
+			dates = append(dates, date)
+			perDay[date] = make([]agg, len(members))
+		}
+		for j, m := range members {
+			a := &perDay[date][j]
+			if math.IsNaN(m.temp[i]) {
+				continue
+			}
+			if !a.ok {
+				a.max, a.min, a.ok = m.temp[i], m.temp[i], true
+			}
+			a.max = math.Max(a.max, m.temp[i])
+			a.min = math.Min(a.min, m.temp[i])
+			if !math.IsNaN(m.precip[i]) {
+				a.sum += m.precip[i]
+			}
+		}
+	}
+	for _, date := range dates {
+		var maxs, mins, sums []float64
+		for _, a := range perDay[date] {
+			if a.ok {
+				maxs = append(maxs, a.max)
+				mins = append(mins, a.min)
+				sums = append(sums, a.sum)
+			}
+		}
+		if len(maxs) == 0 {
+			continue
+		}
+		ens.Daily = append(ens.Daily, EnsembleDay{
+			Date:      date,
+			TempMax:   percentiles(maxs),
+			TempMin:   percentiles(mins),
+			PrecipSum: percentiles(sums),
+			FrostPct:  fraction(mins, func(v float64) bool { return v < freezing }),
+			RainPct:   fraction(sums, func(v float64) bool { return v > 1 }),
+		})
+	}
+
+	// Headline probabilities. "Tonight" runs from 18:00 (or now, if later)
+	// to 06:00 the next morning; "tomorrow" is the next calendar day.
+	tonightStart := time.Date(now.Year(), now.Month(), now.Day(), 18, 0, 0, 0, tz)
+	if now.Hour() < 6 {
+		tonightStart = now.Truncate(time.Hour)
+	} else if now.After(tonightStart) {
+		tonightStart = now.Truncate(time.Hour)
+	}
+	tonightEnd := time.Date(tonightStart.Year(), tonightStart.Month(), tonightStart.Day(), 6, 0, 0, 0, tz)
+	if !tonightEnd.After(tonightStart) {
+		tonightEnd = tonightEnd.AddDate(0, 0, 1)
+	}
+	tomorrow := now.AddDate(0, 0, 1).Format("2006-01-02")
+
+	var tonightMins []float64
+	for _, m := range members {
+		lo := math.Inf(1)
+		for i, t := range parsed {
+			if !t.Before(tonightStart) && t.Before(tonightEnd) && !math.IsNaN(m.temp[i]) {
+				lo = math.Min(lo, m.temp[i])
+			}
+		}
+		if !math.IsInf(lo, 1) {
+			tonightMins = append(tonightMins, lo)
+		}
+	}
+	if len(tonightMins) > 0 {
+		ens.FrostTonightPct = fraction(tonightMins, func(v float64) bool { return v < freezing })
+		ens.Probabilities = append(ens.Probabilities, Probability{
+			Label: fmt.Sprintf("temp < %.0f %s", freezing, unit), When: "tonight", Pct: ens.FrostTonightPct,
+		})
+	}
+
+	for _, d := range ens.Daily {
+		if d.Date != tomorrow {
+			continue
+		}
+		var sums []float64
+		for _, a := range perDay[d.Date] {
+			if a.ok {
+				sums = append(sums, a.sum)
+			}
+		}
+		ens.HeavyRainTomorrowPct = fraction(sums, func(v float64) bool { return v > 5 })
+		ens.Probabilities = append(ens.Probabilities,
+			Probability{Label: "rain > 1 mm", When: "tomorrow", Pct: d.RainPct},
+			Probability{Label: "rain > 5 mm", When: "tomorrow", Pct: ens.HeavyRainTomorrowPct},
+			Probability{Label: fmt.Sprintf("temp < %.0f %s", freezing, unit), When: "tomorrow", Pct: d.FrostPct},
+		)
+	}
+
+	return ens
+}
+
+// column returns the non-NaN values of series(m)[i] across members.
+func column(members []member, i int, series func(member) []float64) []float64 {
+	var vals []float64
+	for _, m := range members {
+		if v := series(m)[i]; !math.IsNaN(v) {
+			vals = append(vals, v)
+		}
+	}
+	return vals
+}
+
+// percentiles computes P10/P50/P90 with linear interpolation between ranks.
+func percentiles(vals []float64) Percentiles {
+	if len(vals) == 0 {
+		return Percentiles{}
+	}
+	sorted := append([]float64(nil), vals...)
+	sort.Float64s(sorted)
+	return Percentiles{
+		P10: round1(quantile(sorted, 0.1)),
+		P50: round1(quantile(sorted, 0.5)),
+		P90: round1(quantile(sorted, 0.9)),
+	}
+}
+
+// quantile expects sorted input.
+func quantile(sorted []float64, q float64) float64 {
+	pos := q * float64(len(sorted)-1)
+	lo := int(math.Floor(pos))
+	hi := int(math.Ceil(pos))
+	return sorted[lo] + (sorted[hi]-sorted[lo])*(pos-float64(lo))
+}
+
+// fraction returns the percentage of vals for which pred holds.
+func fraction(vals []float64, pred func(float64) bool) int {
+	if len(vals) == 0 {
+		return 0
+	}
+	n := 0
+	for _, v := range vals {
+		if pred(v) {
+			n++
+		}
+	}
+	return int(math.Round(float64(n) * 100 / float64(len(vals))))
+}
@@ -0,0 +1,534 @@
+package weather
+
+import (
+	"fmt"
+	"math"
+	"net/url"
+	"sort"
+	"sync"
+	"time"
+)
+
+const archiveURL = "https://archive-api.open-meteo.com/v1/archive"
+
+const (
+	gddCapC        = 30.0 // modified growing degree days: Tmax capped at 30 °C
+	chillLowC      = 0.0  // chill hours: hours between 0 and 7.2 °C
+	chillHighC     = 7.2
+	frostC         = 0.0 // air frost
+	frostYears     = 10  // years of history behind the frost dates
+	maxPastDays    = 92  // the forecast API's past_days limit
+	balanceDays    = 7   // water balance: the last week and the next
+	dryDeepSoil    = 0.15
+	moistDeepSoil  = 0.30
+	defaultGDDBase = 10.0
+)
+
+// GardenOptions configures the accumulations. Zero dates pick the season:
+// GDD from 1 March (1 September south of the equator) and chill hours from
+// 1 October (1 April), whichever passed most recently.
+type GardenOptions struct {
+	Since      time.Time // growing degree days accumulate from this date
+	ChillSince time.Time // chill hours accumulate from this date
+	BaseC      *float64  // GDD base temperature; nil means 10 °C
+}
+
+// GardenDay is one day of the water balance.
+type GardenDay struct {
+	Date     string  // "2006-01-02"
+	TMax     float64 // °C
+	TMin     float64
+	GDD      float64
+	Precip   float64 // mm
+	ET0      float64 // FAO-56 reference evapotranspiration, mm
+	Balance  float64 // Precip - ET0
+	Frost    bool
+	Forecast bool // after today
+	Today    bool
+}
+
+// WaterAdvice answers "should I water today?".
+type WaterAdvice struct {
+	Water  bool
+	Amount float64 // mm (= litres per m²) to give when Water is set
+	Reason string
+}
+
+// FrostDates is the local frost climatology: when the last spring frost and
+// the first autumn frost fall, from daily minima over the past years. Dates
+// are month and day in a non-leap year; zero when most years have none.
+type FrostDates struct {
+	Years          int
+	LastEarly      time.Time // 1 year in 10 has its last frost before this
+	LastMedian     time.Time
+	LastLate       time.Time // 1 year in 10 still has a frost after this
+	FirstEarly     time.Time
+	FirstMedian    time.Time
+	FirstLate      time.Time
+	FrostFreeYears int
+	Spring         bool // today falls in the last-frost half of the year
+	Risk           int  // % of years with a frost still to come (Spring) or already past (autumn)
+}
+
+// RiskText describes Risk for today's half of the year.
+func (f *FrostDates) RiskText() string {
+	if f.Spring {
+		return fmt.Sprintf("%d%% of years had another frost after today", f.Risk)
+	}
+	return fmt.Sprintf("%d%% of years had their first frost by today", f.Risk)
+}
+
+// MonthDay formats a FrostDates date as "Apr 12", or "none" when zero.
+func MonthDay(t time.Time) string {
+	if t.IsZero() {
+		return "none"
+	}
+	return t.Format("Jan 2")
+}
+
+// GardenReport is the gardening outlook for one place. Values are metric;
+// Temp, Depth and DegreeDays convert them for display.
+type GardenReport struct {
+	City, Country string
+	Imperial      bool
+	Today         string
+	Since         time.Time
+	ChillSince    time.Time
+	BaseC         float64
+
+	GDD         float64 // since Since, through today
+	GDDNextWeek float64 // forecast for the next seven days
+	ChillHours  int     // since ChillSince, through the current hour
+
+	SoilTemp6   float64 // °C at 6 cm, current hour
+	SoilTemp18  float64 // °C at 18 cm
+	SoilMoist   float64 // m³/m³, 3–9 cm
+	SoilMoistDp float64 // m³/m³, 9–27 cm
+
+	Days        []GardenDay // the last week, today and the next week
+	Water       WaterAdvice
+	FrostNights []string    // forecast days with an air frost, "Mon 2"
+	Frost       *FrostDates // nil when the history is unavailable
+}
+
+// Temp converts °C for display.
+func (g *GardenReport) Temp(c float64) float64 {
+	if g.Imperial {
+		return c*9/5 + 32
+	}
+	return c
+}
+
+// TempUnit is "°C" or "°F".
+func (g *GardenReport) TempUnit() string {
+	if g.Imperial {
+		return "°F"
+	}
+	return "°C"
+}
+
+// DegreeDays converts °C-days for display.
+func (g *GardenReport) DegreeDays(v float64) float64 {
+	if g.Imperial {
+		return v * 9 / 5
+	}
+	return v
+}
+
+// Depth converts millimetres for display.
+func (g *GardenReport) Depth(mm float64) float64 {
+	if g.Imperial {
+		return mm / 25.4
+	}
+	return mm
+}
+
+// DepthUnit is "mm" or "in".
+func (g *GardenReport) DepthUnit() string {
+	if g.Imperial {
+		return "in"
+	}
+	return "mm"
+}
+
+// depth formats millimetres of water for reasons: "12 mm" or "0.5 in".
+func (g *GardenReport) depth(mm float64) string {
+	if g.Imperial {
+		return fmt.Sprintf("%.1f in", g.Depth(mm))
+	}
+	return fmt.Sprintf("%.0f mm", mm)
+}
+
+// seasonStart returns the most recent month/1 on or before today, using the
+// southern month when lat is south of the equator.
+func seasonStart(today time.Time, lat float64, north, south time.Month) time.Time {
+	m := north
+	if lat < 0 {
+		m = south
+	}
+	start := time.Date(today.Year(), m, 1, 0, 0, 0, 0, today.Location())
+	if start.After(today) {
+		start = start.AddDate(-1, 0, 0)
+	}
+	return start
+}
+
+// gddDay is the modified growing degree days for one day: Tmax capped at
+// 30 °C and Tmin raised to the base, so neither heat nor cold nights count
+// beyond what the plant can use.
+func gddDay(tmax, tmin, base float64) float64 {
+	hi := math.Max(math.Min(tmax, gddCapC), base)
+	lo := math.Max(math.Min(tmin, gddCapC), base)
+	return (hi+lo)/2 - base
+}
+
+type gardenSeries struct {
+	Hourly struct {
+		Time    []string  `json:"time"`
+		Temp    []float64 `json:"temperature_2m"`
+		SoilT6  []float64 `json:"soil_temperature_6cm"`
+		SoilT18 []float64 `json:"soil_temperature_18cm"`
+		SoilM3  []float64 `json:"soil_moisture_3_to_9cm"`
+		SoilM9  []float64 `json:"soil_moisture_9_to_27cm"`
+	} `json:"hourly"`
+	Daily struct {
+		Time   []string  `json:"time"`
+		TMax   []float64 `json:"temperature_2m_max"`
+		TMin   []float64 `json:"temperature_2m_min"`
+		Precip []float64 `json:"precipitation_sum"`
+		ET0    []float64 `json:"et0_fao_evapotranspiration"`
+	} `json:"daily"`
+	Current struct {
+		Time string `json:"time"`
+	} `json:"current"`
+}
+
+// Garden builds the gardening report for city. The frost history is
+// best-effort: without it Frost is nil.
+func (c *Client) Garden(city, units string, opts GardenOptions) (*GardenReport, error) {
+	loc, err := c.Geocode(city)
+	if err != nil {
+		return nil, err
+	}
+	tz, err := time.LoadLocation(loc.Timezone)
+	if err != nil {
+		tz = time.UTC
+	}
+	now := time.Now().In(tz)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, tz)
+
+	g := &GardenReport{
+		City: loc.Name, Country: loc.Country, Imperial: units == "imperial",
+		Today: today.Format("2006-01-02"),
+		Since: opts.Since, ChillSince: opts.ChillSince, BaseC: defaultGDDBase,
+	}
+	if g.Since.IsZero() {
+		g.Since = seasonStart(today, loc.Latitude, time.March, time.September)
+	}
+	if g.ChillSince.IsZero() {
+		g.ChillSince = seasonStart(today, loc.Latitude, time.October, time.April)
+	}
+	if opts.BaseC != nil {
+		g.BaseC = *opts.BaseC
+	}
+	for _, d := range []*time.Time{&g.Since, &g.ChillSince} {
+		*d = time.Date(d.Year(), d.Month(), d.Day(), 0, 0, 0, 0, tz)
+		if d.After(today) {
+			return nil, fmt.Errorf("garden: start date %s is in the future", d.Format("2006-01-02"))
+		}
+		if today.Sub(*d) > 366*24*time.Hour {
+			return nil, fmt.Errorf("garden: start date %s is more than a year ago", d.Format("2006-01-02"))
+		}
+	}
+	earliest := g.Since
+	if g.ChillSince.Before(earliest) {
+		earliest = g.ChillSince
+	}
+	pastDays := int(today.Sub(earliest).Hours()/24 + 0.5)
+	pastDays = max(balanceDays, min(maxPastDays, pastDays))
+
+	var (
+		recent, older gardenSeries
+		olderErr      error
+		wg            sync.WaitGroup
+	)
+	if archiveTo := today.AddDate(0, 0, -pastDays-1); !earliest.After(archiveTo) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			u := fmt.Sprintf("%s?latitude=%.4f&longitude=%.4f&start_date=%s&end_date=%s"+
+				"&hourly=temperature_2m&daily=temperature_2m_max,temperature_2m_min&timezone=%s",
+				archiveURL, loc.Latitude, loc.Longitude,
+				earliest.Format("2006-01-02"), archiveTo.Format("2006-01-02"), url.QueryEscape(loc.Timezone))
+			olderErr = c.getJSON(u, &older)
+		}()
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		g.Frost = c.frostDates(loc.Latitude, loc.Longitude, loc.Timezone, today)
+	}()
+	u := fmt.Sprintf("%s?latitude=%.4f&longitude=%.4f&current=temperature_2m"+
+		"&hourly=temperature_2m,soil_temperature_6cm,soil_temperature_18cm,soil_moisture_3_to_9cm,soil_moisture_9_to_27cm"+
+		"&daily=temperature_2m_max,temperature_2m_min,precipitation_sum,et0_fao_evapotranspiration"+
+		"&past_days=%d&forecast_days=%d&timezone=%s",
+		forecastURL, loc.Latitude, loc.Longitude, pastDays, balanceDays, url.QueryEscape(loc.Timezone))
+	err = c.getJSON(u, &recent)
+	wg.Wait()
+	if err != nil {
+		return nil, fmt.Errorf("garden: %w", err)
+	}
+	if olderErr != nil {
+		return nil, fmt.Errorf("garden history: %w", olderErr)
+	}
+	g.build(&older, &recent, today)
+	return g, nil
+}
+
+// build fills the report from the archive (older) and forecast (recent)
+// series; the two do not overlap.
+func (g *GardenReport) build(older, recent *gardenSeries, today time.Time) {
+	todayStr := g.Today
+	since, chillSince := g.Since.Format("2006-01-02"), g.ChillSince.Format("2006-01-02")
+
+	for _, s := range []*gardenSeries{older, recent} {
+		for i, d := range s.Daily.Time {
+			if d < since || d > todayStr {
+				continue
+			}
+			g.GDD += gddDay(safeFloat(s.Daily.TMax, i), safeFloat(s.Daily.TMin, i), g.BaseC)
+		}
+	}
+
+	currentHour := recent.Current.Time
+	if len(currentHour) >= 13 {
+		currentHour = currentHour[:13] + ":00"
+	}
+	for _, s := range []*gardenSeries{older, recent} {
+		for i, ts := range s.Hourly.Time {
+			if ts < chillSince || (currentHour != "" && ts > currentHour) {
+				continue
+			}
+			if t := safeFloat(s.Hourly.Temp, i); t > chillLowC && t <= chillHighC {
+				g.ChillHours++
+			}
+		}
+	}
+	for i, ts := range recent.Hourly.Time {
+		if ts == currentHour {
+			g.SoilTemp6 = safeFloat(recent.Hourly.SoilT6, i)
+			g.SoilTemp18 = safeFloat(recent.Hourly.SoilT18, i)
+			g.SoilMoist = safeFloat(recent.Hourly.SoilM3, i)
+			g.SoilMoistDp = safeFloat(recent.Hourly.SoilM9, i)
+			break
+		}
+	}
+
+	from := today.AddDate(0, 0, -balanceDays).Format("2006-01-02")
+	for i, d := range recent.Daily.Time {
+		if d < from {
+			continue
+		}
+		day := GardenDay{
+			Date:     d,
+			TMax:     safeFloat(recent.Daily.TMax, i),
+			TMin:     safeFloat(recent.Daily.TMin, i),
+			Precip:   safeFloat(recent.Daily.Precip, i),
+			ET0:      safeFloat(recent.Daily.ET0, i),
+			Forecast: d > todayStr,
+			Today:    d == todayStr,
+		}
+		day.GDD = gddDay(day.TMax, day.TMin, g.BaseC)
+		day.Balance = day.Precip - day.ET0
+		day.Frost = day.TMin <= frostC
+		if day.Forecast {
+			g.GDDNextWeek += day.GDD
+			if day.Frost {
+				t, _ := time.Parse("2006-01-02", d)
+				g.FrostNights = append(g.FrostNights, t.Format("Mon 2"))
+			}
+		}
+		g.Days = append(g.Days, day)
+	}
+	g.Water = g.waterAdvice()
+}
+
+// waterAdvice weighs the last week's rain against evapotranspiration, the
+// rain due today and tomorrow, and the deeper soil moisture.
+func (g *GardenReport) waterAdvice() WaterAdvice {
+	var pastBalance, rainSoon float64
+	for i, d := range g.Days {
+		switch {
+		case !d.Forecast && !d.Today:
+			pastBalance += d.Balance
+		case d.Today || (i > 0 && g.Days[i-1].Today):
+			rainSoon += d.Precip
+		}
+	}
+	deficit := -pastBalance
+	switch {
+	case g.SoilMoistDp >= moistDeepSoil:
+		return WaterAdvice{Reason: fmt.Sprintf("Soil is moist (%.0f%% water at 9–27 cm)", g.SoilMoistDp*100)}
+	case deficit < 1:
+		sign := "+"
+		if pastBalance < 0 {
+			sign = "-"
+		}
+		return WaterAdvice{Reason: fmt.Sprintf("Rain has kept up with evaporation this week (%s%s)", sign, g.depth(math.Abs(pastBalance)))}
+	case rainSoon >= deficit:
+		return WaterAdvice{Reason: fmt.Sprintf("%s of rain due by tomorrow covers this week's %s shortfall", g.depth(rainSoon), g.depth(deficit))}
+	case deficit-rainSoon < 5 && g.SoilMoistDp > dryDeepSoil:
+		return WaterAdvice{Reason: fmt.Sprintf("Only %s short and the soil is holding moisture", g.depth(deficit-rainSoon))}
+	}
+	amount := math.Round(deficit - rainSoon)
+	give := fmt.Sprintf("%.0f L per m²", amount)
+	if g.Imperial {
+		give = g.depth(amount) + " of water"
+	}
+	return WaterAdvice{Water: true, Amount: amount,
+		Reason: fmt.Sprintf("%s short over the last week after rain; give about %s", g.depth(deficit), give)}
+}
+
+// frostCache holds this year's frost climatologies by location, at most
+// frostCacheSize of them, oldest out first. They only change once a year,
+// so a new year empties it.
+var frostCache struct {
+	sync.Mutex
+	year  int
+	mins  map[string]map[string]float64
+	order []string // keys in mins, oldest first
+}
+
+const frostCacheSize = 200
+
+func frostCacheGet(key string, year int) (map[string]float64, bool) {
+	frostCache.Lock()
+	defer frostCache.Unlock()
+	if frostCache.year != year {
+		return nil, false
+	}
+	mins, ok := frostCache.mins[key]
+	return mins, ok
+}
+
+func frostCachePut(key string, year int, mins map[string]float64) {
+	frostCache.Lock()
+	defer frostCache.Unlock()
+	if frostCache.year != year || frostCache.mins == nil {
+		frostCache.year, frostCache.mins, frostCache.order = year, map[string]map[string]float64{}, nil
+	}
+	if _, ok := frostCache.mins[key]; !ok {
+		if len(frostCache.order) >= frostCacheSize {
+			delete(frostCache.mins, frostCache.order[0])
+			frostCache.order = frostCache.order[1:]
+		}
+		frostCache.order = append(frostCache.order, key)
+	}
+	frostCache.mins[key] = mins
+}
+
+// frostDates fetches frostYears of daily minima and summarises the last and
+// first frost dates. It returns nil if the archive is unavailable.
+func (c *Client) frostDates(lat, lon float64, timezone string, today time.Time) *FrostDates {
+	key := locationKey(lat, lon)
+	mins, ok := frostCacheGet(key, today.Year())
+	if !ok {
+		u := fmt.Sprintf("%s?latitude=%.4f&longitude=%.4f&start_date=%d-01-01&end_date=%d-12-31"+
+			"&daily=temperature_2m_min&timezone=%s",
+			archiveURL, lat, lon, today.Year()-frostYears, today.Year()-1, url.QueryEscape(timezone))
+		var raw struct {
+			Daily struct {
+				Time []string   `json:"time"`
+				TMin []*float64 `json:"temperature_2m_min"` // null where ERA5 has no data yet
+			} `json:"daily"`
+		}
+		if err := c.getJSON(u, &raw); err != nil {
+			return nil
+		}
+		mins = seriesMap(raw.Daily.Time, raw.Daily.TMin)
+		frostCachePut(key, today.Year(), mins)
+	}
+	return buildFrostDates(mins, lat, today)
+}
+
+// buildFrostDates finds each year's last spring and first autumn frost.
+// North of the equator spring is January–June; south of it, July–December.
+// A year with no spring frost counts as the earliest possible last frost,
+// and one with no autumn frost as the latest possible first frost.
+func buildFrostDates(mins map[string]float64, lat float64, today time.Time) *FrostDates {
+	const noYear = 2001 // month/day carrier, not a leap year
+	springStart := time.January
+	if lat < 0 {
+		springStart = time.July
+	}
+	md := func(t time.Time) time.Time { return time.Date(noYear, t.Month(), t.Day(), 0, 0, 0, 0, time.UTC) }
+
+	var lasts, firsts []time.Time // zero = no frost that half-year
+	f := &FrostDates{}
+	for y := today.Year() - frostYears; y < today.Year(); y++ {
+		var last, first time.Time
+		found := false
+		for d := time.Date(y, 1, 1, 0, 0, 0, 0, time.UTC); d.Year() == y; d = d.AddDate(0, 0, 1) {
+			tmin, ok := mins[d.Format("2006-01-02")]
+			if !ok {
+				continue
+			}
+			found = true
+			if tmin > frostC {
+				continue
+			}
+			inSpring := d.Month() >= springStart && d.Month() < springStart+6
+			if inSpring {
+				last = md(d)
+			} else if first.IsZero() {
+				first = md(d)
+			}
+		}
+		if !found {
+			continue
+		}
+		f.Years++
+		if last.IsZero() && first.IsZero() {
+			f.FrostFreeYears++
+		}
+		lasts = append(lasts, last)
+		firsts = append(firsts, first)
+	}
+	if f.Years == 0 {
+		return nil
+	}
+
+	// Percentiles over the years; a year without frost sorts first for last
+	// frosts (it was over before it began) and last for first frosts.
+	never := time.Date(noYear+1, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := range firsts {
+		if firsts[i].IsZero() {
+			firsts[i] = never
+		}
+	}
+	sort.Slice(lasts, func(i, j int) bool { return lasts[i].Before(lasts[j]) })
+	sort.Slice(firsts, func(i, j int) bool { return firsts[i].Before(firsts[j]) })
+	pick := func(ts []time.Time, p float64) time.Time {
+		t := ts[int(math.Round(p*float64(len(ts)-1)))]
+		if t.Equal(never) {
+			return time.Time{}
+		}
+		return t
+	}
+	f.LastEarly, f.LastMedian, f.LastLate = pick(lasts, 0.1), pick(lasts, 0.5), pick(lasts, 0.9)
+	f.FirstEarly, f.FirstMedian, f.FirstLate = pick(firsts, 0.1), pick(firsts, 0.5), pick(firsts, 0.9)
+
+	now := md(today)
+	f.Spring = now.Month() >= springStart && now.Month() < springStart+6
+	n := 0
+	for i := range lasts {
+		if f.Spring && lasts[i].After(now) {
+			n++
+		}
+		if !f.Spring && !firsts[i].After(now) {
+			n++
+		}
+	}
+	f.Risk = int(math.Round(100 * float64(n) / float64(f.Years)))
+	return f
+}
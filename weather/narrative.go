@@ -0,0 +1,289 @@
+package weather
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"WeatherApp/i18n"
+)
+
+// Narrative thresholds, in °C and km/h; converted for imperial units.
+const (
+	wetLikely     = 50 // precipitation probability, %
+	wetPossible   = 30
+	gustNotableKm = 50.0
+	nearFreezingC = 2.0
+	tempSwingC    = 5.0 // a change worth mentioning within the next day
+	dayChangeC    = 3.0 // tomorrow's high against today's
+)
+
+// Narrative turns the next 24 hours and tomorrow's forecast into a short
+// paragraph, e.g. "Dry until mid-afternoon, then showers likely from 15:00
+// with gusts to 50 km/h; turning colder tonight, near freezing by dawn."
+// The same forecast always gives the same text, in info.Lang.
+func Narrative(info *WeatherInfo) string {
+	if info == nil || len(info.Hourly) == 0 {
+		return ""
+	}
+	n := narrator{info: info, imperial: info.TempUnit == "°F", lang: info.Lang}
+	first := n.precip()
+	if s := n.temperature(); s != "" {
+		first += "; " + s
+	}
+	out := []string{first + "."}
+	if s := n.tomorrow(); s != "" {
+		out = append(out, s+".")
+	}
+	return strings.Join(out, " ")
+}
+
+type narrator struct {
+	info     *WeatherInfo
+	imperial bool
+	lang     string
+}
+
+func (n narrator) tr(s string) string { return i18n.T(n.lang, s) }
+
+func (n narrator) sprintf(format string, args ...any) string {
+	return i18n.Sprintf(n.lang, format, args...)
+}
+
+// temp converts a °C threshold or difference to the forecast's units.
+func (n narrator) temp(c float64, diff bool) float64 {
+	if !n.imperial {
+		return c
+	}
+	if diff {
+		return c * 9 / 5
+	}
+	return c*9/5 + 32
+}
+
+func (n narrator) deg(v float64) string {
+	return fmt.Sprintf("%.0f%s", v, n.info.TempUnit)
+}
+
+// precipKind names the precipitation an hour's condition describes.
+func precipKind(c Condition) string {
+	switch {
+	case c.Thunder:
+		return "thunderstorms"
+	case c.Precip == PrecipSnow:
+		return "snow"
+	case c.Precip == PrecipDrizzle:
+		return "drizzle"
+	case c.Showers:
+		return "showers"
+	}
+	return "rain"
+}
+
+// partOfDay names the time of day an hour falls in, e.g. "mid-afternoon".
+func (n narrator) partOfDay(h HourlyPoint, nextDay bool) string {
+	var hour int
+	fmt.Sscanf(h.Time, "%d", &hour)
+	var part string
+	switch {
+	case hour < 5:
+		return n.tr("the early hours")
+	case hour < 9:
+		part = "early morning"
+	case hour < 11:
+		part = "mid-morning"
+	case hour < 12:
+		part = "late morning"
+	case hour < 14:
+		part = "midday"
+	case hour < 16:
+		part = "mid-afternoon"
+	case hour < 18:
+		part = "late afternoon"
+	case hour < 21:
+		part = "evening"
+	default:
+		part = "late evening"
+	}
+	if nextDay {
+		return n.sprintf("tomorrow %s", n.tr(part))
+	}
+	return n.tr(part)
+}
+
+// sky describes a dry spell by its most common condition.
+func (n narrator) sky() string {
+	var clear, cloudy, fog int
+	for _, h := range n.info.Hourly {
+		switch h.Condition.Sky {
+		case SkyClear, SkyMainlyClear:
+			clear++
+		case SkyOvercast:
+			cloudy++
+		case SkyFog:
+			fog++
+		}
+	}
+	half := len(n.info.Hourly) / 2
+	switch {
+	case fog >= 3:
+		return n.tr("Dry, with fog at times")
+	case clear > half:
+		return n.tr("Dry and mostly clear")
+	case cloudy > half:
+		return n.tr("Dry but cloudy")
+	}
+	return n.tr("Dry with variable cloud")
+}
+
+// gust returns the strongest gust in hours [from, to) if it is worth a
+// mention, as " with gusts to 55 km/h".
+func (n narrator) gust(from, to int) string {
+	limit := gustNotableKm
+	if n.info.WindUnit == "mph" {
+		limit = math.Round(gustNotableKm / 1.609)
+	}
+	top := 0.0
+	for _, h := range n.info.Hourly[from:to] {
+		top = math.Max(top, h.WindGust)
+	}
+	if top < limit {
+		return ""
+	}
+	return n.sprintf(" with gusts to %.0f %s", top, n.info.WindUnit)
+}
+
+// precip describes the first wet spell in the next 24 hours, or the sky
+// when there is none.
+func (n narrator) precip() string {
+	hours := n.info.Hourly
+	threshold, chance := wetLikely, "likely"
+	start := -1
+	for _, t := range []int{wetLikely, wetPossible} {
+		for i, h := range hours {
+			if h.PrecipProb >= t {
+				start = i
+				break
+			}
+		}
+		if start >= 0 {
+			threshold = t
+			if t == wetPossible {
+				chance = "possible"
+			}
+			break
+		}
+	}
+	if start < 0 {
+		return n.sprintf("%s for the next 24 hours", n.sky()) + n.gust(0, len(hours))
+	}
+	end := start
+	for end < len(hours) && hours[end].PrecipProb >= threshold {
+		end++
+	}
+	kind := precipKind(hours[start].Condition)
+	for _, h := range hours[start:end] {
+		if k := precipKind(h.Condition); k == "thunderstorms" || k == "snow" {
+			kind = k
+			break
+		}
+	}
+	nextDay := func(i int) bool { return hours[i].DateTime[:10] != hours[0].DateTime[:10] }
+
+	gust := n.gust(start, end)
+	var s string
+	if start == 0 {
+		s = capitalise(n.sprintf("%s %s now", n.tr(kind), n.tr(chance)))
+	} else {
+		s = n.sprintf("Dry until %s, then %s %s from %s", n.partOfDay(hours[start], nextDay(start)), n.tr(kind), n.tr(chance), hours[start].Time)
+	}
+	s += gust
+	switch {
+	case end == len(hours):
+		if !nextDay(start) {
+			s += n.tr(", lasting into tomorrow")
+		}
+	case end-start <= 2:
+		s += n.tr(", soon passing")
+	default:
+		s += n.sprintf(", clearing by %s", hours[end].Time)
+	}
+	return s
+}
+
+// temperature describes the biggest swing in the next 24 hours, warning
+// of frost or near-freezing temperatures overnight.
+func (n narrator) temperature() string {
+	hours := n.info.Hourly
+	now := hours[0].Temp
+	lo, hi := 0, 0
+	for i, h := range hours {
+		if h.Temp < hours[lo].Temp {
+			lo = i
+		}
+		if h.Temp > hours[hi].Temp {
+			hi = i
+		}
+	}
+	var hour int
+	fmt.Sscanf(hours[lo].Time, "%d", &hour)
+	overnight := hour >= 21 || hour < 9
+	low := hours[lo].Temp
+
+	var s string
+	switch {
+	case now-low >= n.temp(tempSwingC, true) && overnight:
+		s = n.tr("turning colder tonight")
+	case now-low >= n.temp(tempSwingC, true):
+		s = n.sprintf("turning colder, %s by %s", n.deg(low), hours[lo].Time)
+	case hours[hi].Temp-now >= n.temp(tempSwingC, true):
+		return n.sprintf("warming to %s by %s", n.deg(hours[hi].Temp), hours[hi].Time)
+	}
+	if overnight {
+		var dawn string
+		switch {
+		case low <= n.temp(0, false):
+			dawn = n.sprintf("a frost by dawn, down to %s", n.deg(low))
+		case low <= n.temp(nearFreezingC, false):
+			dawn = n.tr("near freezing by dawn")
+		}
+		if s == "" || dawn == "" {
+			return s + dawn
+		}
+		return s + ", " + dawn
+	}
+	return s
+}
+
+// tomorrow compares tomorrow's high with today's and mentions rain when
+// it is likely.
+func (n narrator) tomorrow() string {
+	days := n.info.Forecast
+	if len(days) < 2 {
+		return ""
+	}
+	today, tmrw := days[0], days[1]
+	var s string
+	switch diff := tmrw.TempMax - today.TempMax; {
+	case diff >= n.temp(dayChangeC, true):
+		s = n.sprintf("Tomorrow warmer, with a high of %s", n.deg(tmrw.TempMax))
+	case diff <= -n.temp(dayChangeC, true):
+		s = n.sprintf("Tomorrow colder, with a high of %s", n.deg(tmrw.TempMax))
+	default:
+		s = n.sprintf("Tomorrow similar, with a high of %s", n.deg(tmrw.TempMax))
+	}
+	if tmrw.PrecipProb >= wetLikely {
+		s += n.sprintf(" and %s likely (%d%%)", n.tr(precipKind(tmrw.Condition)), tmrw.PrecipProb)
+	}
+	return s
+}
+
+func capitalise(s string) string {
+	r, size := utf8.DecodeRuneInString(s)
+	if size == 0 {
+		return s
+	}
+	return string(unicode.ToUpper(r)) + s[size:]
+}
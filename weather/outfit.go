@@ -1,6 +1,13 @@
 package weather
 
-import "strconv"
+import (
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"WeatherApp/i18n"
+)
 
 // OutfitItem represents a single clothing or accessory suggestion.
 type OutfitItem struct {
@@ -15,18 +22,52 @@ type OutfitAdvice struct {
 	Headline string       // e.g. "Layer up — cold and wet"
 	Items    []OutfitItem // 3–6 items
 	TempTier string       // "freezing" | "cold" | "cool" | "mild" | "warm" | "hot"
+
+	Activity    string // Activity.Name the advice was built for
+	Suitability Suitability
+
+	// Set by BuildOutfitFor / OutfitOptions.BuildFor only.
+	Window   string       // "08:00–18:00"
+	Timeline []OutfitHour // one entry per forecast hour inside the window
+}
+
+// OutfitHour is one hour of an outfit timeline.
+type OutfitHour struct {
+	Time       string // "HH:MM"
+	FeelsLike  float64
+	PrecipProb int
+	Icon       string // weather icon class
+	Tier       string
+}
+
+// OutfitOptions personalise outfit advice. The zero value is the generic
+// walk that BuildOutfit and BuildOutfitFor assume.
+type OutfitOptions struct {
+	Activity Activity
+	Comfort  *ComfortProfile // nil is the average person
+}
+
+func (o OutfitOptions) activity() Activity {
+	if o.Activity.Key == "" {
+		return DefaultActivity
+	}
+	return o.Activity
 }
 
 // BuildOutfit generates outfit suggestions from current conditions.
 // tempC is always in Celsius (converted internally if needed), units is "metric"|"imperial".
 func BuildOutfit(info *WeatherInfo) OutfitAdvice {
-	cur := info.Current
+	return OutfitOptions{}.Build(info)
+}
 
-	// Normalise feels-like to °C for threshold logic (reuse shared helper)
-	feelsC := toCelsius(cur.FeelsLike, info.TempUnit)
+// BuildOutfitFor is OutfitOptions.BuildFor with the default options.
+func BuildOutfitFor(info *WeatherInfo, from, to time.Time) OutfitAdvice {
+	return OutfitOptions{}.BuildFor(info, from, to)
+}
 
-	// Wind in km/h for thresholds (reuse shared helper)
-	windKmh := toKmh(cur.WindSpeed, info.WindUnit)
+// Build is BuildOutfit for the options' activity.
+func (o OutfitOptions) Build(info *WeatherInfo) OutfitAdvice {
+	cur := info.Current
 
 	// Today's precipitation probability (first forecast day)
 	precipProb := 0
@@ -34,23 +75,76 @@ func BuildOutfit(info *WeatherInfo) OutfitAdvice {
 		precipProb = info.Forecast[0].PrecipProb
 	}
 
-	uv := cur.UVIndex
+	return o.build(outfitConditions{
+		// Normalise feels-like to °C and wind to km/h for threshold logic (reuse shared helpers)
+		airC:       toCelsius(cur.Temp, info.TempUnit),
+		feelsC:     toCelsius(cur.FeelsLike, info.TempUnit),
+		windKmh:    toKmh(cur.WindSpeed, info.WindUnit),
+		windUnit:   info.WindUnit,
+		tempUnit:   info.TempUnit,
+		precipProb: precipProb,
+		precipWhen: i18n.T(info.Lang, "today"),
+		uv:         cur.UVIndex,
+		isDay:      info.Sun.IsDay || info.Sun.SunriseTime == "",
+		lang:       info.Lang,
+	})
+}
 
-	var tier string
+// outfitConditions are the inputs to the outfit rules, already in metric units.
+type outfitConditions struct {
+	airC       float64
+	feelsC     float64
+	windKmh    float64
+	windUnit   string
+	tempUnit   string // "°C" or "°F", for temperatures in reasons
+	precipProb int
+	precipWhen string // "today", "around 17:00", ...
+	uv         float64
+	isDay      bool
+	lang       string // i18n locale for headlines and notes
+}
+
+// outfitTiers lists the temperature tiers from coldest to hottest.
+var outfitTiers = []string{"freezing", "cold", "cool", "mild", "warm", "hot"}
+
+func outfitTier(feelsC float64) string {
 	switch {
 	case feelsC < 0:
-		tier = "freezing"
+		return "freezing"
 	case feelsC < 8:
-		tier = "cold"
+		return "cold"
 	case feelsC < 15:
-		tier = "cool"
+		return "cool"
 	case feelsC < 22:
-		tier = "mild"
+		return "mild"
 	case feelsC < 29:
-		tier = "warm"
+		return "warm"
 	default:
-		tier = "hot"
+		return "hot"
+	}
+}
+
+func tierRank(tier string) int {
+	for i, t := range outfitTiers {
+		if t == tier {
+			return i
+		}
 	}
+	return -1
+}
+
+// suitability scores c for the activity as the profile's owner feels it.
+func (o OutfitOptions) suitability(c outfitConditions) Suitability {
+	c.feelsC = o.Comfort.adjustC(c.feelsC)
+	return o.activity().suitability(c)
+}
+
+func (o OutfitOptions) build(c outfitConditions) OutfitAdvice {
+	act := o.activity()
+	windKmh, precipProb, uv := c.windKmh, c.precipProb, c.uv
+	effC := o.Comfort.adjustC(act.effectiveC(c.airC, c.feelsC, windKmh))
+	tier := outfitTier(effC)
+	tr := func(s string) string { return i18n.T(c.lang, s) }
 
 	headlines := map[string]string{
 		"freezing": "Bundle up — it's freezing out there",
@@ -62,35 +156,40 @@ func BuildOutfit(info *WeatherInfo) OutfitAdvice {
 	}
 
 	advice := OutfitAdvice{
-		Headline: headlines[tier],
-		TempTier: tier,
+		Headline:    tr(headlines[tier]),
+		TempTier:    tier,
+		Activity:    tr(act.Name),
+		Suitability: o.suitability(c),
+		Items:       act.activityItems(c, effC),
 	}
+	// Walkers carry an umbrella; moving or working hands need a shell instead.
+	walking := act.Key == DefaultActivity.Key
 
 	switch tier {
 	case "freezing":
 		advice.Items = append(advice.Items, OutfitItem{
 			Icon: "thermal", Label: "Thermal Base", Color: "oi-blue",
-			Note: "Moisture-wicking thermals keep heat in",
+			Note: tr("Moisture-wicking thermals keep heat in"),
 		})
 	case "cold":
 		advice.Items = append(advice.Items, OutfitItem{
 			Icon: "sweater", Label: "Thick Sweater", Color: "oi-blue",
-			Note: "Wool or fleece sweater recommended",
+			Note: tr("Wool or fleece sweater recommended"),
 		})
 	case "cool":
 		advice.Items = append(advice.Items, OutfitItem{
 			Icon: "longsleeve", Label: "Long Sleeve", Color: "oi-sky",
-			Note: "A long-sleeve shirt is enough inside",
+			Note: tr("A long-sleeve shirt is enough inside"),
 		})
 	case "mild":
 		advice.Items = append(advice.Items, OutfitItem{
 			Icon: "tshirt", Label: "T-Shirt", Color: "oi-green",
-			Note: "Any casual top works great",
+			Note: tr("Any casual top works great"),
 		})
 	case "warm", "hot":
 		advice.Items = append(advice.Items, OutfitItem{
 			Icon: "tshirt", Label: "Light Top", Color: "oi-orange",
-			Note: "Breathable, light-coloured fabric is best",
+			Note: tr("Breathable, light-coloured fabric is best"),
 		})
 	}
 
@@ -98,79 +197,104 @@ func BuildOutfit(info *WeatherInfo) OutfitAdvice {
 	case "freezing":
 		advice.Items = append(advice.Items, OutfitItem{
 			Icon: "coat", Label: "Heavy Coat", Color: "oi-indigo",
-			Note: "Insulated or down-filled coat essential",
+			Note: tr("Insulated or down-filled coat essential"),
 		})
 	case "cold":
 		advice.Items = append(advice.Items, OutfitItem{
 			Icon: "coat", Label: "Winter Coat", Color: "oi-indigo",
-			Note: "Lined coat with hood recommended",
+			Note: tr("Lined coat with hood recommended"),
 		})
 	case "cool":
 		advice.Items = append(advice.Items, OutfitItem{
 			Icon: "jacket", Label: "Light Jacket", Color: "oi-sky",
-			Note: "Zip-up or denim jacket keeps the chill off",
+			Note: tr("Zip-up or denim jacket keeps the chill off"),
 		})
 	}
 
-	if windKmh >= 30 && tier != "freezing" && tier != "cold" {
+	if windKmh >= math.Min(30, act.WindLimitKmh) && tier != "freezing" && tier != "cold" {
 		advice.Items = append(advice.Items, OutfitItem{
 			Icon: "windbreaker", Label: "Windbreaker", Color: "oi-teal",
-			Note: "Gusts up to " + windLabel(windKmh, info.WindUnit) + " — block the wind",
+			Note: i18n.Sprintf(c.lang, "Gusts up to %s — block the wind", windLabel(windKmh, c.windUnit)),
 		})
 	}
 
-	if precipProb >= 60 {
+	switch {
+	case precipProb >= 30 && act.Key == "work":
+		advice.Items = append(advice.Items, OutfitItem{
+			Icon: "raincoat", Label: "Waterproof Suit", Color: "oi-sky",
+			Note: i18n.Sprintf(c.lang, "Jacket and over-trousers (%d%% chance of rain)", precipProb),
+		})
+	case precipProb >= 30 && !walking:
+		advice.Items = append(advice.Items, OutfitItem{
+			Icon: "raincoat", Label: "Waterproof Shell", Color: "oi-sky",
+			Note: i18n.Sprintf(c.lang, "Light, breathable and packable (%d%% chance of rain)", precipProb),
+		})
+	case precipProb >= 60:
 		advice.Items = append(advice.Items, OutfitItem{
 			Icon: "umbrella", Label: "Umbrella", Color: "oi-blue",
-			Note: "Rain likely today (" + strconv.Itoa(precipProb) + "% chance)",
+			Note: i18n.Sprintf(c.lang, "Rain likely %s (%d%% chance)", c.precipWhen, precipProb),
 		})
-	} else if precipProb >= 30 {
+	case precipProb >= 30:
 		advice.Items = append(advice.Items, OutfitItem{
 			Icon: "raincoat", Label: "Rain Jacket", Color: "oi-sky",
-			Note: "Pack one just in case (" + strconv.Itoa(precipProb) + "% chance)",
+			Note: i18n.Sprintf(c.lang, "Pack one just in case (%d%% chance)", precipProb),
 		})
 	}
 
 	if uv >= 8 {
 		advice.Items = append(advice.Items, OutfitItem{
 			Icon: "sunscreen", Label: "SPF 50+", Color: "oi-orange",
-			Note: "UV is very high — reapply every 2 hours",
+			Note: tr("UV is very high — reapply every 2 hours"),
 		})
 		advice.Items = append(advice.Items, OutfitItem{
 			Icon: "sunglasses", Label: "Sunglasses", Color: "oi-amber",
-			Note: "Protect your eyes from UV " + strconv.Itoa(int(uv)) + " index",
+			Note: i18n.Sprintf(c.lang, "Protect your eyes from UV %d index", int(uv)),
 		})
 	} else if uv >= 5 {
 		advice.Items = append(advice.Items, OutfitItem{
 			Icon: "sunscreen", Label: "Sunscreen", Color: "oi-amber",
-			Note: "UV " + strconv.Itoa(int(uv)) + " — SPF 30 before heading out",
+			Note: i18n.Sprintf(c.lang, "UV %d — SPF 30 before heading out", int(uv)),
 		})
 	}
 
 	if tier == "freezing" {
 		advice.Items = append(advice.Items, OutfitItem{
 			Icon: "beanie", Label: "Beanie + Gloves", Color: "oi-indigo",
-			Note: "Extremities lose heat fast in freezing temps",
+			Note: tr("Extremities lose heat fast in freezing temps"),
 		})
 	} else if uv >= 6 {
 		advice.Items = append(advice.Items, OutfitItem{
 			Icon: "hat", Label: "Sun Hat", Color: "oi-amber",
-			Note: "Wide brim hat shields face and neck",
+			Note: tr("Wide brim hat shields face and neck"),
 		})
 	}
 
-	if precipProb >= 50 || tier == "freezing" {
+	switch {
+	case act.Key == "work":
+		advice.Items = append(advice.Items, OutfitItem{
+			Icon: "boots", Label: "Safety Boots", Color: "oi-teal",
+			Note: tr("Steel toe caps; waterproof if the ground is wet"),
+		})
+	case !walking:
+		// Cyclists and runners keep their sport shoes.
+	case precipProb >= 50 || tier == "freezing":
 		advice.Items = append(advice.Items, OutfitItem{
 			Icon: "boots", Label: "Waterproof Boots", Color: "oi-teal",
-			Note: "Keep feet dry on wet ground",
+			Note: tr("Keep feet dry on wet ground"),
 		})
-	} else if tier == "hot" {
+	case tier == "hot":
 		advice.Items = append(advice.Items, OutfitItem{
 			Icon: "sandals", Label: "Sandals", Color: "oi-orange",
-			Note: "Let your feet breathe in the heat",
+			Note: tr("Let your feet breathe in the heat"),
 		})
 	}
 
+	// Labels are translated last, after the profile's own label choices.
+	advice.Items = o.Comfort.apply(advice.Items)
+	for i := range advice.Items {
+		advice.Items[i].Label = tr(advice.Items[i].Label)
+	}
+
 	// Cap to 6 items max to keep the card compact
 	if len(advice.Items) > 6 {
 		advice.Items = advice.Items[:6]
@@ -179,6 +303,143 @@ func BuildOutfit(info *WeatherInfo) OutfitAdvice {
 	return advice
 }
 
+// BuildFor recommends an outfit for the hours between from and to, which
+// are local wall-clock times matched against HourlyPoint.DateTime.
+// Layers are chosen for the coldest hour, rain gear for the wettest, and the
+// headline says what to leave with and when conditions change, e.g.
+// "Leave with a jacket, carry an umbrella for the 17:00 showers".
+// Suitability is the average over the window's hours.
+func (o OutfitOptions) BuildFor(info *WeatherInfo, from, to time.Time) OutfitAdvice {
+	const layout = "2006-01-02T15:04"
+	lo := from.Truncate(time.Hour).Format(layout)
+	hi := to.Format(layout)
+	window := from.Format("15:04") + "–" + to.Format("15:04")
+
+	var points []HourlyPoint
+	for _, p := range info.Hourly {
+		if p.DateTime >= lo && p.DateTime <= hi {
+			points = append(points, p)
+		}
+	}
+	lang := info.Lang
+	if len(points) == 0 {
+		return OutfitAdvice{Window: window, Headline: i18n.Sprintf(lang, "No hourly forecast covers %s", window)}
+	}
+
+	act := o.activity()
+	conds := make([]outfitConditions, len(points))
+	timeline := make([]OutfitHour, len(points))
+	coldest, warmest, wettest := 0, 0, 0
+	effs := make([]float64, len(points))
+	var windMax, uvMax float64
+	scoreSum := 0
+	for i, p := range points {
+		conds[i] = outfitConditions{
+			airC:       toCelsius(p.Temp, info.TempUnit),
+			feelsC:     toCelsius(p.FeelsLike, info.TempUnit),
+			windKmh:    toKmh(p.WindSpeed, info.WindUnit),
+			windUnit:   info.WindUnit,
+			tempUnit:   info.TempUnit,
+			precipProb: p.PrecipProb,
+			uv:         p.UVIndex,
+			isDay:      p.UVIndex > 0, // hourly UV is zero between dusk and dawn
+			lang:       lang,
+		}
+		effs[i] = o.Comfort.adjustC(act.effectiveC(conds[i].airC, conds[i].feelsC, conds[i].windKmh))
+		timeline[i] = OutfitHour{
+			Time: p.Time, FeelsLike: p.FeelsLike, PrecipProb: p.PrecipProb,
+			Icon: p.Icon, Tier: outfitTier(effs[i]),
+		}
+		if effs[i] < effs[coldest] {
+			coldest = i
+		}
+		if effs[i] > effs[warmest] {
+			warmest = i
+		}
+		if p.PrecipProb > points[wettest].PrecipProb {
+			wettest = i
+		}
+		windMax = math.Max(windMax, conds[i].windKmh)
+		uvMax = math.Max(uvMax, p.UVIndex)
+		scoreSum += o.suitability(conds[i]).Score
+	}
+
+	// Dress for the coldest hour, with the worst wind, rain and UV of the window.
+	worst := conds[coldest]
+	worst.windKmh = windMax
+	worst.precipProb = points[wettest].PrecipProb
+	worst.precipWhen = i18n.Sprintf(lang, "around %s", points[wettest].Time)
+	worst.uv = uvMax
+	worst.isDay = worst.isDay && conds[len(conds)-1].isDay
+	advice := o.build(worst)
+	advice.Window = window
+	advice.Timeline = timeline
+	advice.Suitability.Score = scoreSum / len(points)
+	advice.Suitability.Label = suitabilityLabel(advice.Suitability.Score)
+
+	// Headline: what to leave with, then what changes along the way.
+	tr := func(s string) string { return i18n.T(lang, s) }
+	startTier := timeline[0].Tier
+	parts := []string{i18n.Sprintf(lang, "leave with %s", tr(tierGarment[startTier]))}
+	for _, h := range timeline[1:] {
+		if tierRank(h.Tier) < tierRank(startTier) && tierGarment[h.Tier] != tierGarment[startTier] {
+			parts = append(parts, i18n.Sprintf(lang, "add %s by %s", tr(tierGarment[h.Tier]), h.Time))
+			break
+		}
+	}
+	if tierRank(timeline[warmest].Tier) >= tierRank(startTier)+2 {
+		parts = append(parts, i18n.Sprintf(lang, "expect to shed layers around %s", timeline[warmest].Time))
+	}
+	wet := points[wettest]
+	rainGear := "a waterproof"
+	for _, it := range advice.Items {
+		if it.Icon == "umbrella" {
+			rainGear = "an umbrella"
+		}
+	}
+	switch {
+	case wet.PrecipProb >= 60:
+		parts = append(parts, i18n.Sprintf(lang, "carry %s for the %s %s", tr(rainGear), wet.Time, tr(precipNoun(wet.Condition))))
+	case wet.PrecipProb >= 30:
+		parts = append(parts, i18n.Sprintf(lang, "pack a rain jacket in case of %s around %s", tr(precipNoun(wet.Condition)), wet.Time))
+	}
+	advice.Headline = capitalise(strings.Join(parts, ", "))
+
+	return advice
+}
+
+// tierGarment is the outer layer to leave the house in for each tier.
+var tierGarment = map[string]string{
+	"freezing": "a heavy coat",
+	"cold":     "a winter coat",
+	"cool":     "a jacket",
+	"mild":     "a light layer",
+	"warm":     "light clothes",
+	"hot":      "light clothes",
+}
+
+// precipNoun names the precipitation in an hour's condition for headlines.
+func precipNoun(c Condition) string {
+	switch {
+	case c.Precip == PrecipSnow:
+		return "snow"
+	case c.Thunder:
+		return "storms"
+	case c.Showers:
+		return "showers"
+	default:
+		return "rain"
+	}
+}
+
+// tempDeltaLabel formats a difference of d °C in unit, e.g. "9°F".
+func tempDeltaLabel(d float64, unit string) string {
+	if unit == "°F" {
+		return strconv.Itoa(int(math.Round(d*9/5))) + "°F"
+	}
+	return strconv.Itoa(int(math.Round(d))) + "°C"
+}
+
 func windLabel(kmh float64, unit string) string {
 	switch unit {
 	case "mph":
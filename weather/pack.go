@@ -0,0 +1,405 @@
+package weather
+
+import (
+	"fmt"
+	"math"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"WeatherApp/i18n"
+)
+
+// tripForecastDays is the longest daily forecast Open-Meteo offers; trip
+// dates beyond it are reported as missing rather than guessed.
+const tripForecastDays = 16
+
+// TripLeg is one stop on an itinerary: a city for an inclusive date range.
+type TripLeg struct {
+	City     string
+	From, To time.Time // dates, midnight UTC
+}
+
+// ParseTripLeg parses "City:2006-01-02[:2006-01-02]". A missing end date
+// means a single day; a leg may not be longer than the forecast reaches.
+func ParseTripLeg(spec string) (TripLeg, error) {
+	parts := strings.Split(strings.TrimSpace(spec), ":")
+	var dates []time.Time
+	for len(parts) > 1 && len(dates) < 2 {
+		d, err := time.Parse("2006-01-02", strings.TrimSpace(parts[len(parts)-1]))
+		if err != nil {
+			break
+		}
+		dates = append([]time.Time{d}, dates...)
+		parts = parts[:len(parts)-1]
+	}
+	city := strings.TrimSpace(strings.Join(parts, ":"))
+	switch {
+	case city == "":
+		return TripLeg{}, fmt.Errorf("trip leg %q: missing city", spec)
+	case len(dates) == 0:
+		return TripLeg{}, fmt.Errorf("trip leg %q: want City:YYYY-MM-DD[:YYYY-MM-DD]", spec)
+	}
+	leg := TripLeg{City: city, From: dates[0], To: dates[len(dates)-1]}
+	if leg.To.Before(leg.From) {
+		return TripLeg{}, fmt.Errorf("trip leg %q: ends before it starts", spec)
+	}
+	if leg.To.Sub(leg.From) >= tripForecastDays*24*time.Hour {
+		return TripLeg{}, fmt.Errorf("trip leg %q: longer than %d days", spec, tripForecastDays)
+	}
+	return leg, nil
+}
+
+// ParseItinerary parses one leg per line (or per ";") in ParseTripLeg form.
+func ParseItinerary(spec string) ([]TripLeg, error) {
+	var legs []TripLeg
+	for _, line := range strings.FieldsFunc(spec, func(r rune) bool { return r == '\n' || r == ';' }) {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		leg, err := ParseTripLeg(line)
+		if err != nil {
+			return nil, err
+		}
+		legs = append(legs, leg)
+	}
+	if len(legs) == 0 {
+		return nil, fmt.Errorf("itinerary is empty")
+	}
+	return legs, nil
+}
+
+// TripDay is one day's forecast at one stop.
+type TripDay struct {
+	City        string
+	Date        string // "2006-01-02"
+	Weekday     string // "Wed"
+	Code        int    // WMO weather code
+	Condition   Condition
+	Description string
+	Icon        string
+	TempMax     float64
+	TempMin     float64
+	FeelsMax    float64
+	FeelsMin    float64
+	PrecipProb  int
+	WindMax     float64
+	UVMax       float64
+}
+
+// LegForecast is a leg with its daily forecasts, or the reason it has none.
+type LegForecast struct {
+	Leg     TripLeg
+	Country string
+	Days    []TripDay
+	Missing []string // dates outside the forecast range
+	Err     string
+}
+
+// PackItem is one line of the packing list.
+type PackItem struct {
+	Icon   string
+	Name   string // "t-shirts", "rain jacket", "sunscreen"
+	Qty    int    // 0 for things you don't count (sunscreen, sunglasses)
+	Reason string // "rain in Berlin Wed"
+}
+
+// String formats the item as in "3 t-shirts" or "1 rain jacket (rain in Berlin Wed)".
+func (p PackItem) String() string {
+	s := p.Name
+	if p.Qty > 0 {
+		s = fmt.Sprintf("%d %s", p.Qty, p.Name)
+	}
+	if p.Reason != "" {
+		s += " (" + p.Reason + ")"
+	}
+	return s
+}
+
+// PackingList is the aggregated plan for a whole itinerary.
+type PackingList struct {
+	Legs     []LegForecast
+	Items    []PackItem
+	Days     int // trip days with a forecast
+	TempUnit string
+	WindUnit string
+}
+
+// Summary joins the items into one line, e.g.
+// "3 t-shirts, 1 rain jacket (rain in Berlin Wed), sunscreen (UV 9 in Lisbon)".
+func (p *PackingList) Summary() string {
+	parts := make([]string, len(p.Items))
+	for i, it := range p.Items {
+		parts[i] = it.String()
+	}
+	return strings.Join(parts, ", ")
+}
+
+// packSpec says how an outfit item turns into a packing line.
+type packSpec struct {
+	singular, plural string
+	per              int  // one per this many days worn (0 = one for the trip)
+	uncounted        bool // listed without a quantity
+}
+
+var packCatalog = map[string]packSpec{
+	"tshirt":      {"t-shirt", "t-shirts", 1, false},
+	"longsleeve":  {"long-sleeve top", "long-sleeve tops", 1, false},
+	"thermal":     {"thermal base layer", "thermal base layers", 2, false},
+	"sweater":     {"warm sweater", "warm sweaters", 3, false},
+	"coat":        {"warm coat", "warm coats", 0, false},
+	"jacket":      {"light jacket", "light jackets", 0, false},
+	"windbreaker": {"windbreaker", "windbreakers", 0, false},
+	"raincoat":    {"rain jacket", "rain jackets", 0, false},
+	"umbrella":    {"umbrella", "umbrellas", 0, false},
+	"boots":       {"pair of waterproof boots", "pairs of waterproof boots", 0, false},
+	"sandals":     {"pair of sandals", "pairs of sandals", 0, false},
+	"beanie":      {"beanie and gloves", "beanies and gloves", 0, false},
+	"hat":         {"sun hat", "sun hats", 0, false},
+	"sunglasses":  {"sunglasses", "sunglasses", 0, true},
+	"sunscreen":   {"sunscreen", "sunscreen", 0, true},
+	"hivis":       {"hi-vis vest", "hi-vis vests", 0, false},
+	"gloves":      {"pair of gloves", "pairs of gloves", 0, false},
+	"lights":      {"bike lights", "bike lights", 0, true},
+	"bottle":      {"water bottle", "water bottles", 0, false},
+}
+
+// packOrder lists clothes first, then outerwear, rain gear and accessories.
+var packOrder = []string{
+	"tshirt", "longsleeve", "thermal", "sweater",
+	"coat", "jacket", "windbreaker", "raincoat", "umbrella",
+	"boots", "sandals", "beanie", "gloves", "hat", "sunglasses", "sunscreen",
+	"hivis", "lights", "bottle",
+}
+
+// PlanTrip geocodes and fetches a daily forecast for every leg concurrently,
+// then turns the per-day outfits into one packing list, in c.Lang. A leg that
+// fails keeps its error in LegForecast.Err and the rest of the trip is still
+// planned.
+func (c *Client) PlanTrip(legs []TripLeg, units string, opts OutfitOptions) *PackingList {
+	tempUnit, windUnit := "celsius", "kmh"
+	if units == "imperial" {
+		tempUnit, windUnit = "fahrenheit", "mph"
+	}
+
+	forecasts := make([]LegForecast, len(legs))
+	var wg sync.WaitGroup
+	for i, leg := range legs {
+		wg.Add(1)
+		go func(i int, leg TripLeg) {
+			defer wg.Done()
+			forecasts[i] = c.fetchLeg(leg, tempUnit, windUnit)
+		}(i, leg)
+	}
+	wg.Wait()
+
+	return buildPackingList(forecasts, TempUnitSymbol(units), WindUnitLabel(units), c.lang(), opts)
+}
+
+// fetchLeg fetches the daily forecast for one leg and keeps the leg's dates.
+func (c *Client) fetchLeg(leg TripLeg, tempUnit, windUnit string) LegForecast {
+	lf := LegForecast{Leg: leg}
+	loc, err := c.Geocode(leg.City)
+	if err != nil {
+		lf.Err = err.Error()
+		return lf
+	}
+	lf.Leg.City = loc.Name
+	lf.Country = loc.Country
+
+	u := fmt.Sprintf(
+		"%s?latitude=%.4f&longitude=%.4f"+
+			"&daily=weather_code,temperature_2m_max,temperature_2m_min,apparent_temperature_max,apparent_temperature_min,"+
+			"precipitation_probability_max,wind_speed_10m_max,uv_index_max"+
+			"&temperature_unit=%s&wind_speed_unit=%s&timezone=%s&forecast_days=%d",
+		forecastURL, loc.Latitude, loc.Longitude,
+		tempUnit, windUnit, url.QueryEscape(loc.Timezone), tripForecastDays,
+	)
+	var raw struct {
+		Daily struct {
+			Time        []string  `json:"time"`
+			WeatherCode []int     `json:"weather_code"`
+			TempMax     []float64 `json:"temperature_2m_max"`
+			TempMin     []float64 `json:"temperature_2m_min"`
+			FeelsMax    []float64 `json:"apparent_temperature_max"`
+			FeelsMin    []float64 `json:"apparent_temperature_min"`
+			PrecipProb  []int     `json:"precipitation_probability_max"`
+			WindMax     []float64 `json:"wind_speed_10m_max"`
+			UVMax       []float64 `json:"uv_index_max"`
+		} `json:"daily"`
+	}
+	if err := c.getJSON(u, &raw); err != nil {
+		lf.Err = fmt.Sprintf("forecast: %v", err)
+		return lf
+	}
+
+	byDate := make(map[string]int, len(raw.Daily.Time))
+	for i, d := range raw.Daily.Time {
+		byDate[d] = i
+	}
+	for d := leg.From; !d.After(leg.To); d = d.AddDate(0, 0, 1) {
+		date := d.Format("2006-01-02")
+		i, ok := byDate[date]
+		if !ok {
+			lf.Missing = append(lf.Missing, date)
+			continue
+		}
+		code := safeInt(raw.Daily.WeatherCode, i)
+		lf.Days = append(lf.Days, TripDay{
+			City:        lf.Leg.City,
+			Date:        date,
+			Weekday:     i18n.Date(c.lang(), d, "Mon"),
+			Code:        code,
+			Condition:   WMOCondition(code),
+			Description: WMODescription(code),
+			Icon:        WMOIconClass(code),
+			TempMax:     safeFloat(raw.Daily.TempMax, i),
+			TempMin:     safeFloat(raw.Daily.TempMin, i),
+			FeelsMax:    safeFloat(raw.Daily.FeelsMax, i),
+			FeelsMin:    safeFloat(raw.Daily.FeelsMin, i),
+			PrecipProb:  safeInt(raw.Daily.PrecipProb, i),
+			WindMax:     safeFloat(raw.Daily.WindMax, i),
+			UVMax:       safeFloat(raw.Daily.UVMax, i),
+		})
+	}
+	return lf
+}
+
+// buildPackingList runs the outfit rules for each trip day and aggregates
+// the items: clothes worn daily are counted per day, gear is packed once,
+// and each line remembers the day that needed it most.
+func buildPackingList(legs []LegForecast, tempUnit, windUnit, lang string, opts OutfitOptions) *PackingList {
+	pl := &PackingList{Legs: legs, TempUnit: tempUnit, WindUnit: windUnit}
+	tr := func(s string) string { return i18n.T(lang, s) }
+
+	type tally struct {
+		days     int
+		label    string  // outfit label from the most demanding day
+		strength float64 // how demanding that day was, per item kind
+		reason   string
+	}
+	tallies := make(map[string]*tally)
+	work := opts.activity().Key == "work"
+
+	for _, leg := range legs {
+		for _, d := range leg.Days {
+			pl.Days++
+			// Dress for the middle of the day; the evening low picks the layers.
+			c := outfitConditions{
+				airC:       toCelsius((d.TempMax+d.TempMin)/2, tempUnit),
+				feelsC:     toCelsius((d.FeelsMax+d.FeelsMin)/2, tempUnit),
+				windKmh:    toKmh(d.WindMax, windUnit),
+				windUnit:   windUnit,
+				tempUnit:   tempUnit,
+				precipProb: d.PrecipProb,
+				precipWhen: i18n.Sprintf(lang, "on %s", d.Weekday),
+				uv:         d.UVMax,
+				isDay:      true,
+				lang:       lang,
+			}
+			outfit := opts.build(c)
+			when := d.City + " " + d.Weekday
+			for _, it := range outfit.Items {
+				t := tallies[it.Icon]
+				if t == nil {
+					t = &tally{label: it.Label, strength: math.Inf(-1)}
+					tallies[it.Icon] = t
+				}
+				t.days++
+				var strength float64
+				var reason string
+				switch it.Icon {
+				case "umbrella", "raincoat":
+					strength = float64(d.PrecipProb)
+					reason = i18n.Sprintf(lang, "rain in %s, %d%%", when, d.PrecipProb)
+				case "boots":
+					// Boots come for rain, for the cold, or for work every day.
+					switch {
+					case work:
+						strength = 0
+					case d.PrecipProb >= 50:
+						strength = float64(d.PrecipProb)
+						reason = i18n.Sprintf(lang, "rain in %s, %d%%", when, d.PrecipProb)
+					default:
+						strength = -d.FeelsMin
+						reason = i18n.Sprintf(lang, "down to %.0f%s in %s", d.FeelsMin, tempUnit, when)
+					}
+				case "sunscreen", "sunglasses", "hat":
+					strength = d.UVMax
+					reason = i18n.Sprintf(lang, "UV %.0f in %s", d.UVMax, when)
+				case "windbreaker":
+					strength = d.WindMax
+					reason = i18n.Sprintf(lang, "wind %.0f %s in %s", d.WindMax, windUnit, when)
+				case "sandals", "bottle":
+					strength = d.FeelsMax
+					reason = i18n.Sprintf(lang, "up to %.0f%s in %s", d.FeelsMax, tempUnit, when)
+				default:
+					strength = -d.FeelsMin
+					reason = i18n.Sprintf(lang, "down to %.0f%s in %s", d.FeelsMin, tempUnit, when)
+				}
+				if strength > t.strength {
+					t.strength, t.reason, t.label = strength, reason, it.Label
+				}
+			}
+		}
+	}
+
+	// Socks and underwear are always needed, one of each per day.
+	if pl.Days > 0 {
+		pl.Items = append(pl.Items, PackItem{Icon: "socks", Name: tr(pluralize("pair of socks and underwear", "pairs of socks and underwear", pl.Days)), Qty: pl.Days})
+	}
+
+	order := append([]string(nil), packOrder...)
+	var extra []string
+	for icon := range tallies {
+		if _, ok := packCatalog[icon]; !ok {
+			extra = append(extra, icon)
+		}
+	}
+	sort.Strings(extra)
+	order = append(order, extra...)
+
+	for _, icon := range order {
+		t := tallies[icon]
+		if t == nil {
+			continue
+		}
+		spec, ok := packCatalog[icon]
+		if !ok {
+			spec = packSpec{singular: strings.ToLower(t.label), plural: strings.ToLower(t.label)}
+		}
+		if icon == "boots" && work {
+			spec.singular, spec.plural = "pair of safety boots", "pairs of safety boots"
+		}
+		spec.singular, spec.plural = tr(spec.singular), tr(spec.plural)
+		if opts.Comfort != nil && opts.Comfort.Prefer[icon] != "" {
+			spec.singular, spec.plural = opts.Comfort.Prefer[icon], opts.Comfort.Prefer[icon]
+		}
+		item := PackItem{Icon: icon}
+		switch {
+		case spec.uncounted:
+			item.Name = spec.singular
+		case spec.per > 0:
+			item.Qty = (t.days + spec.per - 1) / spec.per
+			item.Name = pluralize(spec.singular, spec.plural, item.Qty)
+		default:
+			item.Qty = 1
+			item.Name = spec.singular
+		}
+		// Clothes worn every day explain themselves; gear says why.
+		if spec.per != 1 {
+			item.Reason = t.reason
+		}
+		pl.Items = append(pl.Items, item)
+	}
+	return pl
+}
+
+func pluralize(singular, plural string, n int) string {
+	if n == 1 {
+		return singular
+	}
+	return plural
+}
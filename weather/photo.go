@@ -0,0 +1,143 @@
+package weather
+
+import (
+	"math"
+	"time"
+
+	"WeatherApp/astro"
+)
+
+// PhotoWindow is one golden or blue hour.
+type PhotoWindow struct {
+	Kind      string // "Blue hour" or "Golden hour"
+	Session   string // "Morning" or "Evening"
+	Span      astro.Span
+	AzimuthAt float64 // sun azimuth at the middle of the window, degrees from north
+	Direction string  // compass point to face, e.g. "SW"
+	CloudLow  int     // % at the window's hour
+	CloudMid  int
+	CloudHigh int
+	Score     int // 0–100 chance of good light and colour
+	Rating    string
+	Past      bool
+}
+
+// PhotoDay is one day's photography plan: the sun's rise and set bearings
+// and the golden and blue hours in time order.
+type PhotoDay struct {
+	Date      string // "2006-01-02"
+	Label     string // "Today", "Mon 19"
+	Sunrise   time.Time
+	Sunset    time.Time
+	SunriseAz float64
+	SunsetAz  float64
+	Windows   []PhotoWindow
+}
+
+// Best returns the day's highest-scoring window that is still ahead, or
+// nil when none is.
+func (d PhotoDay) Best() *PhotoWindow {
+	var best *PhotoWindow
+	for i := range d.Windows {
+		w := &d.Windows[i]
+		if !w.Past && (best == nil || w.Score > best.Score) {
+			best = w
+		}
+	}
+	return best
+}
+
+// PhotoRating is the label for a light-quality score.
+func PhotoRating(score int) string {
+	switch {
+	case score >= 80:
+		return "Vivid"
+	case score >= 60:
+		return "Good"
+	case score >= 40:
+		return "Fair"
+	}
+	return "Flat"
+}
+
+// photoScore predicts colour at sunrise or sunset from the cloud layers.
+// Mid and high cloud catch the light and are best at about half cover; a
+// clear sky still gives clean golden light but little drama. Low cloud
+// hides the horizon, and a solid mid/high deck dulls everything.
+func photoScore(low, mid, high int) int {
+	canvas := float64(max(mid, high))
+	colour := 1 - math.Abs(canvas-50)/50
+	blocked := 1 - 0.85*float64(low)/100
+	dull := 1 - 0.6*math.Max(0, canvas-80)/20
+	return int(math.Round(100 * (0.45 + 0.55*colour) * blocked * dull))
+}
+
+// parsePhotoPlan builds the plan for every forecast day. Today's sun times
+// come from sun; the rest are computed the same way.
+func parsePhotoPlan(h hourlyRaw, sun SunBar, currentTimeStr string, lat, lon float64, timezone string) []PhotoDay {
+	const layout = "2006-01-02T15:04"
+	tz, err := time.LoadLocation(timezone)
+	if err != nil {
+		tz = time.UTC
+	}
+	now, err := time.ParseInLocation(layout, currentTimeStr, tz)
+	if err != nil {
+		return nil
+	}
+	index := make(map[string]int, len(h.Time))
+	for i, ts := range h.Time {
+		index[ts] = i
+	}
+
+	var days []PhotoDay
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, tz)
+	for day := today; ; day = day.AddDate(0, 0, 1) {
+		if _, ok := index[day.Format(layout)]; !ok {
+			break
+		}
+		times := sun.Times
+		if !day.Equal(today) || times.SolarNoon.IsZero() {
+			times = astro.Sun(day, lat, lon)
+		}
+		d := PhotoDay{
+			Date:    day.Format("2006-01-02"),
+			Label:   day.Format("Mon 2"),
+			Sunrise: times.Sunrise,
+			Sunset:  times.Sunset,
+		}
+		if day.Equal(today) {
+			d.Label = "Today"
+		}
+		if !times.Sunrise.IsZero() {
+			d.SunriseAz = astro.SunPosition(times.Sunrise, lat, lon).Azimuth
+		}
+		if !times.Sunset.IsZero() {
+			d.SunsetAz = astro.SunPosition(times.Sunset, lat, lon).Azimuth
+		}
+		for _, w := range []PhotoWindow{
+			{Kind: "Blue hour", Session: "Morning", Span: times.BlueMorning},
+			{Kind: "Golden hour", Session: "Morning", Span: times.GoldenMorning},
+			{Kind: "Golden hour", Session: "Evening", Span: times.GoldenEvening},
+			{Kind: "Blue hour", Session: "Evening", Span: times.BlueEvening},
+		} {
+			if w.Span.IsZero() {
+				continue
+			}
+			mid := w.Span.From.Add(w.Span.To.Sub(w.Span.From) / 2)
+			w.AzimuthAt = astro.SunPosition(mid, lat, lon).Azimuth
+			w.Direction = WindCompass(int(math.Round(w.AzimuthAt)))
+			hour := time.Date(mid.Year(), mid.Month(), mid.Day(), mid.Hour(), 0, 0, 0, tz)
+			if i, ok := index[hour.Format(layout)]; ok {
+				w.CloudLow = safeInt(h.CloudLow, i)
+				w.CloudMid = safeInt(h.CloudMid, i)
+				w.CloudHigh = safeInt(h.CloudHigh, i)
+			}
+			w.Score = photoScore(w.CloudLow, w.CloudMid, w.CloudHigh)
+			w.Rating = PhotoRating(w.Score)
+			w.Past = !w.Span.To.After(now)
+			d.Windows = append(d.Windows, w)
+		}
+		days = append(days, d)
+	}
+	return days
+}
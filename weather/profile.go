@@ -0,0 +1,263 @@
+package weather
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Feel is a piece of comfort feedback: how the outfit advice felt in practice.
+type Feel string
+
+const (
+	FeltCold Feel = "cold"
+	FeltOK   Feel = "ok"
+	FeltHot  Feel = "hot"
+)
+
+// ParseFeel accepts "cold", "ok" or "hot" and a few everyday synonyms.
+func ParseFeel(s string) (Feel, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "cold", "too-cold", "chilly":
+		return FeltCold, nil
+	case "ok", "fine", "right", "just-right":
+		return FeltOK, nil
+	case "hot", "too-hot", "warm":
+		return FeltHot, nil
+	}
+	return "", fmt.Errorf("unknown feedback %q (want cold, ok or hot)", s)
+}
+
+const (
+	maxComfortOffset = 6.0 // °C either way
+	maxFeedback      = 30  // history kept for display and step sizing
+)
+
+// ComfortFeedback is one recorded Feel.
+type ComfortFeedback struct {
+	Date string `json:"date"` // "2006-01-02"
+	Feel Feel   `json:"feel"`
+}
+
+// ComfortProfile personalises outfit advice. OffsetC is how many degrees
+// colder than average the user feels: +2 moves every tier boundary up 2 °C,
+// so they are told to wrap up sooner. Prefer renames items by icon key
+// (e.g. "jacket" → "Hoodie") and Avoid drops them (e.g. "umbrella").
+type ComfortProfile struct {
+	OffsetC  float64           `json:"offset_c"`
+	Prefer   map[string]string `json:"prefer,omitempty"`
+	Avoid    []string          `json:"avoid,omitempty"`
+	Feedback []ComfortFeedback `json:"feedback,omitempty"`
+}
+
+// AddFeedback records a Feel for date and moves OffsetC towards it. Steps
+// start at 1 °C and shrink as feedback accumulates, so the offset settles
+// instead of swinging with every chilly morning. Only the latest feedback per
+// day counts.
+func (p *ComfortProfile) AddFeedback(feel Feel, date time.Time) {
+	day := date.Format("2006-01-02")
+	if n := len(p.Feedback); n > 0 && p.Feedback[n-1].Date == day {
+		p.OffsetC -= feelStep(p.Feedback[n-1].Feel, n-1) // undo today's earlier answer
+		p.Feedback = p.Feedback[:n-1]
+	}
+	p.OffsetC += feelStep(feel, len(p.Feedback))
+	p.OffsetC = math.Round(math.Max(-maxComfortOffset, math.Min(maxComfortOffset, p.OffsetC))*10) / 10
+	p.Feedback = append(p.Feedback, ComfortFeedback{Date: day, Feel: feel})
+	if len(p.Feedback) > maxFeedback {
+		p.Feedback = p.Feedback[len(p.Feedback)-maxFeedback:]
+	}
+}
+
+// feelStep is the offset change for the (n+1)th piece of feedback.
+func feelStep(feel Feel, n int) float64 {
+	step := math.Max(0.25, 1/(1+float64(n)/5))
+	switch feel {
+	case FeltCold:
+		return step
+	case FeltHot:
+		return -step
+	}
+	return 0
+}
+
+// Summary describes the calibration, e.g. "runs 1.5 °C cold".
+func (p *ComfortProfile) Summary() string {
+	switch {
+	case p == nil || math.Abs(p.OffsetC) < 0.25:
+		return "average"
+	case p.OffsetC > 0:
+		return fmt.Sprintf("runs %.1f °C cold", p.OffsetC)
+	default:
+		return fmt.Sprintf("runs %.1f °C hot", -p.OffsetC)
+	}
+}
+
+// SetOffset sets OffsetC, which must be within the ±6 °C that feedback
+// can reach.
+func (p *ComfortProfile) SetOffset(c float64) error {
+	if math.IsNaN(c) || math.Abs(c) > maxComfortOffset {
+		return fmt.Errorf("offset %g °C: want -%g to +%g", c, maxComfortOffset, maxComfortOffset)
+	}
+	p.OffsetC = c
+	return nil
+}
+
+// SetPrefer merges "icon=Label,..." into Prefer; an empty label forgets that
+// icon's preference.
+func (p *ComfortProfile) SetPrefer(spec string) error {
+	if p.Prefer == nil {
+		p.Prefer = map[string]string{}
+	}
+	for _, pair := range strings.Split(spec, ",") {
+		icon, label, ok := strings.Cut(pair, "=")
+		if !ok {
+			return fmt.Errorf("prefer %q: want icon=Label", pair)
+		}
+		if label = strings.TrimSpace(label); label == "" {
+			delete(p.Prefer, strings.TrimSpace(icon))
+		} else {
+			p.Prefer[strings.TrimSpace(icon)] = label
+		}
+	}
+	return nil
+}
+
+// PreferSpec is Prefer in SetPrefer's format, sorted by icon.
+func (p *ComfortProfile) PreferSpec() string {
+	if p == nil {
+		return ""
+	}
+	pairs := make([]string, 0, len(p.Prefer))
+	for icon, label := range p.Prefer {
+		pairs = append(pairs, icon+"="+label)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+// SetAvoid replaces Avoid with the comma-separated icon keys in spec; "" or
+// "-" clears it.
+func (p *ComfortProfile) SetAvoid(spec string) {
+	p.Avoid = nil
+	for _, icon := range strings.Split(spec, ",") {
+		if icon = strings.TrimSpace(icon); icon != "" && icon != "-" {
+			p.Avoid = append(p.Avoid, icon)
+		}
+	}
+}
+
+// adjustC returns the temperature the profile's owner experiences.
+func (p *ComfortProfile) adjustC(c float64) float64 {
+	if p == nil {
+		return c
+	}
+	return c - p.OffsetC
+}
+
+// apply renames preferred items and drops avoided ones. An avoided umbrella
+// becomes a rain jacket so rain is still covered.
+func (p *ComfortProfile) apply(items []OutfitItem) []OutfitItem {
+	if p == nil {
+		return items
+	}
+	avoid := make(map[string]bool, len(p.Avoid))
+	for _, a := range p.Avoid {
+		avoid[a] = true
+	}
+	out := items[:0:0]
+	for _, it := range items {
+		if avoid[it.Icon] {
+			if it.Icon != "umbrella" || avoid["raincoat"] {
+				continue
+			}
+			it.Icon, it.Label = "raincoat", "Rain Jacket"
+		}
+		if label := p.Prefer[it.Icon]; label != "" {
+			it.Label = label
+		}
+		out = append(out, it)
+	}
+	return out
+}
+
+// Advice is the package Advice with feelsLike shifted by the profile offset.
+func (p *ComfortProfile) Advice(feelsLike float64, unit string) string {
+	if p == nil {
+		return Advice(feelsLike, unit)
+	}
+	offset := p.OffsetC
+	if unit == "°F" {
+		offset *= 9.0 / 5
+	}
+	return Advice(feelsLike-offset, unit)
+}
+
+// DefaultComfortPath returns $XDG_CONFIG_HOME/weather/comfort.json, falling
+// back to ~/.config when XDG_CONFIG_HOME is unset.
+func DefaultComfortPath() string {
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "comfort.json"
+		}
+		dir = filepath.Join(home, ".config")
+	}
+	return filepath.Join(dir, "weather", "comfort.json")
+}
+
+// LoadComfortProfile reads a profile. A missing file is the average profile.
+func LoadComfortProfile(path string) (*ComfortProfile, error) {
+	p := &ComfortProfile{}
+	b, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return p, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("comfort profile: %w", err)
+	}
+	if err := json.Unmarshal(b, p); err != nil {
+		return nil, fmt.Errorf("comfort profile %s: %w", path, err)
+	}
+	return p, nil
+}
+
+// Save writes the profile to path, creating its directory.
+func (p *ComfortProfile) Save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("comfort profile: %w", err)
+	}
+	b, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, b, 0o644)
+}
+
+// Encode packs the profile into a cookie-safe string.
+func (p *ComfortProfile) Encode() string {
+	b, _ := json.Marshal(p)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// DecodeComfortProfile is the inverse of Encode.
+func DecodeComfortProfile(s string) (*ComfortProfile, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("comfort profile: %w", err)
+	}
+	p := &ComfortProfile{}
+	if err := json.Unmarshal(b, p); err != nil {
+		return nil, fmt.Errorf("comfort profile: %w", err)
+	}
+	p.OffsetC = math.Max(-maxComfortOffset, math.Min(maxComfortOffset, p.OffsetC))
+	return p, nil
+}
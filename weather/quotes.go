@@ -1,116 +1,238 @@
 package weather
 
 import (
-	"math/rand"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
 	"strings"
+	"time"
 )
 
-// Quote returns a funny weather quote matching the WMO code.
-func Quote(wmoCode int) string {
-	var pool []string
-	switch {
-	case wmoCode == 0:
-		pool = []string{
-			"Sun's out, bad decisions are out too.",
-			"Perfect weather to pretend you're a lizard on a rock.",
-			"The sky is blue. Your excuses are not.",
-			"Vitamin D loading... please wait.",
-			"It's so sunny even your shadow needs sunglasses.",
-		}
-	case wmoCode == 1:
-		pool = []string{
-			"Mostly clear — like your schedule should be.",
-			"A few clouds, just enough to keep the sky humble.",
-			"The sun is trying its best. Same energy.",
-		}
-	case wmoCode == 2:
-		pool = []string{
-			"Partly cloudy, fully indecisive.",
-			"The weather can't make up its mind. Neither can you. Perfect match.",
-			"Clouds auditioning for a role in your afternoon plans.",
-		}
-	case wmoCode == 3:
-		pool = []string{
-			"Overcast. Great day to feel dramatically misunderstood.",
-			"Zero sun, maximum brooding potential.",
-			"The sky is wearing a grey blanket. Take notes.",
-			"Overcast skies: nature's way of saying 'meh'.",
-		}
-	case wmoCode == 45 || wmoCode == 48:
-		pool = []string{
-			"Fog warning: if you can't see your problems, do they even exist?",
-			"It's foggy. Perfect alibi weather.",
-			"Visibility low. Mystery high.",
-			"Great day to dramatically disappear into the mist.",
-		}
-	case wmoCode >= 51 && wmoCode <= 55:
-		pool = []string{
-			"Drizzle. Nature's way of passive-aggressively watering your plans.",
-			"It's not raining, it's misting. Like a fancy spa you didn't ask for.",
-			"Light drizzle: too wet to ignore, too weak to respect.",
+// QuoteEntry is one line in a quote pack. An empty tag list matches anything.
+type QuoteEntry struct {
+	Text    string   `json:"text"`
+	Codes   []int    `json:"codes,omitempty"`   // WMO weather codes
+	Times   []string `json:"times,omitempty"`   // "morning", "afternoon", "evening", "night"
+	Seasons []string `json:"seasons,omitempty"` // "spring", "summer", "autumn", "winter"
+	Locale  string   `json:"locale,omitempty"`  // "" = English source, translated by the caller
+}
+
+// QuotePack is a named set of quotes loaded from a JSON file. Locale is the
+// default for entries that don't set their own.
+type QuotePack struct {
+	Name   string       `json:"name"`
+	Locale string       `json:"locale,omitempty"`
+	Quotes []QuoteEntry `json:"quotes"`
+}
+
+// QuoteBook holds the enabled quote packs. A nil or empty book picks no
+// quote, which is how quotes are turned off.
+type QuoteBook struct {
+	Packs []*QuotePack
+}
+
+//go:embed quotes/*.json
+var quoteFS embed.FS
+
+// BuiltinQuotePacks returns the packs embedded in the binary, by name.
+func BuiltinQuotePacks() map[string]*QuotePack {
+	packs := map[string]*QuotePack{}
+	files, _ := fs.Glob(quoteFS, "quotes/*.json")
+	for _, f := range files {
+		b, _ := quoteFS.ReadFile(f)
+		p, err := parseQuotePack(b, strings.TrimSuffix(path.Base(f), ".json"))
+		if err != nil {
+			panic(err) // embedded packs are checked in
 		}
-	case wmoCode == 61:
-		pool = []string{
-			"Slight rain. A solid excuse not to go jogging.",
-			"Rain check? The sky literally issued one.",
-			"Nature is crying. Relatable.",
+		packs[p.Name] = p
+	}
+	return packs
+}
+
+// LoadQuotePack reads a pack from a JSON file. The name defaults to the file
+// name without its extension.
+func LoadQuotePack(file string) (*QuotePack, error) {
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("quote pack: %w", err)
+	}
+	return parseQuotePack(b, strings.TrimSuffix(filepath.Base(file), filepath.Ext(file)))
+}
+
+func parseQuotePack(b []byte, name string) (*QuotePack, error) {
+	p := &QuotePack{}
+	if err := json.Unmarshal(b, p); err != nil {
+		return nil, fmt.Errorf("quote pack %s: %w", name, err)
+	}
+	if p.Name == "" {
+		p.Name = name
+	}
+	for i, q := range p.Quotes {
+		if q.Text == "" {
+			return nil, fmt.Errorf("quote pack %s: quote %d has no text", p.Name, i+1)
 		}
-	case wmoCode == 63:
-		pool = []string{
-			"Moderate rain. Your hair has accepted its fate.",
-			"It's raining. Cancel everything and make soup.",
-			"Rain: nature's way of doing your car wash for free.",
+		for _, t := range q.Times {
+			if !slices.Contains(quoteTimes, t) {
+				return nil, fmt.Errorf("quote pack %s: unknown time %q (want %s)", p.Name, t, strings.Join(quoteTimes, ", "))
+			}
 		}
-	case wmoCode == 65:
-		pool = []string{
-			"Heavy rain. You ARE the soup now.",
-			"It's pouring. Even the ducks are impressed.",
-			"Biblical rain detected. Start building something.",
-			"Congratulations, you're basically underwater.",
+		for _, s := range q.Seasons {
+			if !slices.Contains(quoteSeasons, s) {
+				return nil, fmt.Errorf("quote pack %s: unknown season %q (want %s)", p.Name, s, strings.Join(quoteSeasons, ", "))
+			}
 		}
-	case wmoCode >= 71 && wmoCode <= 75:
-		pool = []string{
-			"Snow! Nature said 'let me delete everything and start fresh'.",
-			"It's snowing. Time to question every life choice that led you here.",
-			"Snow: beautiful from inside. Terrible from outside.",
-			"White stuff everywhere. And it's not sugar.",
+		if q.Locale == "" {
+			p.Quotes[i].Locale = p.Locale
 		}
-	case wmoCode == 77:
-		pool = []string{
-			"Snow grains. Tiny frozen disappointments falling from the sky.",
-			"Snow grains: the economy-sized version of hail.",
+	}
+	return p, nil
+}
+
+// LoadQuotes builds a book from a comma-separated list of built-in pack
+// names and pack files or directories of them. "" enables every built-in
+// pack and "off" none.
+func LoadQuotes(spec string) (*QuoteBook, error) {
+	builtin := BuiltinQuotePacks()
+	book := &QuoteBook{}
+	switch strings.TrimSpace(spec) {
+	case "off":
+		return book, nil
+	case "":
+		for _, name := range sortedKeys(builtin) {
+			book.Packs = append(book.Packs, builtin[name])
 		}
-	case wmoCode >= 80 && wmoCode <= 82:
-		pool = []string{
-			"Rain showers incoming. The sky has commitment issues.",
-			"On-and-off rain. Like a bad situationship.",
-			"Showers: enough rain to ruin your day, not enough to cancel plans.",
+		return book, nil
+	}
+	for _, item := range strings.Split(spec, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
 		}
-	case wmoCode >= 85 && wmoCode <= 86:
-		pool = []string{
-			"Snow showers. Nature's confetti, but colder.",
-			"It's snowing intermittently, like inspiration.",
+		if p, ok := builtin[item]; ok {
+			book.Packs = append(book.Packs, p)
+			continue
 		}
-	case wmoCode == 95:
-		pool = []string{
-			"Thunderstorm. Nature is having a moment.",
-			"Lightning detected. Unplug your WiFi router and panic.",
-			"Thor is upset about something. As usual.",
-			"Great day to feel small and insignificant. Nature's doing the work.",
+		files := []string{item}
+		if st, err := os.Stat(item); err == nil && st.IsDir() {
+			files, _ = filepath.Glob(filepath.Join(item, "*.json"))
+		} else if err != nil && !strings.ContainsAny(item, `/\.`) {
+			return nil, fmt.Errorf("unknown quote pack %q (built in: %s)", item, strings.Join(sortedKeys(builtin), ", "))
 		}
-	case wmoCode == 96 || wmoCode == 99:
-		pool = []string{
-			"Thunderstorm with hail. Nature said 'not today'.",
-			"Hail + lightning. Your car's worst nightmare.",
-			"The sky is literally throwing rocks at you. Take the hint and stay inside.",
+		for _, f := range files {
+			p, err := LoadQuotePack(f)
+			if err != nil {
+				return nil, err
+			}
+			book.Packs = append(book.Packs, p)
 		}
+	}
+	return book, nil
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// QuoteContext is what a quote is picked for. The same context always picks
+// the same quote.
+type QuoteContext struct {
+	Code     int       // WMO weather code
+	Time     time.Time // local time at the place
+	Lat, Lon float64   // seed the pick; Lat also gives the hemisphere's season
+	Lang     string
+}
+
+var (
+	quoteTimes   = []string{"morning", "afternoon", "evening", "night"}
+	quoteSeasons = []string{"spring", "summer", "autumn", "winter"}
+)
+
+// timeOfDay buckets a local hour: morning 05–12, afternoon 12–17, evening
+// 17–21, night otherwise.
+func timeOfDay(t time.Time) string {
+	switch h := t.Hour(); {
+	case h >= 5 && h < 12:
+		return "morning"
+	case h >= 12 && h < 17:
+		return "afternoon"
+	case h >= 17 && h < 21:
+		return "evening"
 	default:
-		pool = []string{
-			"Weather: it exists. Outside: also exists. You: reading this.",
-			"Conditions unknown. Like your weekend plans.",
+		return "night"
+	}
+}
+
+// season returns the meteorological season (Dec–Feb winter in the north),
+// flipped south of the equator.
+func season(t time.Time, lat float64) string {
+	i := int(t.Month()) % 12 / 3 // 0 = Dec–Feb
+	if lat < 0 {
+		i = (i + 2) % 4
+	}
+	return []string{"winter", "spring", "summer", "autumn"}[i]
+}
+
+// Pick returns a quote for ctx, or "" when no pack has one. Quotes for the
+// weather code are preferred; quotes without codes are the fallback. The
+// choice is seeded by place, hour and code, so a forecast keeps its quote
+// until the next hour.
+func (b *QuoteBook) Pick(ctx QuoteContext) string {
+	if b == nil {
+		return ""
+	}
+	tod, sea := timeOfDay(ctx.Time), season(ctx.Time, ctx.Lat)
+	var specific, general []string
+	for _, p := range b.Packs {
+		for _, q := range p.Quotes {
+			if q.Locale != "" && q.Locale != ctx.Lang ||
+				len(q.Times) > 0 && !slices.Contains(q.Times, tod) ||
+				len(q.Seasons) > 0 && !slices.Contains(q.Seasons, sea) {
+				continue
+			}
+			switch {
+			case len(q.Codes) == 0:
+				general = append(general, q.Text)
+			case slices.Contains(q.Codes, ctx.Code):
+				specific = append(specific, q.Text)
+			}
 		}
 	}
-	return pool[rand.Intn(len(pool))]
+	pool := specific
+	if len(pool) == 0 {
+		pool = general
+	}
+	if len(pool) == 0 {
+		return ""
+	}
+	h := fnv.New64a()
+	fmt.Fprintf(h, "%.2f,%.2f|%s|%d", ctx.Lat, ctx.Lon, ctx.Time.Format("2006-01-02T15"), ctx.Code)
+	return pool[h.Sum64()%uint64(len(pool))]
+}
+
+// For picks the quote for the current conditions in info.
+func (b *QuoteBook) For(info *WeatherInfo) string {
+	t, err := time.Parse("2006-01-02T15:04", info.Current.Time)
+	if err != nil {
+		t = time.Now()
+	}
+	return b.Pick(QuoteContext{
+		Code: info.Current.Code,
+		Time: t,
+		Lat:  info.Latitude,
+		Lon:  info.Longitude,
+		Lang: info.Lang,
+	})
 }
 
 // Advice returns a feels-like temperature advice string.
@@ -148,40 +270,3 @@ func Advice(feelsLike float64, unit string) string {
 		return "It's basically an oven outside. Stay in. Order food."
 	}
 }
-
-// QuoteFromIcon picks a funny quote by mapping the weather-icons CSS class back
-// to a condition bucket, so the web server doesn't need to pass raw WMO codes.
-func QuoteFromIcon(iconClass string) string {
-	switch {
-	case strings.Contains(iconClass, "sunny") && !strings.Contains(iconClass, "overcast"):
-		return Quote(0)
-	case strings.Contains(iconClass, "sunny-overcast"):
-		return Quote(1)
-	case strings.Contains(iconClass, "cloudy") && strings.Contains(iconClass, "day"):
-		return Quote(2)
-	case strings.Contains(iconClass, "cloudy"):
-		return Quote(3)
-	case strings.Contains(iconClass, "fog"):
-		return Quote(45)
-	case strings.Contains(iconClass, "sprinkle") || strings.Contains(iconClass, "rain-mix"):
-		return Quote(51)
-	case strings.Contains(iconClass, "rain-wind"):
-		return Quote(65)
-	case strings.Contains(iconClass, "rain"):
-		return Quote(63)
-	case strings.Contains(iconClass, "snow-wind"):
-		return Quote(75)
-	case strings.Contains(iconClass, "snowflake"):
-		return Quote(77)
-	case strings.Contains(iconClass, "snow"):
-		return Quote(71)
-	case strings.Contains(iconClass, "showers"):
-		return Quote(80)
-	case strings.Contains(iconClass, "storm-showers"):
-		return Quote(99)
-	case strings.Contains(iconClass, "thunderstorm"):
-		return Quote(95)
-	default:
-		return Quote(-1)
-	}
-}
@@ -0,0 +1,179 @@
+package weather
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+var testPack = &QuotePack{Name: "test", Quotes: []QuoteEntry{
+	{Text: "one"}, {Text: "two"}, {Text: "three"}, {Text: "four"}, {Text: "five"},
+	{Text: "rain", Codes: []int{61, 63}},
+	{Text: "rainy night", Codes: []int{61}, Times: []string{"night"}},
+	{Text: "winter", Seasons: []string{"winter"}, Codes: []int{71}},
+	{Text: "Regen", Codes: []int{61}, Locale: "de"},
+}}
+
+func TestQuotePickStable(t *testing.T) {
+	book := &QuoteBook{Packs: []*QuotePack{testPack}}
+	ctx := QuoteContext{Code: 0, Time: time.Date(2026, 5, 4, 9, 10, 0, 0, time.UTC), Lat: 51.51, Lon: -0.13, Lang: "en"}
+
+	got := book.Pick(ctx)
+	if got != "two" {
+		t.Errorf("Pick = %q, want %q", got, "two")
+	}
+	later := ctx
+	later.Time = ctx.Time.Add(45 * time.Minute)
+	if again := book.Pick(later); again != got {
+		t.Errorf("Pick later in the hour = %q, want %q again", again, got)
+	}
+
+	// The pick is spread over the pool as the hour changes.
+	seen := map[string]bool{}
+	for h := range 24 {
+		ctx.Time = time.Date(2026, 5, 4, h, 0, 0, 0, time.UTC)
+		seen[book.Pick(ctx)] = true
+	}
+	if len(seen) < 3 {
+		t.Errorf("24 hours picked only %v", seen)
+	}
+}
+
+func TestQuotePickMatching(t *testing.T) {
+	book := &QuoteBook{Packs: []*QuotePack{testPack}}
+	day := time.Date(2026, 5, 4, 14, 0, 0, 0, time.UTC)
+	night := time.Date(2026, 5, 4, 23, 0, 0, 0, time.UTC)
+	january := time.Date(2026, 1, 15, 14, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name string
+		ctx  QuoteContext
+		want []string // any of these
+	}{
+		{"code preferred", QuoteContext{Code: 63, Time: day, Lang: "en"}, []string{"rain"}},
+		{"time tag", QuoteContext{Code: 61, Time: night, Lang: "en"}, []string{"rain", "rainy night"}},
+		{"time tag excluded by day", QuoteContext{Code: 61, Time: day, Lang: "en"}, []string{"rain"}},
+		{"locale", QuoteContext{Code: 61, Time: day, Lang: "de"}, []string{"rain", "Regen"}},
+		{"season north", QuoteContext{Code: 71, Time: january, Lat: 50, Lang: "en"}, []string{"winter"}},
+		{"season south falls back", QuoteContext{Code: 71, Time: january, Lat: -34, Lang: "en"}, []string{"one", "two", "three", "four", "five"}},
+	}
+	for _, tt := range tests {
+		got := book.Pick(tt.ctx)
+		ok := false
+		for _, w := range tt.want {
+			ok = ok || got == w
+		}
+		if !ok {
+			t.Errorf("%s: Pick = %q, want one of %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSeasonHemisphere(t *testing.T) {
+	tests := []struct {
+		month        time.Month
+		north, south string
+	}{
+		{time.January, "winter", "summer"},
+		{time.March, "spring", "autumn"},
+		{time.July, "summer", "winter"},
+		{time.October, "autumn", "spring"},
+		{time.December, "winter", "summer"},
+	}
+	for _, tt := range tests {
+		day := time.Date(2026, tt.month, 10, 12, 0, 0, 0, time.UTC)
+		if got := season(day, 51.5); got != tt.north {
+			t.Errorf("%s north: season = %q, want %q", tt.month, got, tt.north)
+		}
+		if got := season(day, -33.9); got != tt.south {
+			t.Errorf("%s south: season = %q, want %q", tt.month, got, tt.south)
+		}
+	}
+}
+
+func TestTimeOfDay(t *testing.T) {
+	for h, want := range map[int]string{
+		0: "night", 4: "night", 5: "morning", 11: "morning", 12: "afternoon",
+		16: "afternoon", 17: "evening", 20: "evening", 21: "night", 23: "night",
+	} {
+		if got := timeOfDay(time.Date(2026, 5, 4, h, 30, 0, 0, time.UTC)); got != want {
+			t.Errorf("%02d:30: timeOfDay = %q, want %q", h, got, want)
+		}
+	}
+}
+
+func TestLoadQuotes(t *testing.T) {
+	all, err := LoadQuotes("")
+	if err != nil || len(all.Packs) == 0 {
+		t.Fatalf("LoadQuotes(\"\") = %v packs, %v; want the built-in packs", all, err)
+	}
+	classic, err := LoadQuotes("classic")
+	if err != nil || len(classic.Packs) != 1 || classic.Packs[0].Name != "classic" {
+		t.Errorf("LoadQuotes(classic) = %+v, %v", classic, err)
+	}
+
+	off, err := LoadQuotes("off")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := off.Pick(QuoteContext{Time: time.Now()}); got != "" {
+		t.Errorf("off: Pick = %q, want none", got)
+	}
+	if got := (*QuoteBook)(nil).Pick(QuoteContext{Time: time.Now()}); got != "" {
+		t.Errorf("nil book: Pick = %q, want none", got)
+	}
+
+	if _, err := LoadQuotes("classic,nosuchpack"); err == nil || !strings.Contains(err.Error(), `"nosuchpack"`) {
+		t.Errorf("unknown pack: err = %v", err)
+	}
+	if _, err := LoadQuotes(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("missing file: no error")
+	}
+}
+
+func TestLoadQuotesFiles(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, body string) string {
+		t.Helper()
+		f := filepath.Join(dir, name)
+		if err := os.WriteFile(f, []byte(body), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return f
+	}
+	write("office.json", `{"locale": "de", "quotes": [{"text": "Büro"}, {"text": "Office", "locale": "en"}]}`)
+	write("night.json", `{"name": "owls", "quotes": [{"text": "Hoot", "times": ["night"]}]}`)
+
+	book, err := LoadQuotes(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	names := map[string]*QuotePack{}
+	for _, p := range book.Packs {
+		names[p.Name] = p
+	}
+	if names["office"] == nil || names["owls"] == nil {
+		t.Fatalf("packs = %v, want office (from the file name) and owls", names)
+	}
+	if got := names["office"].Quotes; got[0].Locale != "de" || got[1].Locale != "en" {
+		t.Errorf("office locales = %q, %q; want the pack's de and the entry's own en", got[0].Locale, got[1].Locale)
+	}
+}
+
+func TestQuotePackInvalid(t *testing.T) {
+	tests := []struct {
+		name, body, want string
+	}{
+		{"bad time", `{"quotes": [{"text": "x", "times": ["noon"]}]}`, `unknown time "noon"`},
+		{"bad season", `{"quotes": [{"text": "x", "seasons": ["monsoon"]}]}`, `unknown season "monsoon"`},
+		{"no text", `{"quotes": [{"codes": [0]}]}`, "quote 1 has no text"},
+		{"not json", `quotes: []`, "quote pack bad"},
+	}
+	for _, tt := range tests {
+		_, err := parseQuotePack([]byte(tt.body), "bad")
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: err = %v, want %q", tt.name, err, tt.want)
+		}
+	}
+}
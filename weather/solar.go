@@ -0,0 +1,275 @@
+package weather
+
+import (
+	"fmt"
+	"math"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"WeatherApp/astro"
+)
+
+// PV model constants: an isotropic sky with grass-like ground reflection,
+// and crystalline silicon cells losing 0.4 % per °C above 25 °C, heated by
+// the sun as in the NOCT test (45 °C at 800 W/m² and 20 °C air).
+const (
+	groundAlbedo = 0.2
+	tempCoeff    = -0.004
+	noctC        = 45.0
+	maxDNI       = 1100.0 // W/m²; caps beam irradiance recovered near the horizon
+)
+
+// MaxSolarDays is the longest PV forecast; the forecast API ends at 16 days.
+const MaxSolarDays = 16
+
+// SolarSystem describes a rooftop PV array.
+type SolarSystem struct {
+	KWp     float64 `json:"kwp"`     // rated DC power at 1000 W/m² and 25 °C
+	Tilt    float64 `json:"tilt"`    // degrees from horizontal
+	Azimuth float64 `json:"azimuth"` // degrees clockwise from north the panels face; negative faces the equator
+	Losses  float64 `json:"losses"`  // wiring, inverter, soiling and mismatch, %
+}
+
+// DefaultSolarSystem is a typical 4 kWp roof facing the equator.
+var DefaultSolarSystem = SolarSystem{KWp: 4, Tilt: 35, Azimuth: -1, Losses: 14}
+
+// ParseSolarSystem reads "kwp:tilt:azimuth:losses", e.g. "4.2:30:160:14".
+// Empty or missing parts keep DefaultSolarSystem's.
+func ParseSolarSystem(spec string) (SolarSystem, error) {
+	sys := DefaultSolarSystem
+	fields := []*float64{&sys.KWp, &sys.Tilt, &sys.Azimuth, &sys.Losses}
+	parts := strings.Split(strings.TrimSpace(spec), ":")
+	if len(parts) > len(fields) {
+		return sys, fmt.Errorf("solar system %q: want kwp:tilt:azimuth:losses", spec)
+	}
+	for i, p := range parts {
+		if p = strings.TrimSpace(p); p == "" {
+			continue
+		}
+		v, err := strconv.ParseFloat(p, 64)
+		if err != nil {
+			return sys, fmt.Errorf("solar system %q: bad number %q", spec, p)
+		}
+		*fields[i] = v
+	}
+	return sys, sys.Validate()
+}
+
+// Validate checks the system's numbers are in range.
+func (s SolarSystem) Validate() error {
+	switch {
+	case s.KWp <= 0 || s.KWp > 1000:
+		return fmt.Errorf("system size %g kWp is out of range (0–1000)", s.KWp)
+	case s.Tilt < 0 || s.Tilt > 90:
+		return fmt.Errorf("tilt %g° is out of range (0–90)", s.Tilt)
+	case s.Azimuth >= 360:
+		return fmt.Errorf("azimuth %g° is out of range (0–359)", s.Azimuth)
+	case s.Losses < 0 || s.Losses >= 100:
+		return fmt.Errorf("losses %g%% are out of range (0–99)", s.Losses)
+	}
+	return nil
+}
+
+// String formats the system the way ParseSolarSystem reads it.
+func (s SolarSystem) String() string {
+	return strconv.FormatFloat(s.KWp, 'f', -1, 64) + ":" + strconv.FormatFloat(s.Tilt, 'f', -1, 64) + ":" +
+		strconv.FormatFloat(s.Azimuth, 'f', -1, 64) + ":" + strconv.FormatFloat(s.Losses, 'f', -1, 64)
+}
+
+// Facing describes the panels' direction, e.g. "S (180°)".
+func (s SolarSystem) Facing() string {
+	if s.Azimuth < 0 {
+		return "the equator"
+	}
+	return fmt.Sprintf("%s (%.0f°)", WindCompass(int(math.Round(s.Azimuth))), s.Azimuth)
+}
+
+// SolarHour is one hour of estimated generation.
+type SolarHour struct {
+	Time  string  `json:"time"`        // "2006-01-02T15:04", the start of the hour
+	GHI   float64 `json:"ghi_wm2"`     // global horizontal irradiance, mean over the hour
+	POA   float64 `json:"poa_wm2"`     // irradiance on the panels
+	CellC float64 `json:"cell_temp_c"` // estimated cell temperature
+	KWh   float64 `json:"kwh"`         // energy over the hour, equal to the mean kW
+}
+
+// SolarDay sums one day's generation.
+type SolarDay struct {
+	Date   string      `json:"date"`  // "2006-01-02"
+	Label  string      `json:"label"` // "Today", "Tomorrow", "Mon 19"
+	KWh    float64     `json:"kwh"`
+	PeakKW float64     `json:"peak_kw"`
+	PeakAt string      `json:"peak_at"` // "15:04", start of the best hour
+	Hours  []SolarHour `json:"hours"`   // 24 hours from midnight
+}
+
+// SolarForecast is the PV generation forecast for one system.
+type SolarForecast struct {
+	City      string      `json:"city"`
+	Country   string      `json:"country"`
+	Latitude  float64     `json:"latitude"`
+	Longitude float64     `json:"longitude"`
+	Timezone  string      `json:"timezone"`
+	System    SolarSystem `json:"system"` // as modelled, with the azimuth resolved
+	Now       string      `json:"now"`    // "2006-01-02T15:04", local time of the forecast
+	NowKW     float64     `json:"now_kw"` // the current hour's estimate
+	TotalKWh  float64     `json:"total_kwh"`
+	Days      []SolarDay  `json:"days"`
+}
+
+// Next24 returns the hours from the current one onwards, up to 24.
+func (f *SolarForecast) Next24() []SolarHour {
+	var out []SolarHour
+	for _, d := range f.Days {
+		for _, h := range d.Hours {
+			if h.Time[:13] >= f.Now[:13] && len(out) < 24 {
+				out = append(out, h)
+			}
+		}
+	}
+	return out
+}
+
+// PeakKW is the highest hourly output in the forecast, at least 0.1 kW so
+// it can scale a chart.
+func (f *SolarForecast) PeakKW() float64 {
+	top := 0.1
+	for _, d := range f.Days {
+		top = math.Max(top, d.PeakKW)
+	}
+	return top
+}
+
+type solarSeries struct {
+	Hourly struct {
+		Time    []string  `json:"time"`
+		Temp    []float64 `json:"temperature_2m"`
+		GHI     []float64 `json:"shortwave_radiation"`
+		Direct  []float64 `json:"direct_radiation"`
+		Diffuse []float64 `json:"diffuse_radiation"`
+	} `json:"hourly"`
+}
+
+// Solar forecasts sys's hourly and daily output for city over days days
+// (1–15, one short of the forecast range; 0 means a week).
+func (c *Client) Solar(city string, sys SolarSystem, days int) (*SolarForecast, error) {
+	if err := sys.Validate(); err != nil {
+		return nil, err
+	}
+	if days <= 0 {
+		days = 7
+	}
+	days = min(days, MaxSolarDays-1)
+	loc, err := c.Geocode(city)
+	if err != nil {
+		return nil, err
+	}
+	u := fmt.Sprintf("%s?latitude=%.4f&longitude=%.4f"+
+		"&hourly=temperature_2m,shortwave_radiation,direct_radiation,diffuse_radiation"+
+		"&forecast_days=%d&timezone=%s",
+		forecastURL, loc.Latitude, loc.Longitude, days+1, url.QueryEscape(loc.Timezone))
+	var raw solarSeries
+	if err := c.getJSON(u, &raw); err != nil {
+		return nil, fmt.Errorf("solar: %w", err)
+	}
+	tz, err := time.LoadLocation(loc.Timezone)
+	if err != nil {
+		tz = time.UTC
+	}
+	f := buildSolar(&raw, sys, loc.Latitude, loc.Longitude, tz, time.Now().In(tz), days)
+	f.City, f.Country, f.Timezone = loc.Name, loc.Country, loc.Timezone
+	return f, nil
+}
+
+// buildSolar models each hour. Open-Meteo's radiation is the mean over the
+// hour before its timestamp, so the value at 10:00 is generation from 09:00
+// and the sun is placed at 09:30. One extra forecast day supplies the last
+// day's final hour.
+func buildSolar(raw *solarSeries, sys SolarSystem, lat, lon float64, tz *time.Location, now time.Time, days int) *SolarForecast {
+	const layout = "2006-01-02T15:04"
+	if sys.Azimuth < 0 {
+		sys.Azimuth = 180
+		if lat < 0 {
+			sys.Azimuth = 0
+		}
+	}
+	f := &SolarForecast{
+		Latitude: lat, Longitude: lon, System: sys,
+		Now:  now.Format(layout),
+		Days: make([]SolarDay, 0, days), // byDay points into it
+	}
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, tz)
+	byDay := make(map[string]*SolarDay, days)
+	for d := 0; d < days; d++ {
+		day := today.AddDate(0, 0, d)
+		sd := SolarDay{Date: day.Format("2006-01-02"), Label: day.Format("Mon 2")}
+		switch d {
+		case 0:
+			sd.Label = "Today"
+		case 1:
+			sd.Label = "Tomorrow"
+		}
+		f.Days = append(f.Days, sd)
+		byDay[sd.Date] = &f.Days[len(f.Days)-1]
+	}
+
+	h := raw.Hourly
+	for i, ts := range h.Time {
+		end, err := time.ParseInLocation(layout, ts, tz)
+		if err != nil {
+			continue
+		}
+		start := end.Add(-time.Hour)
+		day, ok := byDay[start.Format("2006-01-02")]
+		if !ok {
+			continue
+		}
+		sh := solarHour(sys, lat, lon, start,
+			safeFloat(h.GHI, i), safeFloat(h.Direct, i), safeFloat(h.Diffuse, i), safeFloat(h.Temp, i))
+		sh.Time = start.Format(layout)
+		day.Hours = append(day.Hours, sh)
+		day.KWh += sh.KWh
+		if sh.KWh > day.PeakKW {
+			day.PeakKW, day.PeakAt = sh.KWh, start.Format("15:04")
+		}
+		if sh.Time[:13] == f.Now[:13] {
+			f.NowKW = sh.KWh
+		}
+	}
+	for i := range f.Days {
+		f.Days[i].KWh = math.Round(f.Days[i].KWh*100) / 100
+		f.TotalKWh += f.Days[i].KWh
+	}
+	f.TotalKWh = math.Round(f.TotalKWh*100) / 100
+	return f
+}
+
+// solarHour turns one hour's horizontal irradiance into output. The beam
+// is recovered from direct horizontal irradiance and projected onto the
+// panels; diffuse light comes from the visible part of an even sky, plus
+// ground reflection.
+func solarHour(sys SolarSystem, lat, lon float64, start time.Time, ghi, direct, diffuse, airC float64) SolarHour {
+	sun := astro.SunPosition(start.Add(30*time.Minute), lat, lon)
+	alt, az := sun.Altitude*math.Pi/180, sun.Azimuth*math.Pi/180
+	tilt, paz := sys.Tilt*math.Pi/180, sys.Azimuth*math.Pi/180
+
+	var beam float64
+	if sinAlt := math.Sin(alt); sinAlt > 0.01 {
+		dni := math.Min(direct/sinAlt, maxDNI)
+		cosAOI := sinAlt*math.Cos(tilt) + math.Cos(alt)*math.Sin(tilt)*math.Cos(az-paz)
+		beam = dni * math.Max(0, cosAOI)
+	}
+	poa := beam + diffuse*(1+math.Cos(tilt))/2 + ghi*groundAlbedo*(1-math.Cos(tilt))/2
+	poa = math.Max(0, poa)
+	cell := airC + poa/800*(noctC-20)
+	kw := sys.KWp * poa / 1000 * (1 + tempCoeff*(cell-25)) * (1 - sys.Losses/100)
+	kw = math.Max(0, math.Min(kw, sys.KWp))
+	return SolarHour{
+		GHI:   math.Round(ghi),
+		POA:   math.Round(poa),
+		CellC: math.Round(cell*10) / 10,
+		KWh:   math.Round(kw*1000) / 1000,
+	}
+}
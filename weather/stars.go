@@ -0,0 +1,230 @@
+package weather
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"WeatherApp/astro"
+)
+
+// StarHour is one night hour of the stargazing outlook.
+type StarHour struct {
+	Time     string // "HH:MM", the start of the hour
+	DateTime string
+	Score    int // 0–100
+	Cloud    int // total cloud cover, %
+	Humidity int // %
+	SunAlt   float64
+	MoonAlt  float64 // degrees; negative when the moon is down
+}
+
+// Dark reports whether the sun is low enough for astronomical darkness.
+func (h StarHour) Dark() bool { return h.SunAlt <= -18 }
+
+// StarNight is the outlook for one night, named after its evening.
+type StarNight struct {
+	Date      string     // evening date, "2006-01-02"
+	Label     string     // "Now" (the night in progress), "Tonight", "Mon 19"
+	Dark      astro.Span // astronomical dusk to dawn; zero when the sky never gets fully dark
+	MoonIllum float64    // 0–1 at midnight
+	Hours     []StarHour // remaining hours with the sun below -6°
+	Best      astro.Span // best observing window; zero when no hour rates Fair
+	Score     int        // mean over Best, or the best single hour when there is no window
+	Rating    string
+	Limit     string // what holds the night back, e.g. "Cloud", "Moon 80% lit"; "" when nothing does
+}
+
+// Stargazing scoring: cloud is the hard limit, then the moon, the sun below
+// the horizon and haze or dew from humid air.
+const (
+	starWindowMin = 40 // hours scoring at least this ("Fair") form windows
+	moonPenalty   = 0.6
+	humidFrom     = 70.0
+)
+
+// StarRating is the label for a stargazing score.
+func StarRating(score int) string {
+	switch {
+	case score >= 80:
+		return "Excellent"
+	case score >= 60:
+		return "Good"
+	case score >= 40:
+		return "Fair"
+	case score >= 20:
+		return "Poor"
+	}
+	return "Bad"
+}
+
+// starScore rates one hour. Each factor is 0–1: clear sky; the moon's glare
+// (its lit fraction, stronger the higher it stands); how far the sun is
+// below the horizon (1 in astronomical darkness, falling to 0 at the end of
+// civil twilight); and humidity above 70 %, which brings haze and dew.
+func starScore(h StarHour, illum float64) (score int, clear, moon, dark, humid float64) {
+	clear = 1 - float64(h.Cloud)/100
+	moon = 1.0
+	if h.MoonAlt > -0.5 {
+		moon = 1 - moonPenalty*illum*(0.5+0.5*math.Sin(math.Max(0, h.MoonAlt)*math.Pi/180))
+	}
+	dark = math.Max(0, math.Min(1, (-6-h.SunAlt)/12))
+	humid = 1 - 0.3*math.Max(0, float64(h.Humidity)-humidFrom)/(100-humidFrom)
+	return int(math.Round(100 * clear * moon * dark * humid)), clear, moon, dark, humid
+}
+
+// parseStargazing builds the outlook for every night the hourly forecast
+// covers through to the next morning, starting with the one in progress.
+func parseStargazing(h hourlyRaw, currentTimeStr string, lat, lon float64, timezone string) []StarNight {
+	const layout = "2006-01-02T15:04"
+	tz, err := time.LoadLocation(timezone)
+	if err != nil {
+		tz = time.UTC
+	}
+	now, err := time.ParseInLocation(layout, currentTimeStr, tz)
+	if err != nil {
+		return nil
+	}
+	index := make(map[string]int, len(h.Time))
+	for i, ts := range h.Time {
+		index[ts] = i
+	}
+
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, tz)
+	var nights []StarNight
+	for evening := today.AddDate(0, 0, -1); ; evening = evening.AddDate(0, 0, 1) {
+		noon := evening.Add(12 * time.Hour)
+		morning := noon.AddDate(0, 0, 1)
+		// A night is only rated once the forecast reaches the next morning.
+		if _, ok := index[morning.Add(-time.Hour).Format(layout)]; !ok {
+			if evening.After(today) {
+				break
+			}
+			continue
+		}
+		n := StarNight{
+			Date:      evening.Format("2006-01-02"),
+			Label:     evening.Format("Mon 2"),
+			MoonIllum: astro.MoonIllumination(morning.Add(-12 * time.Hour)).Fraction,
+		}
+		switch {
+		case evening.Before(today):
+			n.Label = "Now"
+		case evening.Equal(today):
+			n.Label = "Tonight"
+		}
+		dusk := astro.Sun(evening, lat, lon).AstroDusk
+		dawn := astro.Sun(evening.AddDate(0, 0, 1), lat, lon).AstroDawn
+		if !dusk.IsZero() && !dawn.IsZero() {
+			n.Dark = astro.Span{From: dusk, To: dawn}
+		}
+
+		var limits [4]float64 // shortfall per factor, summed over the night
+		for t := noon; t.Before(morning); t = t.Add(time.Hour) {
+			ts := t.Format(layout)
+			i, ok := index[ts]
+			if !ok || !t.Add(time.Hour).After(now) {
+				continue
+			}
+			mid := t.Add(30 * time.Minute)
+			sh := StarHour{
+				Time:     t.Format("15:04"),
+				DateTime: ts,
+				Cloud:    safeInt(h.CloudCover, i),
+				Humidity: safeInt(h.Humidity, i),
+				SunAlt:   astro.SunPosition(mid, lat, lon).Altitude,
+				MoonAlt:  astro.MoonPosition(mid, lat, lon).Altitude,
+			}
+			if sh.SunAlt > -6 {
+				continue
+			}
+			var f [4]float64
+			sh.Score, f[0], f[1], f[2], f[3] = starScore(sh, n.MoonIllum)
+			for k := range f {
+				limits[k] += 1 - f[k]
+			}
+			n.Hours = append(n.Hours, sh)
+		}
+		// Last night is only shown while it is still dark.
+		if len(n.Hours) == 0 && evening.Before(today) {
+			continue
+		}
+		n.Best, n.Score = bestStarWindow(n.Hours)
+		n.Rating = StarRating(n.Score)
+		n.Limit = starLimit(n, limits)
+		nights = append(nights, n)
+	}
+	return nights
+}
+
+// bestStarWindow finds the run of consecutive hours rating at least Fair
+// with the highest total score. Without one, the score is the best hour's.
+func bestStarWindow(hours []StarHour) (astro.Span, int) {
+	var best astro.Span
+	bestSum, bestLen, top := 0, 0, 0
+	for i := 0; i < len(hours); {
+		top = max(top, hours[i].Score)
+		if hours[i].Score < starWindowMin {
+			i++
+			continue
+		}
+		j, sum := i, 0
+		for ; j < len(hours) && hours[j].Score >= starWindowMin; j++ {
+			sum += hours[j].Score
+			top = max(top, hours[j].Score)
+		}
+		if sum > bestSum {
+			from, _ := time.Parse("2006-01-02T15:04", hours[i].DateTime)
+			to, _ := time.Parse("2006-01-02T15:04", hours[j-1].DateTime)
+			best, bestSum, bestLen = astro.Span{From: from, To: to.Add(time.Hour)}, sum, j-i
+		}
+		i = j
+	}
+	if bestLen == 0 {
+		return best, top
+	}
+	return best, int(math.Round(float64(bestSum) / float64(bestLen)))
+}
+
+// starLimit names the factor that costs the night the most. Dusk and dawn
+// twilight only count when the night never gets fully dark.
+func starLimit(n StarNight, shortfall [4]float64) string {
+	if len(n.Hours) == 0 {
+		return "No darkness"
+	}
+	if !n.Dark.IsZero() {
+		shortfall[2] = 0
+	}
+	k := 0
+	for i := range shortfall {
+		if shortfall[i] > shortfall[k] {
+			k = i
+		}
+	}
+	if shortfall[k] < 0.1*float64(len(n.Hours)) {
+		return ""
+	}
+	switch k {
+	case 0:
+		return "Cloud"
+	case 1:
+		return fmt.Sprintf("Moon %.0f%% lit", n.MoonIllum*100)
+	case 2:
+		if n.Dark.IsZero() {
+			return "No astronomical darkness"
+		}
+		return "Twilight"
+	}
+	return "Humid, dew likely"
+}
+
+// BestStarNight returns the index of the highest-scoring night, or -1.
+func BestStarNight(nights []StarNight) int {
+	best := -1
+	for i, n := range nights {
+		if best < 0 || n.Score > nights[best].Score {
+			best = i
+		}
+	}
+	return best
+}
@@ -1,6 +1,12 @@
 package weather
 
-import "fmt"
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
 
 // UVLevel returns a short label for a UV index value.
 func UVLevel(uv float64) string {
@@ -67,3 +73,258 @@ func UVBar(uv float64, width int) string {
 	}
 	return fmt.Sprintf("[%s] %.1f", bar, uv)
 }
+
+// SkinType is a Fitzpatrick skin phototype. MED is the minimal erythemal
+// dose: the erythemally weighted UV dose (J/m²) that just reddens the skin.
+type SkinType struct {
+	Type  int    // 1-6
+	Roman string // "I" … "VI"
+	Name  string
+	MED   float64
+}
+
+// SkinTypes are the six Fitzpatrick types, indexed by Type-1.
+var SkinTypes = []SkinType{
+	{1, "I", "Very fair, always burns", 200},
+	{2, "II", "Fair, usually burns", 250},
+	{3, "III", "Medium, sometimes burns", 300},
+	{4, "IV", "Olive, rarely burns", 450},
+	{5, "V", "Brown, very rarely burns", 600},
+	{6, "VI", "Dark brown, never burns", 1000},
+}
+
+// DefaultSkinType is type II, the most sun-sensitive common type, so the
+// default estimates err on the side of caution.
+var DefaultSkinType = SkinTypes[1]
+
+// ParseSkinType accepts "1"-"6" or "I"-"VI"; "" is DefaultSkinType.
+func ParseSkinType(s string) (SkinType, error) {
+	s = strings.ToUpper(strings.TrimSpace(s))
+	if s == "" {
+		return DefaultSkinType, nil
+	}
+	for _, st := range SkinTypes {
+		if s == st.Roman || s == strconv.Itoa(st.Type) {
+			return st, nil
+		}
+	}
+	return SkinType{}, fmt.Errorf("unknown skin type %q (want 1-6 or I-VI)", s)
+}
+
+// uvIndexWm2 is the erythemal irradiance of UV index 1 (25 mW/m²).
+const uvIndexWm2 = 0.025
+
+// TimeToBurn estimates unprotected-skin time to one MED at a steady UV
+// index, multiplied by the sunscreen's SPF (assumed applied as tested).
+// It returns 0 when the UV index is 0, meaning no burn risk.
+func (s SkinType) TimeToBurn(uv, spf float64) time.Duration {
+	if uv <= 0 {
+		return 0
+	}
+	spf = math.Max(spf, 1)
+	secs := s.MED / (uv * uvIndexWm2) * spf
+	return time.Duration(secs) * time.Second
+}
+
+// VitaminD estimates the time to make about 1000 IU of vitamin D with face,
+// hands and forearms (a quarter of the skin) bare and no sunscreen, from
+// the rule of thumb that one full-body MED yields about 10,000 IU. Below
+// UV 3 there is too little UVB to count on, and it returns 0.
+func (s SkinType) VitaminD(uv float64) time.Duration {
+	if uv < 3 {
+		return 0
+	}
+	const iu, fullBodyIU, exposed = 1000.0, 10000.0, 0.25
+	return time.Duration(float64(s.TimeToBurn(uv, 1)) * iu / (fullBodyIU * exposed))
+}
+
+// FormatExposure renders a duration as "25 min" or "2 h 10 min", and
+// anything over five hours as "5 h+".
+func FormatExposure(d time.Duration) string {
+	m := int(d.Round(time.Minute) / time.Minute)
+	switch {
+	case m >= 300:
+		return "5 h+"
+	case m >= 60:
+		if m%60 == 0 {
+			return fmt.Sprintf("%d h", m/60)
+		}
+		return fmt.Sprintf("%d h %d min", m/60, m%60)
+	case m < 1:
+		return "< 1 min"
+	}
+	return fmt.Sprintf("%d min", m)
+}
+
+// UVHour is one hour of the day's UV curve.
+type UVHour struct {
+	Time     string // "HH:MM"
+	DateTime string // "2006-01-02T15:04", local time
+	UV       float64
+	Daylight bool // any part of the hour is between sunrise and sunset
+	Past     bool // the hour has already ended
+}
+
+// parseUVCurve returns all 24 hours of today's UV forecast, or tomorrow's
+// once today's last daylight hour has passed.
+func parseUVCurve(h hourlyRaw, d dailyRaw, currentTimeStr string) []UVHour {
+	const layout = "2006-01-02T15:04"
+	now, err := time.Parse(layout, currentTimeStr)
+	if err != nil || len(currentTimeStr) < 10 {
+		return nil
+	}
+	for day := 0; day < 2 && day < len(d.Time); day++ {
+		date := d.Time[day]
+		var sunrise, sunset time.Time
+		if day < len(d.Sunrise) && day < len(d.Sunset) {
+			sunrise, _ = time.Parse(layout, d.Sunrise[day])
+			sunset, _ = time.Parse(layout, d.Sunset[day])
+		}
+		var hours []UVHour
+		ahead := false
+		for i, ts := range h.Time {
+			if !strings.HasPrefix(ts, date) {
+				continue
+			}
+			t, err := time.Parse(layout, ts)
+			if err != nil {
+				continue
+			}
+			end := t.Add(time.Hour)
+			uh := UVHour{
+				Time:     t.Format("15:04"),
+				DateTime: ts,
+				UV:       safeFloat(h.UVIndex, i),
+				Daylight: end.After(sunrise) && t.Before(sunset),
+				Past:     !end.After(now),
+			}
+			if uh.Daylight && !uh.Past {
+				ahead = true
+			}
+			hours = append(hours, uh)
+		}
+		if ahead || day == 1 {
+			return hours
+		}
+	}
+	return nil
+}
+
+// UVWindow is a run of daylight hours, e.g. the safe hours of the day.
+type UVWindow struct {
+	From, To string // "HH:MM"; To is the end of the last hour
+}
+
+// String is "07:00–10:00".
+func (w UVWindow) String() string { return w.From + "–" + w.To }
+
+// UVOptions personalise the UV exposure report.
+type UVOptions struct {
+	Skin      SkinType
+	SPF       float64 // 0 or 1 = no sunscreen
+	SafeBelow float64 // UV index under which an hour counts as safe; 0 = 3
+}
+
+// UVExposure is the day's UV curve read for one skin type and sunscreen.
+type UVExposure struct {
+	Skin      SkinType
+	SPF       float64
+	SafeBelow float64
+	Hours     []UVHour
+	Peak      UVHour     // highest UV hour
+	Safe      []UVWindow // daylight windows below SafeBelow
+	BurnNow   time.Duration
+	BurnPeak  time.Duration
+	VitaminD  time.Duration // at the peak hour; 0 when UV is too weak
+	Day       string        // "Today" or "Tomorrow"
+}
+
+// Report builds the UV exposure report for info, or nil without a UV curve.
+func (o UVOptions) Report(info *WeatherInfo) *UVExposure {
+	if len(info.UVCurve) == 0 {
+		return nil
+	}
+	if o.Skin.MED == 0 {
+		o.Skin = DefaultSkinType
+	}
+	if o.SafeBelow <= 0 {
+		o.SafeBelow = 3
+	}
+	x := &UVExposure{Skin: o.Skin, SPF: math.Max(o.SPF, 1), SafeBelow: o.SafeBelow, Hours: info.UVCurve, Day: "Today"}
+	if !strings.HasPrefix(info.Current.Time, info.UVCurve[0].DateTime[:10]) {
+		x.Day = "Tomorrow"
+	}
+
+	var open *UVWindow
+	for i, h := range x.Hours {
+		if h.UV > x.Peak.UV {
+			x.Peak = h
+		}
+		if h.Daylight && h.UV < x.SafeBelow {
+			if open == nil {
+				open = &UVWindow{From: h.Time}
+			}
+			open.To = hourEnd(h.Time)
+		}
+		if open != nil && (!h.Daylight || h.UV >= x.SafeBelow || i == len(x.Hours)-1) {
+			x.Safe = append(x.Safe, *open)
+			open = nil
+		}
+	}
+	// Whole hours overhang sunrise and sunset; trim the windows to them.
+	// Tomorrow's sun times are within a few minutes of today's. In a polar
+	// day or night there is no sunrise or sunset to trim to.
+	sun := info.Sun.Times
+	for i := range x.Safe {
+		if !sun.Sunrise.IsZero() {
+			if rise := sun.Sunrise.Format("15:04"); x.Safe[i].From < rise {
+				x.Safe[i].From = rise
+			}
+		}
+		if !sun.Sunset.IsZero() {
+			if set := sun.Sunset.Format("15:04"); x.Safe[i].To > set {
+				x.Safe[i].To = set
+			}
+		}
+	}
+	x.BurnNow = x.Skin.TimeToBurn(info.Current.UVIndex, x.SPF)
+	x.BurnPeak = x.Skin.TimeToBurn(x.Peak.UV, x.SPF)
+	x.VitaminD = x.Skin.VitaminD(x.Peak.UV)
+	return x
+}
+
+// Burn is the time to burn at h's UV for this report's skin and SPF.
+func (x *UVExposure) Burn(h UVHour) time.Duration {
+	return x.Skin.TimeToBurn(h.UV, x.SPF)
+}
+
+// Summary is a one-line reading, e.g. "Peak UV 7 at 13:00 — skin type II
+// burns in 24 min unprotected; UV under 3 07:20–10:00, 16:00–18:10."
+func (x *UVExposure) Summary() string {
+	if x.Peak.UV < 1 {
+		return "UV stays low all day — no burn risk."
+	}
+	protect := "unprotected"
+	if x.SPF > 1 {
+		protect = fmt.Sprintf("with SPF %.0f", x.SPF)
+	}
+	s := fmt.Sprintf("Peak UV %.0f at %s — skin type %s burns in %s %s",
+		x.Peak.UV, x.Peak.Time, x.Skin.Roman, FormatExposure(x.BurnPeak), protect)
+	if len(x.Safe) > 0 {
+		ws := make([]string, len(x.Safe))
+		for i, w := range x.Safe {
+			ws[i] = w.String()
+		}
+		s += fmt.Sprintf("; UV under %.0f %s", x.SafeBelow, strings.Join(ws, ", "))
+	}
+	return s + "."
+}
+
+// hourEnd returns the "HH:MM" one hour after t, wrapping at midnight.
+func hourEnd(t string) string {
+	p, err := time.Parse("15:04", t)
+	if err != nil {
+		return t
+	}
+	return p.Add(time.Hour).Format("15:04")
+}
@@ -0,0 +1,385 @@
+package weather
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"math"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// verifyLeads are the lead times, in hours, at which model forecasts are kept
+// for verification. Storing every hour would grow the file for little gain.
+var verifyLeads = []int{6, 12, 24, 48, 72, 96}
+
+const (
+	pendingRetention  = 5 * 24 * time.Hour  // unmatched forecasts older than their valid time
+	verifiedRetention = 30 * 24 * time.Hour // rolling window for skill scores
+	minSkillSamples   = 10                  // pairs needed before a model is weighted by skill
+)
+
+// forecastRecord is one model's temperature forecast awaiting an observation.
+type forecastRecord struct {
+	Key      string  `json:"key"`      // location key, see locationKey
+	Location string  `json:"location"` // display name, e.g. "London, GB"
+	Model    string  `json:"model"`
+	ValidAt  string  `json:"valid_at"` // local "2006-01-02T15:04"
+	Lead     int     `json:"lead_h"`
+	TempC    float64 `json:"temp_c"`
+}
+
+// verifiedRecord is a forecast paired with what was later observed.
+type verifiedRecord struct {
+	Key      string  `json:"key"`
+	Location string  `json:"location"`
+	Model    string  `json:"model"`
+	ValidAt  string  `json:"valid_at"`
+	Lead     int     `json:"lead_h"`
+	ErrC     float64 `json:"err_c"` // forecast − observed, °C
+	Recorded int64   `json:"recorded"`
+}
+
+type verifyData struct {
+	Pending  []forecastRecord `json:"pending"`
+	Verified []verifiedRecord `json:"verified"`
+}
+
+// SkillScore is a model's rolling temperature error at one location and lead time.
+type SkillScore struct {
+	Key      string  `json:"key"`      // location key, see locationKey
+	Location string  `json:"location"` // the newest display name for Key
+	Model    string  `json:"model"`
+	LeadH    int     `json:"lead_hours"`
+	Samples  int     `json:"samples"`
+	MAE      float64 `json:"mae_c"`  // mean absolute error, °C
+	Bias     float64 `json:"bias_c"` // mean (forecast − observed), °C; positive = too warm
+}
+
+// VerifyStore persists model forecasts and later observations to a JSON file
+// and derives rolling skill scores from them. It is safe for concurrent use.
+type VerifyStore struct {
+	path string
+	mu   sync.Mutex
+	data verifyData
+}
+
+// DefaultVerifyPath returns $XDG_DATA_HOME/weather/verification.json, falling
+// back to ~/.local/share when XDG_DATA_HOME is unset.
+func DefaultVerifyPath() string {
+	dir := os.Getenv("XDG_DATA_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "verification.json"
+		}
+		dir = filepath.Join(home, ".local", "share")
+	}
+	return filepath.Join(dir, "weather", "verification.json")
+}
+
+// OpenVerifyStore loads the store at path. A missing file is an empty store.
+func OpenVerifyStore(path string) (*VerifyStore, error) {
+	s := &VerifyStore{path: path}
+	b, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("verification store: %w", err)
+	}
+	if err := json.Unmarshal(b, &s.data); err != nil {
+		return nil, fmt.Errorf("verification store %s: %w", path, err)
+	}
+	return s, nil
+}
+
+// locationKey identifies a location by rounded coordinates so the same city
+// matches across spellings.
+func locationKey(lat, lon float64) string {
+	return fmt.Sprintf("%.2f,%.2f", lat, lon)
+}
+
+// HasDue reports whether any pending forecast for lat/lon is valid at or
+// before currentTime, i.e. whether observations are worth fetching.
+func (s *VerifyStore) HasDue(lat, lon float64, currentTime string) bool {
+	if s == nil {
+		return false
+	}
+	key := locationKey(lat, lon)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, p := range s.data.Pending {
+		// Same-layout timestamps compare correctly as strings.
+		if p.Key == key && p.ValidAt <= currentTime {
+			return true
+		}
+	}
+	return false
+}
+
+// fetchObserved returns the best-match analysis temperature for the past five
+// days, keyed by local "2006-01-02T15:04", as the stand-in for observations.
+func fetchObserved(lat, lon float64, timezone, tempUnit string) (map[string]float64, error) {
+	u := fmt.Sprintf(
+		"%s?latitude=%.4f&longitude=%.4f&hourly=temperature_2m"+
+			"&past_days=5&forecast_days=1&temperature_unit=%s&timezone=%s",
+		forecastURL, lat, lon, tempUnit, url.QueryEscape(timezone),
+	)
+	var resp struct {
+		Hourly modelHourlyRaw `json:"hourly"`
+	}
+	if err := consensusClient.getJSON(u, &resp); err != nil {
+		return nil, fmt.Errorf("observations: %w", err)
+	}
+	return seriesMap(resp.Hourly.Time, resp.Hourly.Temp), nil
+}
+
+// Record stores each model's forecasts from info.Consensus at the verification
+// lead times and verifies pending forecasts against observations. observed
+// holds past hourly temperatures in info's units (see fetchObserved) and may
+// be nil; info.Current always counts as the observation for the current hour.
+func (s *VerifyStore) Record(info *WeatherInfo, lat, lon float64, observed map[string]float64) error {
+	if s == nil || info.Consensus == nil {
+		return nil
+	}
+	const layout = "2006-01-02T15:04"
+	now, err := time.Parse(layout, info.Current.Time)
+	if err != nil {
+		return fmt.Errorf("verification: bad current time %q", info.Current.Time)
+	}
+	hour := now.Truncate(time.Hour)
+	key := locationKey(lat, lon)
+	location := info.CityName
+	if info.CountryCode != "" {
+		location += ", " + strings.ToUpper(info.CountryCode)
+	}
+	toC := func(t float64) float64 { return toCelsius(t, info.TempUnit) }
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// 1. Verify pending forecasts that have an observation. Only hours up to
+	// now count; the observed series also carries today's forecast hours.
+	obs := make(map[string]float64, len(observed)+1)
+	for t, v := range observed {
+		if t <= hour.Format(layout) {
+			obs[t] = toC(v)
+		}
+	}
+	obs[hour.Format(layout)] = toC(info.Current.Temp)
+	seen := make(map[string]bool)
+	for _, v := range s.data.Verified {
+		seen[v.Key+"|"+v.Model+"|"+v.ValidAt+"|"+fmt.Sprint(v.Lead)] = true
+	}
+	pending := s.data.Pending[:0]
+	for _, p := range s.data.Pending {
+		obsC, ok := obs[p.ValidAt]
+		if p.Key != key || !ok {
+			pending = append(pending, p)
+			continue
+		}
+		id := p.Key + "|" + p.Model + "|" + p.ValidAt + "|" + fmt.Sprint(p.Lead)
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		s.data.Verified = append(s.data.Verified, verifiedRecord{
+			Key: p.Key, Location: p.Location, Model: p.Model, ValidAt: p.ValidAt, Lead: p.Lead,
+			ErrC:     math.Round((p.TempC-obsC)*100) / 100,
+			Recorded: time.Now().Unix(),
+		})
+	}
+	s.data.Pending = pending
+
+	// 2. Queue new forecasts, one per model and lead for this issue hour.
+	queued := make(map[string]bool, len(s.data.Pending))
+	for _, p := range s.data.Pending {
+		queued[p.Key+"|"+p.Model+"|"+p.ValidAt+"|"+fmt.Sprint(p.Lead)] = true
+	}
+	for _, r := range info.Consensus.Models {
+		if !r.Available {
+			continue
+		}
+		for _, lead := range verifyLeads {
+			validAt := hour.Add(time.Duration(lead) * time.Hour).Format(layout)
+			t, ok := r.HourlyTemp[validAt]
+			if !ok {
+				continue
+			}
+			id := key + "|" + r.Model + "|" + validAt + "|" + fmt.Sprint(lead)
+			if queued[id] {
+				continue
+			}
+			queued[id] = true
+			s.data.Pending = append(s.data.Pending, forecastRecord{
+				Key: key, Location: location, Model: r.Model,
+				ValidAt: validAt, Lead: lead, TempC: math.Round(toC(t)*100) / 100,
+			})
+		}
+	}
+
+	s.prune(hour)
+	return s.save()
+}
+
+// prune drops pending forecasts that can no longer be verified and verified
+// pairs that have left the rolling window. Caller holds s.mu.
+func (s *VerifyStore) prune(now time.Time) {
+	const layout = "2006-01-02T15:04"
+	pending := s.data.Pending[:0]
+	for _, p := range s.data.Pending {
+		t, err := time.Parse(layout, p.ValidAt)
+		if err == nil && now.Sub(t) < pendingRetention {
+			pending = append(pending, p)
+		}
+	}
+	s.data.Pending = pending
+
+	cutoff := time.Now().Add(-verifiedRetention).Unix()
+	verified := s.data.Verified[:0]
+	for _, v := range s.data.Verified {
+		if v.Recorded >= cutoff {
+			verified = append(verified, v)
+		}
+	}
+	s.data.Verified = verified
+}
+
+// save writes the store atomically via a temp file. Caller holds s.mu.
+func (s *VerifyStore) save() error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return fmt.Errorf("verification store: %w", err)
+	}
+	b, err := json.Marshal(s.data)
+	if err != nil {
+		return err
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, b, 0o644); err != nil {
+		return fmt.Errorf("verification store: %w", err)
+	}
+	return os.Rename(tmp, s.path)
+}
+
+// Report returns rolling MAE and bias per location, model and lead time,
+// sorted by location, lead and then MAE. Locations are told apart by key, so
+// names in different languages ("München", "Munich") share one set of
+// scores under the newest name. A non-empty location filters by a
+// case-insensitive substring of any name the location was recorded under.
+func (s *VerifyStore) Report(location string) []SkillScore {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	type acc struct {
+		n         int
+		abs, bias float64
+	}
+	type id struct {
+		key, model string
+		lead       int
+	}
+	type name struct {
+		label    string
+		recorded int64
+	}
+	names := make(map[string]name)
+	matched := make(map[string]bool)
+	filter := strings.ToLower(location)
+	for _, v := range s.data.Verified {
+		if n, ok := names[v.Key]; !ok || v.Recorded >= n.recorded {
+			names[v.Key] = name{v.Location, v.Recorded}
+		}
+		if strings.Contains(strings.ToLower(v.Location), filter) {
+			matched[v.Key] = true
+		}
+	}
+
+	sums := make(map[id]*acc)
+	for _, v := range s.data.Verified {
+		if !matched[v.Key] {
+			continue
+		}
+		k := id{v.Key, v.Model, v.Lead}
+		a := sums[k]
+		if a == nil {
+			a = &acc{}
+			sums[k] = a
+		}
+		a.n++
+		a.abs += math.Abs(v.ErrC)
+		a.bias += v.ErrC
+	}
+
+	scores := make([]SkillScore, 0, len(sums))
+	for k, a := range sums {
+		scores = append(scores, SkillScore{
+			Key: k.key, Location: names[k.key].label, Model: k.model, LeadH: k.lead, Samples: a.n,
+			MAE:  round2(a.abs / float64(a.n)),
+			Bias: round2(a.bias / float64(a.n)),
+		})
+	}
+	sort.Slice(scores, func(i, j int) bool {
+		a, b := scores[i], scores[j]
+		if a.Location != b.Location {
+			return a.Location < b.Location
+		}
+		if a.Key != b.Key {
+			return a.Key < b.Key
+		}
+		if a.LeadH != b.LeadH {
+			return a.LeadH < b.LeadH
+		}
+		return a.MAE < b.MAE
+	})
+	return scores
+}
+
+// SkillWeights returns a weight per model at lat/lon from inverse MAE over
+// all lead times, normalised so the average model scores 1 and clamped to
+// [0.25, 2]. Models with fewer than minSkillSamples pairs are left out.
+func (s *VerifyStore) SkillWeights(lat, lon float64) map[string]float64 {
+	if s == nil {
+		return nil
+	}
+	key := locationKey(lat, lon)
+
+	s.mu.Lock()
+	abs := make(map[string]float64)
+	count := make(map[string]int)
+	for _, v := range s.data.Verified {
+		if v.Key == key {
+			abs[v.Model] += math.Abs(v.ErrC)
+			count[v.Model]++
+		}
+	}
+	s.mu.Unlock()
+
+	inv := make(map[string]float64)
+	var total float64
+	for m, n := range count {
+		if n < minSkillSamples {
+			continue
+		}
+		mae := math.Max(abs[m]/float64(n), 0.1) // guard against a lucky zero
+		inv[m] = 1 / mae
+		total += inv[m]
+	}
+	if len(inv) < 2 {
+		return nil
+	}
+	avg := total / float64(len(inv))
+	weights := make(map[string]float64, len(inv))
+	for m, v := range inv {
+		weights[m] = math.Max(0.25, math.Min(2, v/avg))
+	}
+	return weights
+}
+
+func round2(v float64) float64 { return math.Round(v*100) / 100 }